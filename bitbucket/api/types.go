@@ -0,0 +1,136 @@
+// Package api contains the Bitbucket Cloud REST API 2.0 resources downloaded
+// by the bitbucket Downloader
+package api
+
+import "time"
+
+// Link is a hypermedia link of a resource
+type Link struct {
+	Href string `json:"href"`
+}
+
+// Links contains the links of a resource, only the web page one is used
+type Links struct {
+	HTML Link `json:"html"`
+}
+
+// User represents https://developer.atlassian.com/cloud/bitbucket/rest/api-group-users/
+// Bitbucket Cloud users do not have a numeric ID, they are identified by
+// their UUID, and the nickname is used as login
+type User struct {
+	AccountID   string `json:"account_id"`   // user_account_id text,
+	DisplayName string `json:"display_name"` // user_name text,
+	Nickname    string `json:"nickname"`     // user_login text NOT NULL,
+	UUID        string `json:"uuid"`         // user_uuid text,
+}
+
+// Workspace represents https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/
+type Workspace struct {
+	CreatedOn time.Time `json:"created_on"` // created_at timestamptz,
+	Links     Links     `json:"links"`      // htmlurl text,
+	IsPrivate bool      `json:"is_private"` // private boolean,
+	Name      string    `json:"name"`       // name text,
+	Slug      string    `json:"slug"`       // slug text NOT NULL,
+	UUID      string    `json:"uuid"`       // uuid text,
+}
+
+// Branch is the branch of a repository
+type Branch struct {
+	Name string `json:"name"`
+}
+
+// Commit is the reference to a commit
+type Commit struct {
+	Hash string `json:"hash"`
+}
+
+// Repository represents https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/
+type Repository struct {
+	CreatedOn   time.Time `json:"created_on"`  // created_at timestamptz,
+	Description string    `json:"description"` // description text,
+	ForkPolicy  string    `json:"fork_policy"` // fork_policy text,
+	FullName    string    `json:"full_name"`   // full_name text,
+	HasIssues   bool      `json:"has_issues"`  // has_issues boolean,
+	HasWiki     bool      `json:"has_wiki"`    // has_wiki boolean,
+	Links       Links     `json:"links"`       // htmlurl text,
+	IsPrivate   bool      `json:"is_private"`  // private boolean,
+	Language    string    `json:"language"`    // language text,
+	Mainbranch  *Branch   `json:"mainbranch"`  // default_branch text,
+	Name        string    `json:"name"`        // name text,
+	Parent      *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"` // parent_full_name text,
+	Project *struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	} `json:"project"` // project_key text, project_name text,
+	Size      int64     `json:"size"`       // size bigint,
+	Slug      string    `json:"slug"`       // slug text NOT NULL,
+	UpdatedOn time.Time `json:"updated_on"` // updated_at timestamptz,
+	UUID      string    `json:"uuid"`       // uuid text,
+	Workspace struct {
+		Slug string `json:"slug"`
+	} `json:"workspace"` // workspace_slug text NOT NULL,
+}
+
+// Endpoint is the source or the destination of a pull request
+type Endpoint struct {
+	Branch     Branch  `json:"branch"`
+	Commit     *Commit `json:"commit"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// PullRequest represents https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/
+type PullRequest struct {
+	Author            User      `json:"author"`              // user_uuid text, user_login text NOT NULL,
+	ClosedBy          *User     `json:"closed_by"`           // closed_by_login text,
+	CloseSourceBranch bool      `json:"close_source_branch"` // close_source_branch boolean,
+	CommentCount      int       `json:"comment_count"`       // comments bigint,
+	CreatedOn         time.Time `json:"created_on"`          // created_at timestamptz,
+	Description       string    `json:"description"`         // body text,
+	Destination       Endpoint  `json:"destination"`         // base_ref text, base_sha text, base_repository_full_name text,
+	ID                int       `json:"id"`                  // id bigint NOT NULL,
+	Links             Links     `json:"links"`               // htmlurl text,
+	MergeCommit       *Commit   `json:"merge_commit"`        // merge_commit_sha text,
+	Reason            string    `json:"reason"`              // reason text,
+	Source            Endpoint  `json:"source"`              // head_ref text, head_sha text, head_repository_full_name text,
+	State             string    `json:"state"`               // state text,
+	TaskCount         int       `json:"task_count"`          // task_count bigint,
+	Title             string    `json:"title"`               // title text,
+	UpdatedOn         time.Time `json:"updated_on"`          // updated_at timestamptz,
+	// Participants are only part of the response of a single pull request
+	Participants []Participant `json:"participants"`
+}
+
+// Participant is a user that reviewed, approved or commented a pull request
+type Participant struct {
+	Approved       bool       `json:"approved"`        // approved boolean,
+	ParticipatedOn *time.Time `json:"participated_on"` // participated_at timestamptz,
+	Role           string     `json:"role"`            // role text,
+	State          *string    `json:"state"`           // state text,
+	User           User       `json:"user"`            // user_uuid text, user_login text NOT NULL,
+}
+
+// Comment represents a pull request comment
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-comments-get
+type Comment struct {
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"` // body text,
+	CreatedOn time.Time `json:"created_on"` // created_at timestamptz,
+	Deleted   bool      `json:"deleted"`    // deleted boolean,
+	ID        int       `json:"id"`         // id bigint,
+	Inline    *struct {
+		Path string `json:"path"`
+		From *int   `json:"from"`
+		To   *int   `json:"to"`
+	} `json:"inline"` // path text, position bigint,
+	Links  Links `json:"links"` // htmlurl text,
+	Parent *struct {
+		ID int `json:"id"`
+	} `json:"parent"` // in_reply_to bigint,
+	UpdatedOn time.Time `json:"updated_on"` // updated_at timestamptz,
+	User      User      `json:"user"`       // user_uuid text, user_login text NOT NULL,
+}
@@ -0,0 +1,333 @@
+// Package bitbucket downloads the metadata of Bitbucket Cloud workspaces and
+// repositories using the REST API 2.0, and stores it with the same model as
+// the github package
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/src-d/metadata-retrieval/bitbucket/api"
+	"github.com/src-d/metadata-retrieval/utils/ctxlog"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// DefaultURL is the base URL of the Bitbucket Cloud REST API
+const DefaultURL = "https://api.bitbucket.org/2.0/"
+
+const (
+	// pageLen is the page size requested for the paginated resources, the
+	// maximum allowed by Bitbucket
+	pageLen = 100
+	// pullRequestsPageLen is the page size requested for the pull requests,
+	// Bitbucket does not allow more than 50
+	pullRequestsPageLen = 50
+)
+
+// pullRequestStates are all the states of a pull request, only the open ones
+// are listed unless they are requested
+var pullRequestStates = []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"}
+
+// Storer is an interface required by Downloader to persist the downloaded data
+type Storer interface {
+	SaveWorkspace(ctx context.Context, workspace *api.Workspace) error
+	SaveRepository(ctx context.Context, repository *api.Repository) error
+	SavePullRequest(ctx context.Context, workspace, repositorySlug string, pr *api.PullRequest) error
+	SavePullRequestComment(ctx context.Context, workspace, repositorySlug string, pullRequestID int, comment *api.Comment) error
+	SavePullRequestParticipant(ctx context.Context, workspace, repositorySlug string, pullRequestID int, participant *api.Participant) error
+
+	Begin() error
+	Commit() error
+	Rollback() error
+	Version(v int)
+	SetActiveVersion(ctx context.Context, v int) error
+	Cleanup(ctx context.Context, currentVersion int) error
+}
+
+// Downloader fetches Bitbucket Cloud data using the REST API 2.0
+type Downloader struct {
+	storer  Storer
+	client  *http.Client
+	baseURL string
+}
+
+// NewDownloader creates a new Downloader that will store the Bitbucket
+// metadata in the given Storer. The HTTP client is expected to have the
+// proper authentication setup. The base URL of the API is DefaultURL when
+// empty
+func NewDownloader(httpClient *http.Client, baseURL string, storer Storer) (*Downloader, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid Bitbucket API URL %q: %v", baseURL, err)
+	}
+
+	return &Downloader{
+		storer:  storer,
+		client:  httpClient,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+	}, nil
+}
+
+// get requests the given URL and decodes the JSON response into v
+func (d Downloader) get(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	path := strings.TrimPrefix(u, d.baseURL)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: could not decode the response: %v", path, err)
+	}
+
+	return nil
+}
+
+// page is a page of a paginated collection, the URL of the next page is empty
+// for the last one
+// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#pagination
+type page struct {
+	Next   string          `json:"next"`
+	Values json.RawMessage `json:"values"`
+}
+
+// downloadPages requests all the pages of the given resource path; process
+// is called with the raw JSON of the values of each page
+func (d Downloader) downloadPages(ctx context.Context, name string, path string, params url.Values, process func(values json.RawMessage) error) error {
+	u := d.baseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	for u != "" {
+		var res page
+		if err := d.get(ctx, u, &res); err != nil {
+			return fmt.Errorf("query to %s failed: %v", name, err)
+		}
+
+		if err := process(res.Values); err != nil {
+			return fmt.Errorf("can not process %s: %v", name, err)
+		}
+
+		u = res.Next
+	}
+
+	return nil
+}
+
+// repositoryPath returns the API path of a repository
+func repositoryPath(workspace string, slug string) string {
+	return "repositories/" + url.PathEscape(workspace) + "/" + url.PathEscape(slug)
+}
+
+// DownloadRepository downloads the metadata for the given repository and its
+// pull requests, with their comments and participants
+func (d Downloader) DownloadRepository(ctx context.Context, workspace string, slug string, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"owner": workspace, "repo": slug})
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var repository api.Repository
+	err = d.get(ctx, d.baseURL+repositoryPath(workspace, slug), &repository)
+	if err != nil {
+		return fmt.Errorf("repository query failed: %v", err)
+	}
+
+	err = d.storer.SaveRepository(ctx, &repository)
+	if err != nil {
+		return fmt.Errorf("failed to save repository %v: %v", repository.FullName, err)
+	}
+
+	err = d.downloadPullRequests(ctx, workspace, slug)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d Downloader) downloadPullRequests(ctx context.Context, workspace string, slug string) error {
+	logger := ctxlog.Get(ctx)
+	logger.Infof("start downloading pull requests")
+	defer logger.Infof("finished downloading pull requests")
+
+	params := url.Values{
+		"pagelen": []string{fmt.Sprint(pullRequestsPageLen)},
+		"state":   pullRequestStates,
+	}
+	path := repositoryPath(workspace, slug) + "/pullrequests"
+
+	return d.downloadPages(ctx, "pull requests", path, params, func(values json.RawMessage) error {
+		var prs []api.PullRequest
+		if err := json.Unmarshal(values, &prs); err != nil {
+			return err
+		}
+
+		for _, pr := range prs {
+			if err := d.downloadPullRequest(ctx, workspace, slug, pr.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// downloadPullRequest requests a single pull request, the pull requests list
+// does not include the participants
+func (d Downloader) downloadPullRequest(ctx context.Context, workspace string, slug string, id int) error {
+	var pr api.PullRequest
+	path := fmt.Sprintf("%s/pullrequests/%d", repositoryPath(workspace, slug), id)
+	if err := d.get(ctx, d.baseURL+path, &pr); err != nil {
+		return fmt.Errorf("pull request query failed: %v", err)
+	}
+
+	if err := d.storer.SavePullRequest(ctx, workspace, slug, &pr); err != nil {
+		return fmt.Errorf("failed to save PR #%v: %v", pr.ID, err)
+	}
+
+	for _, participant := range pr.Participants {
+		if err := d.storer.SavePullRequestParticipant(ctx, workspace, slug, pr.ID, &participant); err != nil {
+			return fmt.Errorf("failed to save participant %v of PR #%v: %v", participant.User.Nickname, pr.ID, err)
+		}
+	}
+
+	params := url.Values{"pagelen": []string{fmt.Sprint(pageLen)}}
+	return d.downloadPages(ctx, "pull request comments", path+"/comments", params, func(values json.RawMessage) error {
+		var comments []api.Comment
+		if err := json.Unmarshal(values, &comments); err != nil {
+			return err
+		}
+
+		for _, comment := range comments {
+			if err := d.storer.SavePullRequestComment(ctx, workspace, slug, pr.ID, &comment); err != nil {
+				return fmt.Errorf("failed to save comment %v of PR #%v: %v", comment.ID, pr.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListRepositories returns the slugs of the repositories of the given
+// workspace
+func (d Downloader) ListRepositories(ctx context.Context, workspace string, noForks bool) ([]string, error) {
+	params := url.Values{"pagelen": []string{fmt.Sprint(pageLen)}}
+	path := "repositories/" + url.PathEscape(workspace)
+
+	repositories := []string{}
+	err := d.downloadPages(ctx, "repositories", path, params, func(values json.RawMessage) error {
+		var res []api.Repository
+		if err := json.Unmarshal(values, &res); err != nil {
+			return err
+		}
+
+		for _, repository := range res {
+			if noForks && repository.Parent != nil {
+				continue
+			}
+
+			repositories = append(repositories, repository.Slug)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
+
+// DownloadWorkspace downloads the metadata for the given workspace
+func (d Downloader) DownloadWorkspace(ctx context.Context, name string, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"workspace": name})
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var workspace api.Workspace
+	err = d.get(ctx, d.baseURL+"workspaces/"+url.PathEscape(name), &workspace)
+	if err != nil {
+		return fmt.Errorf("workspace query failed: %v", err)
+	}
+
+	err = d.storer.SaveWorkspace(ctx, &workspace)
+	if err != nil {
+		return fmt.Errorf("failed to save workspace %v: %v", name, err)
+	}
+
+	return nil
+}
+
+// SetCurrent enables the given version as the current one accessible in the DB
+func (d Downloader) SetCurrent(ctx context.Context, version int) error {
+	err := d.storer.SetActiveVersion(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to set current DB version to %v: %v", version, err)
+	}
+	return nil
+}
+
+// Cleanup deletes from the DB all records that do not belong to the currentVersion
+func (d Downloader) Cleanup(ctx context.Context, currentVersion int) error {
+	err := d.storer.Cleanup(ctx, currentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to do cleanup for DB version %v: %v", currentVersion, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+const recFile = "../testdata/bitbucket-recordings.json"
+
+// getRoundTripDownloader returns a Downloader that replays the recorded
+// responses of the acme workspace, indexed by the request URI relative to the
+// API base URL. The requests that were not recorded are not found
+func getRoundTripDownloader(t *testing.T, storer Storer) *Downloader {
+	replayer, err := testutils.NewReplayer(recFile, "/2.0/", testutils.RecordedResponse{
+		Status: http.StatusNotFound,
+		Body:   []byte(`{"type":"error","error":{"message":"Resource not found"}}`),
+	})
+	require.NoError(t, err, "Failed to read the offline recordings")
+
+	downloader, err := NewDownloader(&http.Client{Transport: replayer}, "", storer)
+	require.NoError(t, err)
+
+	return downloader
+}
+
+func TestOfflineRepositoryDownload(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.BitbucketMemory{}
+	downloader := getRoundTripDownloader(t, storer)
+
+	err := downloader.DownloadRepository(context.TODO(), "acme", "api", 1)
+	require.NoError(err)
+
+	require.Equal("acme/api", storer.Repository.FullName)
+	require.Equal("master", storer.Repository.Mainbranch.Name)
+
+	// the pull requests are paginated
+	require.Len(storer.PRs, 2)
+	require.Equal("OPEN", storer.PRs[0].State)
+	require.Equal("MERGED", storer.PRs[1].State)
+	require.Equal("bob", storer.PRs[1].ClosedBy.Nickname)
+
+	require.Len(storer.Participants, 2)
+	require.True(storer.Participants[0].Approved)
+	require.Equal("REVIEWER", storer.Participants[0].Role)
+	require.Nil(storer.Participants[1].State)
+
+	require.Len(storer.PRComments, 3)
+	require.Equal("main.go", storer.PRComments[0].Inline.Path)
+	require.Equal(12, *storer.PRComments[0].Inline.To)
+	require.Equal(11, storer.PRComments[1].Parent.ID)
+	require.True(storer.PRComments[2].Deleted)
+}
+
+func TestOfflineRepositoryNotFound(t *testing.T) {
+	storer := &testutils.BitbucketMemory{}
+	downloader := getRoundTripDownloader(t, storer)
+
+	err := downloader.DownloadRepository(context.TODO(), "acme", "missing", 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+}
+
+func TestOfflineWorkspaceDownload(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.BitbucketMemory{}
+	downloader := getRoundTripDownloader(t, storer)
+
+	err := downloader.DownloadWorkspace(context.TODO(), "acme", 1)
+	require.NoError(err)
+	require.Equal("acme", storer.Workspace.Slug)
+	require.Equal("ACME", storer.Workspace.Name)
+}
+
+func TestOfflineListRepositories(t *testing.T) {
+	require := require.New(t)
+
+	downloader := getRoundTripDownloader(t, &testutils.BitbucketMemory{})
+
+	repositories, err := downloader.ListRepositories(context.TODO(), "acme", false)
+	require.NoError(err)
+	require.Equal([]string{"api", "api-fork", "web"}, repositories)
+
+	repositories, err = downloader.ListRepositories(context.TODO(), "acme", true)
+	require.NoError(err)
+	require.Equal([]string{"api", "web"}, repositories)
+}
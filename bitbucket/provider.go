@@ -0,0 +1,43 @@
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/src-d/metadata-retrieval/bitbucket/store"
+	"github.com/src-d/metadata-retrieval/provider"
+)
+
+func init() {
+	provider.Register("bitbucket", newProvider)
+}
+
+// newProvider creates a Downloader for the provider registry
+func newProvider(c provider.Config) (provider.Downloader, error) {
+	var storer Storer = &store.Stdout{}
+	if c.DB != nil {
+		storer = store.NewDB(c.DB)
+	}
+
+	d, err := NewDownloader(c.HTTPClient, c.BaseURL, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerDownloader{d}, nil
+}
+
+// providerDownloader implements provider.Downloader, where workspaces are
+// organizations
+type providerDownloader struct {
+	*Downloader
+}
+
+func (d providerDownloader) DownloadOrganization(ctx context.Context, name string, version int) error {
+	return d.DownloadWorkspace(ctx, name, version)
+}
+
+// RateRemaining returns provider.UnknownRate, Bitbucket does not report the
+// rate limit status
+func (d providerDownloader) RateRemaining(ctx context.Context) (int, error) {
+	return provider.UnknownRate, nil
+}
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/bitbucket/api"
+	"github.com/src-d/metadata-retrieval/database"
+)
+
+type DB struct {
+	*sql.DB
+	tx *sql.Tx
+	v  int
+}
+
+func NewDB(db *sql.DB) *DB {
+	return &DB{DB: db}
+}
+
+func (s *DB) Begin() error {
+	var err error
+	s.tx, err = s.DB.Begin()
+	return err
+}
+
+func (s *DB) Commit() error {
+	return s.tx.Commit()
+}
+
+func (s *DB) Rollback() error {
+	return s.tx.Rollback()
+}
+
+func (s *DB) Version(v int) {
+	s.v = v
+}
+
+const (
+	workspacesCols              = "created_at, htmlurl, name, private, slug, uuid"
+	repositoriesCols            = "created_at, default_branch, description, fork_policy, full_name, has_issues, has_wiki, htmlurl, language, name, parent_full_name, private, project_key, project_name, size, slug, updated_at, uuid, workspace_slug"
+	pullRequestsCols            = "base_ref, base_repository_full_name, base_sha, body, close_source_branch, closed_by_login, closed_by_uuid, comments, created_at, head_ref, head_repository_full_name, head_sha, htmlurl, id, merge_commit_sha, reason, repository_name, repository_owner, state, task_count, title, updated_at, user_login, user_name, user_uuid"
+	pullRequestCommentsCols     = "body, created_at, deleted, htmlurl, id, in_reply_to, path, position, pull_request_id, repository_name, repository_owner, updated_at, user_login, user_uuid"
+	pullRequestParticipantsCols = "approved, participated_at, pull_request_id, repository_name, repository_owner, role, state, user_login, user_uuid"
+)
+
+var tables = []string{
+	"bitbucket_workspaces_versioned",
+	"bitbucket_repositories_versioned",
+	"bitbucket_pull_requests_versioned",
+	"bitbucket_pull_request_comments_versioned",
+	"bitbucket_pull_request_participants_versioned",
+}
+
+func (s *DB) SetActiveVersion(ctx context.Context, v int) error {
+	// Unified schema
+
+	if err := database.SetUnifiedViews(ctx, s.DB, v); err != nil {
+		return err
+	}
+
+	// Bitbucket schema without versions
+
+	return database.SetVersionedViews(ctx, s.DB, tables, v)
+}
+
+func (s *DB) Cleanup(ctx context.Context, currentVersion int) error {
+	return database.CleanupVersioned(ctx, s.DB, tables, currentVersion)
+}
+
+// insert stores the given values of a row in a versioned table
+func (s *DB) insert(ctx context.Context, table string, cols string, args ...interface{}) error {
+	return database.InsertVersioned(ctx, s.tx, s.v, table, cols, args...)
+}
+
+func (s *DB) SaveWorkspace(ctx context.Context, workspace *api.Workspace) error {
+	err := s.insert(ctx, "bitbucket_workspaces_versioned", workspacesCols,
+		workspace.CreatedOn,       // created_at timestamptz,
+		workspace.Links.HTML.Href, // htmlurl text,
+		workspace.Name,            // name text,
+		workspace.IsPrivate,       // private boolean,
+		workspace.Slug,            // slug text NOT NULL,
+		workspace.UUID,            // uuid text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveWorkspace: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveRepository(ctx context.Context, repository *api.Repository) error {
+	var defaultBranch, parentFullName, projectKey, projectName string
+	if repository.Mainbranch != nil {
+		defaultBranch = repository.Mainbranch.Name
+	}
+
+	if repository.Parent != nil {
+		parentFullName = repository.Parent.FullName
+	}
+
+	if repository.Project != nil {
+		projectKey = repository.Project.Key
+		projectName = repository.Project.Name
+	}
+
+	err := s.insert(ctx, "bitbucket_repositories_versioned", repositoriesCols,
+		repository.CreatedOn,       // created_at timestamptz,
+		defaultBranch,              // default_branch text,
+		repository.Description,     // description text,
+		repository.ForkPolicy,      // fork_policy text,
+		repository.FullName,        // full_name text,
+		repository.HasIssues,       // has_issues boolean,
+		repository.HasWiki,         // has_wiki boolean,
+		repository.Links.HTML.Href, // htmlurl text,
+		repository.Language,        // language text,
+		repository.Name,            // name text,
+		parentFullName,             // parent_full_name text,
+		repository.IsPrivate,       // private boolean,
+		projectKey,                 // project_key text,
+		projectName,                // project_name text,
+		repository.Size,            // size bigint,
+		repository.Slug,            // slug text NOT NULL,
+		repository.UpdatedOn,       // updated_at timestamptz,
+		repository.UUID,            // uuid text,
+		repository.Workspace.Slug,  // workspace_slug text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveRepository: %v", err)
+	}
+	return nil
+}
+
+// endpoint returns the full name of the repository and the commit hash of the
+// source or the destination of a pull request
+func endpoint(e api.Endpoint) (string, string) {
+	var fullName, sha string
+	if e.Repository != nil {
+		fullName = e.Repository.FullName
+	}
+
+	if e.Commit != nil {
+		sha = e.Commit.Hash
+	}
+
+	return fullName, sha
+}
+
+func (s *DB) SavePullRequest(ctx context.Context, workspace, repositorySlug string, pr *api.PullRequest) error {
+	baseRepository, baseSHA := endpoint(pr.Destination)
+	headRepository, headSHA := endpoint(pr.Source)
+
+	var closedByLogin, closedByUUID, mergeCommitSHA string
+	if pr.ClosedBy != nil {
+		closedByLogin = pr.ClosedBy.Nickname
+		closedByUUID = pr.ClosedBy.UUID
+	}
+
+	if pr.MergeCommit != nil {
+		mergeCommitSHA = pr.MergeCommit.Hash
+	}
+
+	err := s.insert(ctx, "bitbucket_pull_requests_versioned", pullRequestsCols,
+		pr.Destination.Branch.Name, // base_ref text,
+		baseRepository,             // base_repository_full_name text,
+		baseSHA,                    // base_sha text,
+		pr.Description,             // body text,
+		pr.CloseSourceBranch,       // close_source_branch boolean,
+		closedByLogin,              // closed_by_login text,
+		closedByUUID,               // closed_by_uuid text,
+		pr.CommentCount,            // comments bigint,
+		pr.CreatedOn,               // created_at timestamptz,
+		pr.Source.Branch.Name,      // head_ref text,
+		headRepository,             // head_repository_full_name text,
+		headSHA,                    // head_sha text,
+		pr.Links.HTML.Href,         // htmlurl text,
+		pr.ID,                      // id bigint NOT NULL,
+		mergeCommitSHA,             // merge_commit_sha text,
+		pr.Reason,                  // reason text,
+		repositorySlug,             // repository_name text NOT NULL,
+		workspace,                  // repository_owner text NOT NULL,
+		pr.State,                   // state text,
+		pr.TaskCount,               // task_count bigint,
+		pr.Title,                   // title text,
+		pr.UpdatedOn,               // updated_at timestamptz,
+		pr.Author.Nickname,         // user_login text NOT NULL,
+		pr.Author.DisplayName,      // user_name text,
+		pr.Author.UUID,             // user_uuid text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("savePullRequest: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SavePullRequestComment(ctx context.Context, workspace, repositorySlug string, pullRequestID int, comment *api.Comment) error {
+	var path string
+	var position, inReplyTo *int
+	if comment.Inline != nil {
+		path = comment.Inline.Path
+		position = comment.Inline.To
+		if position == nil {
+			position = comment.Inline.From
+		}
+	}
+
+	if comment.Parent != nil {
+		inReplyTo = &comment.Parent.ID
+	}
+
+	err := s.insert(ctx, "bitbucket_pull_request_comments_versioned", pullRequestCommentsCols,
+		comment.Content.Raw,     // body text,
+		comment.CreatedOn,       // created_at timestamptz,
+		comment.Deleted,         // deleted boolean,
+		comment.Links.HTML.Href, // htmlurl text,
+		comment.ID,              // id bigint,
+		inReplyTo,               // in_reply_to bigint,
+		path,                    // path text,
+		position,                // position bigint,
+		pullRequestID,           // pull_request_id bigint NOT NULL,
+		repositorySlug,          // repository_name text NOT NULL,
+		workspace,               // repository_owner text NOT NULL,
+		comment.UpdatedOn,       // updated_at timestamptz,
+		comment.User.Nickname,   // user_login text NOT NULL,
+		comment.User.UUID,       // user_uuid text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("savePullRequestComment: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SavePullRequestParticipant(ctx context.Context, workspace, repositorySlug string, pullRequestID int, participant *api.Participant) error {
+	err := s.insert(ctx, "bitbucket_pull_request_participants_versioned", pullRequestParticipantsCols,
+		participant.Approved,       // approved boolean,
+		participant.ParticipatedOn, // participated_at timestamptz,
+		pullRequestID,              // pull_request_id bigint NOT NULL,
+		repositorySlug,             // repository_name text NOT NULL,
+		workspace,                  // repository_owner text NOT NULL,
+		participant.Role,           // role text,
+		participant.State,          // state text,
+		participant.User.Nickname,  // user_login text NOT NULL,
+		participant.User.UUID,      // user_uuid text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("savePullRequestParticipant: %v", err)
+	}
+	return nil
+}
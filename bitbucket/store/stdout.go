@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/bitbucket/api"
+)
+
+type Stdout struct{}
+
+func (s *Stdout) SaveWorkspace(ctx context.Context, workspace *api.Workspace) error {
+	fmt.Printf("workspace data fetched for %s\n", workspace.Slug)
+	return nil
+}
+
+func (s *Stdout) SaveRepository(ctx context.Context, repository *api.Repository) error {
+	fmt.Printf("repository data fetched for %s\n", repository.FullName)
+	return nil
+}
+
+func (s *Stdout) SavePullRequest(ctx context.Context, workspace, repositorySlug string, pr *api.PullRequest) error {
+	fmt.Printf("PR data fetched for #%v %s\n", pr.ID, pr.Title)
+	return nil
+}
+
+func (s *Stdout) SavePullRequestComment(ctx context.Context, workspace, repositorySlug string, pullRequestID int, comment *api.Comment) error {
+	fmt.Printf("  pr comment data fetched by %s at %v: %q\n", comment.User.Nickname, comment.CreatedOn, trim(comment.Content.Raw))
+	return nil
+}
+
+func (s *Stdout) SavePullRequestParticipant(ctx context.Context, workspace, repositorySlug string, pullRequestID int, participant *api.Participant) error {
+	fmt.Printf("  pr participant data fetched for %s (approved: %v)\n", participant.User.Nickname, participant.Approved)
+	return nil
+}
+
+func (s *Stdout) Begin() error {
+	return nil
+}
+
+func (s *Stdout) Commit() error {
+	return nil
+}
+
+func (s *Stdout) Rollback() error {
+	return nil
+}
+
+func (s *Stdout) Version(v int) {
+}
+
+func (s *Stdout) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+func (s *Stdout) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
+
+func trim(s string) string {
+	if len(s) > 40 {
+		return s[0:39] + "..."
+	}
+
+	return s
+}
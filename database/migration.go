@@ -4,6 +4,18 @@
 // database/migrations/000001_init.up.sql
 // database/migrations/000002_rename_github_tables.down.sql
 // database/migrations/000002_rename_github_tables.up.sql
+// database/migrations/000003_projects.down.sql
+// database/migrations/000003_projects.up.sql
+// database/migrations/000004_commit_comments.down.sql
+// database/migrations/000004_commit_comments.up.sql
+// database/migrations/000005_contributors.down.sql
+// database/migrations/000005_contributors.up.sql
+// database/migrations/000006_gitlab.down.sql
+// database/migrations/000006_gitlab.up.sql
+// database/migrations/000007_bitbucket.down.sql
+// database/migrations/000007_bitbucket.up.sql
+// database/migrations/000008_gitea.down.sql
+// database/migrations/000008_gitea.up.sql
 package database
 
 import (
@@ -80,7 +92,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __000001_initDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x01\x00\x00\xff\xff\x00\x00\x00\x00\x00\x00\x00\x00")
+var __000001_initDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
 
 func _000001_initDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -100,7 +112,7 @@ func _000001_initDownSql() (*asset, error) {
 	return a, nil
 }
 
-var __000001_initUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x58\x4d\x8f\xdb\x36\x10\xbd\xeb\x57\xf0\x96\x0f\x2c\x1a\xa0\x68\x73\xc9\x69\x93\xba\x85\xd1\x64\x37\x70\x5c\x20\x8b\xa2\x20\x28\x69\x2c\x0d\x96\x22\x15\x72\x64\xd7\xfb\xeb\x0b\xca\x5a\x8b\xb4\x28\x59\x59\x64\xb7\xc9\x51\x7c\x43\x6a\xc8\xf7\xde\x0c\xa5\xb7\x8b\x3f\x96\x57\x6f\x92\xe4\xdd\x6a\x71\xb9\x5e\xb0\xf5\xe5\xdb\xf7\x0b\xb6\xfc\x9d\x5d\x5d\xaf\xd9\xe2\xf3\xf2\xd3\xfa\x13\xd3\xa6\x10\x0a\xef\x04\xa1\x56\x96\x6f\xc1\x58\xd4\x0a\x72\xf6\x3c\x61\xcc\x36\xd5\xcf\xbf\xbe\x66\x59\x29\x8c\xc8\x08\x0c\xdb\x0a\xb3\x47\x55\x3c\x7f\xfd\xcb\x0b\xf6\x71\xb5\xfc\x70\xb9\xba\x61\x7f\x2e\x6e\x2e\x12\xc6\xba\x99\x96\xa1\x22\x28\xc0\xb0\xcb\xd5\xea\xf2\xe6\x22\x49\x18\x13\x5b\x41\xc2\xf0\xc6\x48\x46\xf0\x2f\xb9\xe8\x4c\x4b\x29\x52\x6d\x04\x69\x63\x59\x8a\x05\xaa\xc3\xb8\x01\x41\x90\x73\x41\x8c\xb0\x02\x4b\xa2\xaa\xe9\xce\x21\x39\xd8\xcc\x60\xed\xd2\x3c\xae\x02\x95\xc0\x7e\xcd\x92\x2a\xe9\xbf\x03\x73\x6f\x61\xa9\x0b\xec\x27\x2a\x51\x41\xff\xa0\x73\xe0\x98\x1f\x9f\xf5\x4e\x41\xce\x6b\x83\x5b\x41\xc0\x0d\xd4\xda\xcf\xb0\x6e\x52\x89\xd9\x60\x98\x34\x09\x39\x3a\xa9\xa9\xf3\xc8\xb6\x92\x17\x3d\x35\xcb\xab\xdf\x16\x9f\xe7\x50\x63\xd9\xf5\xd5\x38\x69\xf7\x41\x2f\xa6\x49\x6f\x2c\x98\xa7\x23\x3b\x45\xed\x11\x5f\xd5\x42\xed\xfb\xe7\x51\xc2\x43\x72\x37\x5a\x4a\xbd\x83\x40\x2c\x87\x31\x54\x85\x37\x56\xa2\x01\x91\x4a\x60\xa9\xd6\x12\x84\x9a\x21\x8c\x4c\x04\xa2\xfa\x1a\xa5\x78\x34\xf0\xe3\xaa\xed\x41\x5f\xfd\xf5\xfe\xfd\x20\xa4\x5f\x3a\x8c\x99\x16\x5c\x37\x5e\xa0\xa5\x88\x10\x47\x86\x9f\x42\x9f\x81\x8a\x5a\x5d\x0e\x74\x35\x53\x8f\x6d\x3e\x48\xda\x20\x3c\x86\x2c\x19\x73\xc2\x74\x62\xe1\x15\x98\x02\x78\xa6\xab\x0a\xc9\xd7\xc8\x01\x35\x90\x0a\x0b\x87\xa0\x21\x6a\xbf\x34\xc2\x96\x11\xd4\x64\x25\x6e\x21\xf7\xc7\x32\xa9\x15\x84\x25\x6f\xa2\xb4\x6d\x44\x23\x89\xa7\x46\xa8\xac\x3c\x4e\x88\x55\xbc\x1c\xad\x53\x77\xf0\xaa\x8d\x36\xb7\xa7\xcf\x96\x67\xba\x51\xe4\x9b\xa5\x91\x92\x07\x6a\x2e\x85\xe5\x68\x6d\x03\x36\x30\x8b\xb0\x7c\x87\xb7\x18\x8c\xe9\x0a\x6a\x51\xc0\xcc\x52\x2b\x54\xd1\xf8\xd1\xd3\x1e\xaa\x41\x75\x69\x0c\x72\x76\xc6\x30\x63\xc6\x6a\xb1\x09\x47\x19\x4e\xfb\x1a\x86\x58\xe7\x01\x7f\x7f\x75\x63\xcb\x28\x33\xd6\x96\xfe\x36\x2d\x09\x53\x88\x3b\xa7\xf1\xd3\x54\x49\xd7\x98\xd9\x36\xf2\xef\x7f\x82\xf7\xc5\xbd\xe5\x90\x9d\xa0\xac\x3c\x5d\xec\x9c\xe9\x62\x56\x69\xbd\x37\xe6\xa1\x99\x16\xec\x28\x78\x9c\x9e\x60\x2d\x16\x0a\x20\x7a\x3e\xa9\xce\xbd\x76\x20\xb5\x8d\x9e\x55\x07\xa4\xfb\x11\x39\xf4\xf8\x88\x24\x9c\xe5\x41\xd1\xbc\xcb\xc6\x19\x7d\xa7\x20\xa3\x5b\x91\x3a\xbb\x0d\xbd\x59\xa1\x04\x4b\xae\x14\x74\x92\x0f\xe2\x7b\x94\x90\x64\x44\xab\xa7\x66\x51\x4d\x95\x82\xf1\x72\x39\x92\xbe\xef\xbd\x1d\xac\xe0\x05\xb4\xa6\x18\x46\x58\x72\x6e\xb8\x7f\x43\x9f\xc8\xb4\x76\x5d\xa5\x1f\xa1\xa2\x85\x22\x2c\x9c\x53\x76\xa8\xc0\x56\xd3\x43\x51\x7e\x8d\x9a\xf9\x3d\xe7\x8f\xa4\xea\x86\x4a\x6d\xb8\xb0\x56\x67\x18\xde\x21\x42\x4d\x3f\x4c\x66\x87\x2d\x04\x8c\x4f\x6a\xe3\x1b\x48\xe1\xe9\xe9\x1e\x50\xd4\xd3\x1e\x65\x6f\x26\xfd\xb5\x6b\x76\x06\xbe\x34\x60\x1f\x8d\xfd\x3c\xc7\xf6\xfa\xed\x51\x36\x59\xe7\xa6\xe4\xe2\x2e\x1d\x06\x36\x43\x46\x3a\xe4\x0c\xb3\xa7\x51\x23\xf4\xb6\x61\xb6\x14\x23\x88\x23\x32\x02\x05\x52\x2e\x85\x2a\x20\xe7\x1b\x57\xb8\xfc\x4a\x3a\x5a\xb6\x23\x55\xb7\xbd\x7b\xcd\xfd\xe8\x93\x70\x7a\xc8\x25\x88\x3c\x7e\x58\x1d\x72\xe6\xb0\x4e\xa3\x46\x0e\xab\x0d\x8b\x1e\x56\x8b\xc4\x0f\xeb\x81\x7d\xa3\x12\xa8\x48\xa0\xbb\xb3\x64\x42\xf1\x4a\xe7\xb8\xd9\x07\x6d\xc4\xbb\xb5\x1e\x93\x3a\x02\xa7\xdf\x3b\xed\x60\x3e\x1c\x89\x1c\x70\x07\x8c\xb6\xd5\x1e\x1f\x69\xab\x3f\x40\x83\x33\xb0\x45\xd8\xf1\x88\x14\xbf\x9b\xd6\x17\xad\x57\x6d\x29\x1c\xad\x64\x0f\xa8\x84\xfc\x70\x12\x8f\x54\x10\xc3\x42\x71\x90\xaa\xc7\xec\xa4\x35\x4e\x65\x10\x64\x3d\xde\x02\xbf\xf9\xed\xc7\x36\x69\x85\xf4\xbf\xd0\x7e\x4a\xce\x80\xfd\x18\x7b\xbe\x08\x5e\xbd\x4c\xd6\x25\x1c\x3e\xb6\xd0\xba\x9c\x72\xf7\x29\x78\xf8\xe3\x42\x98\x4a\xa4\x3d\xdb\x21\x95\xac\x28\xed\x5e\x65\x17\x2c\x6d\x28\x7c\xc3\xbd\x45\x92\x5c\x83\x65\x4a\x13\xb3\xa4\x0d\x30\x2a\x81\x2d\x5d\x4f\x7e\x77\x08\x78\x66\x99\xde\xb0\x8f\x8d\x94\xab\xc3\xcc\x67\xf6\xa7\x64\xa9\x2c\x81\xc8\x19\x76\xb3\x6c\x3b\xcd\x0b\x5a\xb5\xf9\x77\x4b\x5c\x30\xab\x99\x60\x29\x90\x53\x5c\x9b\xf4\x4e\x37\x32\x67\x29\x24\x91\x5d\xf7\xa9\xbd\x7c\x35\x5b\xee\xdf\xc9\xf5\x6f\x60\x85\x89\x7e\x87\x9b\x0d\x2f\x1b\x75\x3b\xcf\x36\xa8\x5c\x23\x93\x7b\x4e\x7a\xc2\x4c\xda\x38\x48\x48\x3e\xcc\xe4\x08\xb5\x36\x71\x5b\xf0\x7e\x24\x09\xea\xff\x45\xc4\xf0\x79\x1e\x8d\x91\x19\x6c\xe2\x47\xbb\xb7\x4e\x4a\x6c\xe8\xda\xb3\xb7\xd8\xeb\x0f\x1f\x96\xeb\x37\xc9\x7f\x01\x00\x00\xff\xff\x69\x1e\x7d\x28\xab\x17\x00\x00")
+var __000001_initUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x58\xdf\x6f\xdb\xb6\x13\x7f\xd7\x5f\xc1\xb7\x36\x45\xf0\x2d\xf0\xc5\xd6\x97\x3e\xa5\x99\x37\x18\xcb\x8f\xc2\xf5\x80\x06\xc3\x40\x50\xd2\x59\x3a\x84\x22\x55\x1e\x69\xcf\xf9\xeb\x07\xca\xb6\x24\x5a\x94\xe2\x06\x49\xb6\xbe\x45\x77\x47\xf2\xc8\xcf\xe7\x73\x77\xf1\xa7\xd9\x6f\xf3\x9b\x8f\x49\x72\xb9\x98\x5d\x2c\x67\x6c\x79\xf1\xe9\x6a\xc6\xe6\xbf\xb2\x9b\xdb\x25\x9b\x7d\x9d\x7f\x59\x7e\x61\xda\x14\x42\xe1\x83\xb0\xa8\x15\xf1\x35\x18\x42\xad\x20\x67\x6f\x13\xc6\xc8\x55\xff\xff\xf9\x03\xcb\x4a\x61\x44\x66\xc1\xb0\xb5\x30\x5b\x54\xc5\xdb\x0f\x3f\x9d\xb1\xcf\x8b\xf9\xf5\xc5\xe2\x8e\xfd\x3e\xbb\x3b\x4f\x18\xdb\xaf\x24\x86\xca\x42\x01\x86\x5d\x2c\x16\x17\x77\xe7\x49\xc2\x98\x58\x0b\x2b\x0c\x77\x46\x32\x0b\x7f\x5b\x1f\x9d\x69\x29\x45\xaa\x8d\xb0\xda\x10\x4b\xb1\x40\xb5\xb3\x1b\x10\x16\x72\x2e\x2c\xb3\x58\x01\x59\x51\xd5\xf6\xc1\x7b\x72\xa0\xcc\x60\xed\xd3\x6c\x77\x81\x4a\x60\xb7\x67\x69\x2b\xd9\x3f\x03\xf3\xde\xc6\x52\x17\xd8\x2d\x54\xa2\x82\xee\x43\xe7\xc0\x31\x6f\xbf\xf5\x46\x41\xce\x6b\x83\x6b\x61\x81\x1b\xa8\x75\x3f\xc3\xda\xa5\x12\xb3\x81\xd9\x6a\x2b\xe4\xe8\x22\x57\xe7\x91\x6b\x25\x67\x1d\x34\xf3\x9b\x5f\x66\x5f\x4f\x81\x86\xd8\xed\xcd\x38\x68\x87\xa0\xb3\x69\xd0\x1d\x81\x79\x3d\xb0\x53\xd4\xed\xdf\x99\xae\x6a\xa1\xb6\xdd\xf7\x28\xe0\x21\xb8\x2b\x2d\xa5\xde\x40\x40\x96\x9d\x0d\x55\xd1\xb3\x95\x68\x40\xa4\x12\x58\xaa\xb5\x04\xa1\x4e\x20\x46\x26\x02\x52\x7d\x0f\x53\x7a\x30\xf0\x76\xd7\x46\x5d\x37\x7f\x5c\x5d\x0d\x42\xba\xad\xc3\x98\x69\xc2\xed\xed\x05\x92\x0d\xec\x3b\x22\x8e\x98\x5f\x83\x9f\x01\x8b\x1a\x5e\x0e\x78\x75\x22\x1f\x9b\x7c\xd0\x6a\x83\xf0\x12\xb4\x64\xcc\x13\xd3\x93\x85\x57\x60\x0a\xe0\x99\xae\x2a\xb4\x7d\x8e\xec\xbc\x06\x52\x41\xb0\x0b\x1a\x7a\xe9\x9b\x13\x54\x46\xbc\x26\x2b\x71\x0d\x79\xdf\x96\x49\xad\x20\x2c\x79\x13\xa5\x6d\x25\x9c\xb4\x3c\x35\x42\x65\x65\xbb\x20\x56\xf1\x72\x24\xcf\xee\xe0\xa8\x95\x36\xf7\xc7\xdf\xc4\x33\xed\x94\xed\x41\xbc\x72\x52\xf2\x80\xcd\xa5\x20\x8e\x44\x0e\xa8\xbf\xda\x5b\x37\x78\x8f\x81\x4d\x57\x50\x8b\x02\x4e\x2c\xb5\x42\x15\xae\x1f\x3d\xad\xa1\x1a\xd4\x3e\x8d\x41\xce\xbe\x12\x9b\x31\x61\x35\xbe\x09\x45\x19\x6e\xb7\x35\x0c\x7d\x7b\x0d\xf4\xef\x57\x3b\x2a\xa3\xc8\x10\x95\xfd\x6b\x92\x15\xa6\x10\x0f\x60\x86\xa9\x5a\x5d\x63\x46\xcd\x69\x7f\xfe\x15\x9c\x17\xd7\x96\x5f\xb3\x11\x36\x2b\x8f\x37\x7b\x4c\x74\x31\xa9\x34\xda\x1b\xd3\xd0\x89\x12\xdc\x43\xf0\xfc\xe2\xf3\xd2\x23\xc2\x42\x01\x44\xdf\x27\xd5\x79\xaf\x1d\x48\x4d\xd1\xb7\xda\x3b\xd2\xed\x08\x1d\x3a\xff\x08\x25\xbc\xe4\x41\x05\xa5\x72\x5c\x91\x8f\xf0\x3b\x05\x19\xbd\x8a\xd4\xd9\x7d\xa8\xcd\x0a\x25\x90\xd5\xaa\xa5\x7c\x10\xdf\x79\x2d\x5a\x19\xe1\xea\xb1\x58\x94\xab\x52\x30\xbd\x5c\x5a\xd0\xb7\x9d\xb6\x83\x1d\x7a\x01\x8d\x9a\x86\x11\x64\xbd\x1a\x0e\x27\x74\x89\x4c\x73\xd7\xd1\xa8\x32\x1d\xc5\x85\xf9\x18\xb3\x43\x06\x36\x9c\x1e\x92\xf2\x7b\xd8\xcc\x0f\x98\xbf\x10\xab\x9d\x2d\xb5\xe1\x82\x48\x67\x18\xce\x10\x21\xa7\x9f\x46\xb3\xe6\xee\x3c\x40\x7c\x92\x1b\xcf\x40\x85\xd7\x87\x7b\x00\x51\x07\x7b\x14\xbd\x13\xe1\xaf\x7d\xb3\x33\xf0\xcd\x01\xbd\x18\xfa\x79\x8e\x1e\xf3\x7e\x41\x99\xac\x73\x53\x74\xf1\x43\x87\x81\xd5\x10\x91\x66\x1c\x79\x14\xd9\xe3\xa8\x11\x78\x9b\x30\x2a\xc5\x88\xc7\x51\x74\x51\x40\xe5\x52\xa8\x02\x72\xbe\xf2\x85\xab\x77\xf1\x7d\xf5\x1d\xf2\xe6\x80\x61\x3f\xb6\x99\xbd\x4e\xab\xc3\x39\x48\x38\x7e\xe4\x12\x44\x1e\x7f\xac\xbd\xe7\x91\xc7\x3a\x8e\x1a\x79\xac\x26\x2c\xfa\x58\x8d\x27\xfe\x58\x4f\xec\x1b\x95\x40\x65\x05\xfa\x81\x26\x13\x8a\x57\x3a\xc7\xd5\x36\x68\x23\xbd\xa9\xb5\x4d\xaa\x75\x1c\xff\xbf\xd3\x18\xc3\x36\xd4\x58\x22\x0f\xbc\x77\x8c\xb6\xd5\xce\x1f\x51\xf8\x0f\xd2\xe0\x0c\xac\x11\x36\x6d\x39\xe9\x6d\xfe\x9f\x69\x7d\xd1\x7a\xd5\x94\xc2\xd1\x4a\xf6\x84\x4a\xc8\x77\x2f\xf1\x42\x05\x31\xec\x79\x3b\xaa\xf6\x90\x9d\x94\xc6\x31\x0d\x82\xac\xc7\x5b\xe0\xb3\x4f\x3f\xe4\xd2\x0a\xed\xbf\x02\xfb\x31\x38\x03\xf4\x63\xe8\x1d\x62\xfd\x39\xef\xdf\x25\xcb\x12\x58\x33\x06\x22\xf9\x9c\x72\xb6\xd2\xc6\x43\x51\x0b\x8b\xa9\x44\xbb\x65\x1b\xb4\x25\x2b\x4a\xda\xaa\xec\x9c\xa5\xce\x86\x27\x1c\x24\x92\xe4\x1a\x88\x29\x6d\x19\x59\x6d\x80\xd9\x12\xd8\xdc\xf7\xe4\xcb\x5d\xc0\x1b\x62\x7a\xc5\x3e\x3b\x29\x17\x3b\x8c\xde\xd0\xff\x92\xb9\x22\x0b\x22\x67\xb8\x5f\x45\xcd\xb2\x5e\xd0\xa2\xc9\x7f\xbf\xc5\x39\x23\xcd\x04\x4b\xc1\xfa\x16\xdc\x24\xbd\xd1\x4e\xe6\x2c\x85\x24\x72\xeb\x2e\xb5\x77\xef\x4f\xa6\xfb\x61\xcd\xcb\xf0\x7d\xaa\x9f\x4f\x4b\x61\xa2\xdf\xe1\x6a\xc5\x4b\xa7\xee\x4f\x93\x0d\x2a\xdf\xc8\xe4\x96\x5b\x3d\x21\x26\x6d\xbc\x4b\x48\x3e\xcc\xa4\x75\x35\x3a\xf2\x23\x49\xb7\x4d\x2d\x6c\xf7\x5b\x44\xcc\x7f\x9a\x46\x63\x60\x06\x97\xf8\xd1\xe6\xd6\x49\x8a\x0d\x55\x1b\x23\xe1\x21\xd8\xcb\xf6\xf2\xf6\xfa\x7a\xbe\xfc\x98\xfc\x33\x00\x69\x1e\x7d\x28\xab\x17\x00\x00")
 
 func _000001_initUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -120,7 +132,7 @@ func _000001_initUpSql() (*asset, error) {
 	return a, nil
 }
 
-var __000002_rename_github_tablesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\xd0\xcd\x0a\x83\x30\x0c\xc0\xf1\x7b\x9f\xa2\xef\xd1\x93\x8e\x32\x04\x3f\x40\x7a\x2f\x6e\x0b\x2e\xa0\xad\x6b\x5a\x07\x7b\xfa\xdd\x5d\x6a\x4f\x3b\xe7\x9f\x1f\x21\xb5\xbe\x36\xbd\x12\xa2\x6a\x8d\x1e\xa5\xa9\xea\x56\xcb\x19\xe3\x33\xdd\xac\x0f\xf3\xe4\xf0\x33\x45\xf4\x8e\xec\x0e\x81\xd0\x3b\x78\xc8\x51\xf7\x55\xa7\xa5\x19\x64\xa6\x50\x9c\x96\x08\x02\xaf\x1c\x26\xec\x76\x80\xcd\x13\x46\x1f\x10\x78\x84\x0f\x58\x0b\x89\x52\x46\x39\x8e\xf2\xfb\xf6\xee\xd7\x15\x5c\x3c\x71\x98\xe4\x0f\x9f\xd9\xd2\xb2\xd8\x00\xaf\x04\x94\x39\x26\x53\x14\x35\x1b\x60\x47\x78\x97\xd1\xdf\xb0\x6c\x9f\xbe\xaf\x50\x2a\x21\x2e\x43\xd7\x35\x46\x89\x6f\x00\x00\x00\xff\xff\x5c\x3d\xe0\x71\xbf\x02\x00\x00")
+var __000002_rename_github_tablesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xd0\xcf\x8a\x84\x30\x0c\xc7\xf1\x7b\x9f\xa2\xef\xd1\x93\x2e\x65\x11\xfc\x03\xd2\x7b\x71\x77\x83\x1b\xd0\xd6\x49\x5a\x07\xe6\xe9\xe7\xee\xa4\xf6\x9c\xef\xef\x73\x48\x6b\xbf\xbb\xd1\x28\xd5\xf4\xce\xce\xda\x35\x6d\x6f\xf5\x8a\xe9\x3f\xff\xf8\x48\xeb\x12\xf0\xb5\x24\x8c\x81\xfd\x09\xc4\x18\x03\xfc\xe9\xd9\x8e\xcd\x60\xb5\x9b\x74\xa1\x30\x92\x96\x19\x48\x56\x2e\x17\x71\x4d\x70\x44\xc6\x14\x09\x41\x46\xe4\x40\xb4\x90\x39\x17\x94\xeb\xa9\xbc\xf7\xbf\x71\xdf\x21\xa4\x1b\x47\x48\x44\xef\xc8\xdb\xe6\x09\x1e\x19\xb8\xc0\x15\x8a\xaa\xe6\x09\x4e\x84\x67\x1d\xfd\x0c\xeb\xf6\xed\x03\x2a\xa5\x51\xea\x6b\x1a\x86\xce\x19\xf5\x1e\x00\xe5\x74\xba\x80\x81\x02\x00\x00")
 
 func _000002_rename_github_tablesDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -135,12 +147,12 @@ func _000002_rename_github_tablesDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "000002_rename_github_tables.down.sql", size: 703, mode: os.FileMode(420), modTime: time.Unix(1571828565, 0)}
+	info := bindataFileInfo{name: "000002_rename_github_tables.down.sql", size: 641, mode: os.FileMode(420), modTime: time.Unix(1792344732, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __000002_rename_github_tablesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x4d\x6e\xb3\x30\x10\x40\xf7\x3e\xc5\x5c\x20\x27\x60\x95\x7c\x9f\x5b\x21\x85\x50\x11\xd4\x76\x87\x0c\x4c\xd3\x91\x00\xd3\xb1\xa1\x6a\x4e\x5f\x41\xd4\x1f\xa7\xd8\x61\x87\x34\x6f\x1e\xe2\x0d\x3b\x79\x1f\x1f\x22\x21\x36\x1b\xa8\x59\xf7\x30\x12\xbe\x1b\xb0\x1a\x4a\x04\x55\x36\x38\x3d\x56\x8c\xca\x22\xb4\xca\x22\x93\x6a\xe8\x8c\xf5\x85\x13\xff\xb3\xf4\x01\x1e\x63\xf9\x04\xf1\x1d\xc8\xe7\xf8\x98\x1f\x41\xf3\x49\x75\x74\x56\x96\x74\x67\xa2\x45\x64\x30\xc8\x9e\x11\x63\xaf\x0d\x59\xcd\x84\x1e\x82\x8c\x19\x82\xb3\xa2\xd2\x6d\x8b\x9d\xf5\x30\xfd\xd0\x34\x05\xe3\xdb\x80\x66\x0d\x52\x30\xce\xdf\xba\x82\xfc\x79\xef\x94\x93\xb1\x53\x2d\x82\x9d\x2a\xce\x45\x4d\x8f\x15\xbd\x7c\x40\xcf\x7a\xa4\x1a\x59\x6c\xf7\xb9\xcc\x20\xdf\xee\xf6\xd2\xad\x56\x8c\xc8\x86\x74\x87\x35\x64\xf2\xb0\x4d\x24\xe4\x29\x9c\xc8\xbe\x0e\x65\xe1\x01\x23\xc7\x36\x07\x0e\x59\xae\x00\x77\xfb\xf7\x0d\x42\x92\x65\xce\x75\x5d\xae\x15\xb2\x5c\x13\x0b\xfb\xdf\x65\x6f\x7a\x16\x48\xd7\xe7\x5c\x3f\xa4\xf3\x80\x7e\xdb\xd7\x8f\xb2\x56\xfa\x97\x0f\xb8\xd7\x04\xb8\xb1\x10\x09\xf1\x2f\x4d\x92\x38\x8f\xc4\x67\x00\x00\x00\xff\xff\x9a\x3a\xc2\xa9\xf5\x03\x00\x00")
+var __000002_rename_github_tablesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\xd1\x4e\x83\x30\x14\x86\xef\xfb\x14\xe7\x05\xf6\x04\x5c\x31\xad\x86\x64\x0c\xc3\x88\x7a\x47\x0a\x1c\xe7\x49\x80\xe2\x69\xc1\xb8\xa7\x37\x60\xd4\xb1\xd1\x8e\xbb\x26\xe7\x3b\x5f\xd3\xff\xef\x56\x3e\x46\xfb\x40\x88\xcd\x06\x2a\xd6\x1d\x0c\x84\x9f\x06\xac\x86\x02\x41\x15\x35\x8e\xc7\x92\x51\x59\x84\x46\x59\x64\x52\x35\x9d\xb0\xfa\xe1\xc4\x7d\x9a\x3c\xc1\x73\x24\x5f\x20\x7a\x00\xf9\x1a\x1d\xb2\x03\x68\x3e\xaa\x96\x4e\xca\x92\x6e\x4d\xb0\x88\xf4\x06\xd9\x31\x62\xec\xb4\x21\xab\x99\xd0\x41\x90\x31\xbd\x77\x96\x97\xba\x69\xb0\xb5\x0e\xa6\xeb\xeb\x3a\x67\xfc\xe8\xd1\xac\x41\x72\xc6\xe9\xad\x2b\xc8\xff\x7b\xc7\x38\x19\x5b\xd5\x20\xd8\x31\xc5\x29\x51\xd3\x61\x49\x6f\x5f\xd0\xb1\x1e\xa8\x42\x16\xe1\x2e\x93\x29\x64\xe1\x76\x27\xe7\xa9\xe5\x03\xb2\x21\xdd\x62\x05\xa9\xdc\x87\xb1\x84\x2c\x81\x23\xd9\xf7\xbe\xc8\x1d\x60\x30\xb3\x4d\x01\xfb\x2c\x17\xc0\x7c\xfb\xbc\x03\x9f\x64\x99\x9b\xbb\xa6\x46\xbc\x96\x4b\x62\x61\xff\xaf\xd1\x9b\x9e\x05\x72\xee\x3b\x2f\xcc\xab\x73\x80\x6e\xdb\xef\x47\x59\x2b\xbd\xe6\x3d\xee\x35\x01\xdc\x58\x08\x84\xb8\x4b\xe2\x38\xca\x02\xf1\x3d\x00\x9a\x3a\xc2\xa9\xf5\x03\x00\x00")
 
 func _000002_rename_github_tablesUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -160,6 +172,246 @@ func _000002_rename_github_tablesUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __000003_projectsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\xf3\x74\x0d\x57\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xcf\x2c\xc9\x28\x4d\x8a\x2f\x28\xca\xcf\x4a\x4d\x2e\x29\xb6\x26\x42\x51\x7c\x72\x7e\x4e\x69\x6e\x1e\x91\x6a\x13\x8b\x52\x88\x52\x59\x1c\x5f\x66\x44\x94\x89\x65\x46\xf1\x99\x25\xa9\xb9\xc5\x30\x0f\x85\x38\x3a\xf9\xb8\xe2\x33\x36\xb5\xa8\x38\x33\x3f\x2f\x35\xc5\x9a\x18\xf5\x30\xcf\x91\xac\x0d\xe4\x4f\x12\x35\x81\xbc\x4c\xaa\x3d\x30\xdf\x23\xeb\xe3\x72\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x0c\x00\xee\x52\x2c\xd5\xe9\x01\x00\x00")
+
+func _000003_projectsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000003_projectsDownSql,
+		"000003_projects.down.sql",
+	)
+}
+
+func _000003_projectsDownSql() (*asset, error) {
+	bytes, err := _000003_projectsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000003_projects.down.sql", size: 489, mode: os.FileMode(420), modTime: time.Unix(1792334755, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000003_projectsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x95\x4f\x6f\x9c\x30\x10\xc5\xef\x7c\x8a\x39\x66\xa5\x9c\xa2\x36\x97\x9c\x36\x2d\xad\x50\xf7\x4f\x45\xa8\x94\x3d\x59\x06\x4f\xc1\x91\xb1\x91\x3d\xd0\x6e\x3f\x7d\xb5\xa8\xb0\x2c\x32\x4d\xba\x5d\xda\x1c\x61\x9e\xff\x30\xef\x37\x8f\xfb\xf0\x63\xb4\xb9\x0b\x82\x77\x71\xb8\x4c\x42\x48\x96\xf7\xab\x10\xa2\x0f\xb0\xd9\x26\x10\x3e\x46\x0f\xc9\x03\xe4\x92\x8a\x3a\x65\x95\x35\x4f\x98\x91\x63\x0d\x5a\x27\x8d\x46\x01\x57\x01\x80\xab\xcb\x9b\xb7\xb7\x90\x15\xdc\xf2\x8c\xd0\x42\xc3\xed\x5e\xea\xfc\xea\xf6\xcd\x02\x3e\xc7\xd1\x7a\x19\xef\xe0\x53\xb8\xbb\x0e\x00\x7e\xad\x74\x20\x35\x61\x8e\x16\x96\x71\xbc\xdc\x5d\x07\x01\x40\x6a\xc4\x1e\x08\xbf\xd3\x41\x97\x29\xe3\x50\x40\x6a\x8c\x42\xae\x8f\x6f\x18\x27\x20\x59\xa2\x23\x5e\x56\xf4\xa3\x2d\x58\xe4\x34\x5d\x31\x96\x29\x93\x4b\xdd\x6e\xdd\x7e\xd4\xe6\xcb\x6a\x75\x58\x58\x50\xa9\x6a\xab\xfa\x33\xa5\x80\x54\xe6\x52\xb7\x0f\x9a\x97\xd8\x57\xb4\x11\xc8\xa4\x38\x3e\xd7\x65\x8a\x76\xa0\x36\xdf\x34\x4e\x9e\x63\xb1\x32\x4e\x92\xb1\x7b\xd6\xef\x7a\x22\x70\xc4\xe9\x78\x58\x5d\x09\xcf\xf7\x04\x8b\xa3\x45\xd1\xe6\x7d\xf8\xf8\x32\x8b\x1c\x6c\x37\xbf\xb3\xaf\x93\x2d\xfe\x04\x00\x96\x19\x55\x97\x7a\x26\x0e\xa6\xfd\x3c\xdf\xb0\xee\xe2\xc3\xf7\x27\x16\x54\xb5\xad\x8c\xbb\xac\x09\xe3\x36\x79\xbc\xf0\x75\xf2\x4c\x4b\xb8\x15\x33\x19\xc2\x6d\x56\xc8\x66\x34\x8d\xed\xbd\xa7\xfb\x99\x19\x4d\xa8\xe9\x05\x82\xe1\x28\x79\x15\xcf\x8e\x8f\x47\xd8\xce\xe3\xb4\x92\xf6\x95\x6f\x9f\x79\x82\x64\xd0\x81\x43\x51\x9b\xc1\xac\x77\xee\x4d\xb6\xe9\xf2\xd1\x70\x4a\x8a\x97\xc9\x31\x4a\x67\x11\xe9\x58\x73\x33\x0f\x8f\xff\xe3\xdf\xf0\x77\x7f\x80\xaa\x4e\x95\xcc\x86\x37\xb6\xc8\xc5\x20\xaf\x9e\x65\xdc\x15\xc6\x12\x13\xe8\x32\x2b\x2b\x92\x46\xf7\x4b\x49\x92\xba\x2c\x21\x43\xe7\x3c\x7c\x8c\x8d\x3d\x8b\x8e\xc3\x1e\x92\xb0\x74\xf3\x20\xe2\x8f\xac\x57\x1d\x49\xbd\x8d\xff\x22\xb1\xbe\x4a\x54\x82\x35\x5c\xd5\xe8\xe0\xc9\x19\x9d\x9e\x94\xc7\xb4\x77\xa6\x4d\xb6\xae\xbf\xde\xc5\x52\x6a\xcc\x87\x2f\xa8\x7c\x0c\x75\xf2\x96\xc6\xed\x7a\x1d\x25\x77\xc1\xcf\x01\x00\xf3\x25\xb3\x44\xe2\x0a\x00\x00")
+
+func _000003_projectsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000003_projectsUpSql,
+		"000003_projects.up.sql",
+	)
+}
+
+func _000003_projectsUpSql() (*asset, error) {
+	bytes, err := _000003_projectsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000003_projects.up.sql", size: 2786, mode: os.FileMode(420), modTime: time.Unix(1792334755, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000004_commit_commentsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\x75\x0c\x71\x0d\xf2\x74\xf4\xf1\x8c\x72\x75\x51\x08\xf3\x74\x0d\x57\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\xcf\xcd\x4d\xcd\x2b\x29\xb6\x86\x28\x45\x93\x4d\xcf\x2c\xc9\x28\x4d\x8a\x07\x29\xca\x2c\x89\x47\xa8\x85\x28\x0e\x71\x74\xf2\x71\x25\xa8\x3a\xbe\x2c\xb5\xa8\x38\x33\x3f\x2f\x35\xc5\x9a\x8b\xcb\xd9\xdf\xd7\xd7\x33\xc4\x9a\x0b\x30\x00\xfc\xe7\x15\xca\xa0\x00\x00\x00")
+
+func _000004_commit_commentsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_commit_commentsDownSql,
+		"000004_commit_comments.down.sql",
+	)
+}
+
+func _000004_commit_commentsDownSql() (*asset, error) {
+	bytes, err := _000004_commit_commentsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_commit_comments.down.sql", size: 160, mode: os.FileMode(420), modTime: time.Unix(1792344389, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000004_commit_commentsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x91\x41\x6b\x3a\x31\x10\xc5\xef\xf9\x14\x73\x54\xf0\xf4\xe7\x5f\x2f\x9e\xd6\x36\x2d\x4b\x75\x2d\xeb\x16\xf4\xb4\xc4\xcd\xb0\x3b\x60\x12\x49\x26\xb6\xf6\xd3\x17\x83\xdd\xad\x2d\x85\xf6\x14\xde\xe4\xf7\x26\x8f\xbc\xb9\x7c\xc8\x8b\x99\x10\xb7\xa5\xcc\x2a\x09\x55\x36\x5f\x48\xc8\xef\xa1\x58\x55\x20\x37\xf9\xba\x5a\x43\x4b\xdc\xc5\x5d\xdd\x38\x63\x88\xd3\x81\x96\x43\x7d\x44\x1f\xc8\x59\xd4\x30\x12\x00\x21\x9a\x7f\x37\x53\x68\x3a\xe5\x55\xc3\xe8\xe1\xa8\xfc\x89\x6c\x3b\x9a\xfe\x1f\xc3\x53\x99\x2f\xb3\x72\x0b\x8f\x72\x3b\x11\x00\x17\x67\x00\xb2\x8c\x2d\x7a\xc8\xca\x32\xdb\x4e\x84\x00\x50\x91\x3b\xe7\x6b\x15\x82\x6b\x48\x31\x39\x0b\x8c\xaf\x7c\x76\xed\x9c\x3e\xf5\xe2\x12\x86\xf4\x30\xf1\xa8\x18\x75\xad\x18\x98\x0c\x06\x56\xe6\xc0\x6f\x67\x63\xc7\x66\x1f\xfd\xbe\x27\x49\xc3\x8e\x5a\xb2\x49\x58\xa7\xf1\xf3\x9a\x83\xe2\x6e\x10\x2e\x50\xca\x30\xf0\x1e\xd3\xd0\xf9\x53\x6d\x95\xc1\x84\xa6\xcf\x2a\x9e\x17\x8b\x2f\x80\x7b\xb1\xe8\xbf\x13\xf1\xa0\x7f\x48\x1a\x03\xfa\xba\x8f\x77\x6d\x3a\x5f\xed\x5d\x4b\xf6\x7a\xa1\x18\x0f\xe5\xe5\xc5\x9d\xdc\xfc\xa9\xbc\x00\xab\xe2\x17\xfd\x7e\xd0\xe9\xad\xd5\x72\x99\x57\x33\xf1\x3e\x00\x18\x4a\x33\xb8\x39\x02\x00\x00")
+
+func _000004_commit_commentsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_commit_commentsUpSql,
+		"000004_commit_comments.up.sql",
+	)
+}
+
+func _000004_commit_commentsUpSql() (*asset, error) {
+	bytes, err := _000004_commit_commentsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_commit_comments.up.sql", size: 569, mode: os.FileMode(420), modTime: time.Unix(1792335086, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000005_contributorsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\x75\x0c\x71\x0d\xf2\x74\xf4\xf1\x8c\x72\x75\x51\x08\xf3\x74\x0d\x57\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x2d\x4e\x2d\x2a\xb6\x86\xa8\x43\x93\x4a\xcf\x2c\xc9\x28\x4d\x8a\x4f\xce\xcf\x2b\x29\xca\x4c\x2a\x2d\xc9\x07\x29\x84\xa8\x0c\x71\x74\xf2\x71\xc5\xaf\x34\xbe\x2c\xb5\xa8\x38\x33\x3f\x2f\x35\xc5\x9a\x8b\xcb\xd9\xdf\xd7\xd7\x33\xc4\x9a\x0b\x30\x00\x16\x4d\x8e\x80\x97\x00\x00\x00")
+
+func _000005_contributorsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_contributorsDownSql,
+		"000005_contributors.down.sql",
+	)
+}
+
+func _000005_contributorsDownSql() (*asset, error) {
+	bytes, err := _000005_contributorsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_contributors.down.sql", size: 151, mode: os.FileMode(420), modTime: time.Unix(1792335247, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000005_contributorsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x41\x8f\x9b\x40\x0c\x85\xef\xf3\x2b\x7c\x4c\xa4\x9c\xaa\x36\x97\x9c\x48\x4b\x2b\xd4\x86\x54\x84\x43\x72\x42\x06\x5c\xb0\x34\x8c\xd1\x8c\x21\x4d\x7f\x7d\x95\xd5\x26\xb0\xab\x8d\x76\x6f\xbc\x8f\xf7\x6c\x6b\xf4\xb6\xf1\x8f\x24\xdd\x18\xf3\x35\x8b\xa3\x3c\x86\x3c\xda\xfe\x8a\x21\xf9\x0e\xe9\x3e\x87\xf8\x98\x1c\xf2\x03\x34\xac\xed\x50\x16\x95\x38\xf5\x5c\x0e\x2a\x3e\x14\x23\xf9\xc0\xe2\xa8\x86\x85\x01\x08\x43\xf7\xe9\xcb\x1a\xaa\x16\x3d\x56\x4a\x1e\x46\xf4\x17\x76\xcd\x62\xfd\x79\x09\xbf\xb3\x64\x17\x65\x27\xf8\x19\x9f\x56\x06\xe0\x39\x19\x80\x9d\x52\x43\x1e\xa2\x2c\x8b\x4e\x2b\x63\x00\x70\x44\x45\x5f\x0c\xde\x82\xd2\x5f\xbd\xba\x4b\x96\xfb\x77\x25\x5d\x8f\xee\x32\x69\x4f\xa8\x54\x17\xa8\xa0\xdc\x51\x50\xec\x7a\xfd\x77\x4d\x51\x87\x3c\xcd\xf8\x23\xd6\xca\x99\x7c\x80\x92\x1b\x76\x33\xc6\xae\x99\xb1\x96\x3d\x61\x69\x09\x4a\x11\x4b\xe8\xae\xbe\x56\x3b\x3b\xbf\x87\xeb\x59\xc0\x4a\x85\xca\xe2\xee\x7f\xad\x34\x3c\x29\x87\x1d\x4d\x42\x6a\x2a\xb8\xbe\x6b\x39\x3b\xaa\x8b\xde\xf3\x88\x4a\x85\xa7\x5e\xe6\xe7\xdd\x78\xc3\x41\x5f\xf0\xa1\xb4\x5c\x3d\xc2\xaf\xa7\xa8\x28\xda\x87\x3b\x86\xbe\x7e\xe3\xf9\xcc\x72\x2a\x43\x92\x7e\x8b\x8f\x1f\x2f\x43\x80\x7d\xfa\x5e\x59\x6e\xd6\xa7\x2d\xfb\xdd\x2e\xc9\x37\xe6\xff\x00\x46\xd8\xd4\xb9\x83\x02\x00\x00")
+
+func _000005_contributorsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_contributorsUpSql,
+		"000005_contributors.up.sql",
+	)
+}
+
+func _000005_contributorsUpSql() (*asset, error) {
+	bytes, err := _000005_contributorsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_contributors.up.sql", size: 643, mode: os.FileMode(420), modTime: time.Unix(1792335247, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000006_gitlabDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\xd0\xc1\x4e\xc3\x30\x0c\xc6\xf1\x7b\x9f\x22\xef\x91\x53\xc7\x02\x8a\xb4\x32\xb4\x55\x80\xb8\x44\x65\x58\x55\x50\x5b\x07\x3b\xe9\x5e\x1f\x69\xd5\xd8\x06\x53\x71\x76\xff\x7d\x6d\xfc\x5f\x98\x07\xfb\xa8\x8b\x62\xb9\x59\x3f\xa9\xaa\xac\xcd\xc6\x96\x2b\xfb\x66\x96\xea\xd9\x9a\x17\x65\xef\x95\x79\xb5\xdb\x7a\xab\x76\xd8\xf7\x30\x44\xd6\xff\x53\xdc\x0f\x40\x12\x98\x58\xe6\x08\x02\xb2\x8f\x48\x1e\x24\xdc\x33\x27\x39\x74\x19\x97\x85\xd4\x75\x8e\xe0\x2b\x01\x67\x7b\x47\x30\x7a\xd8\x67\xcf\x4e\xcf\x9b\x86\xbf\x6c\xeb\x63\xd7\xbc\xbb\x96\x30\x05\xd6\x73\xe4\xbc\xf6\x75\x11\x08\x3f\x61\x17\xe7\xd1\x45\xdd\xeb\xa4\x07\x6a\xe1\x78\xc0\x3c\x1d\x30\xe6\x7c\xcc\x35\x21\x10\x8e\x4d\xf7\x93\xa3\x2e\x17\x2b\xf3\x77\x35\xf5\x70\x23\x10\x7b\x1c\xe0\x43\xcf\xea\x43\x1a\x29\x3e\x56\x92\xfa\x29\x98\x54\x5f\xb6\x93\xae\x0e\x19\x6f\xfa\xc5\xa9\xe8\xf9\xbc\xb8\x5b\x57\x95\xad\x75\xf1\x3d\x00\x6c\xe9\x28\xf8\x20\x04\x00\x00")
+
+func _000006_gitlabDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000006_gitlabDownSql,
+		"000006_gitlab.down.sql",
+	)
+}
+
+func _000006_gitlabDownSql() (*asset, error) {
+	bytes, err := _000006_gitlabDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000006_gitlab.down.sql", size: 1056, mode: os.FileMode(420), modTime: time.Unix(1792344389, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000006_gitlabUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x56\xc1\x8e\xdb\x36\x10\xbd\xeb\x2b\xe6\x98\x0d\x16\x08\x50\xb4\xb9\xe4\xe4\xb4\x6e\x61\x74\xd7\x5b\x38\x2e\x90\x45\x51\x10\x14\x35\x96\xd8\xa5\x48\x95\x33\x72\xeb\x7e\x7d\x41\xd9\x96\x44\x4b\xda\x7a\x8d\xb8\x9b\x9b\x35\x9c\x79\xd4\xcc\x7b\x6f\xe4\x8f\xf3\x9f\x16\xcb\x0f\x49\xf2\xfd\x6a\x3e\x5b\xcf\x61\x3d\xfb\x78\x37\x87\xc5\x8f\xb0\x7c\x58\xc3\xfc\xf3\xe2\xd3\xfa\x13\xe4\x9a\x8d\x4c\x45\xee\x5d\x5d\x91\xd8\xa2\x27\xed\x2c\x66\xf0\x26\x01\xa0\xba\xfc\xe6\xbb\xf7\xa0\x0a\xe9\xa5\x62\xf4\xb0\x95\x7e\xa7\x6d\xfe\xe6\xfd\xb7\x37\xf0\xcb\x6a\x71\x3f\x5b\x3d\xc2\xcf\xf3\xc7\xdb\x04\xe0\x50\x49\xa0\x2d\x63\x8e\x1e\x66\xab\xd5\xec\xf1\x36\x49\x00\xe4\x56\xb2\xf4\xa2\xf6\x06\x18\xff\xe6\x90\xad\x3c\x4a\xc6\x4c\x48\x06\xd6\x25\x12\xcb\xb2\xe2\x7f\xc2\x49\x86\xa4\xbc\xae\x58\x3b\xdb\x66\x6f\x6a\x63\x84\x95\x25\xc6\x91\x4a\x72\xd1\x44\x9a\x7e\x96\xbf\xde\xdd\x05\x80\x82\x4b\xd3\xbf\x4a\x67\x90\xea\x5c\xdb\xa6\x2e\x02\xa9\xa4\x47\xcb\x22\x4a\x68\x31\x43\xf6\x56\x93\x4e\xb5\xd1\xbc\x6b\x42\xc9\x4d\x37\xca\xc5\xf2\x87\xf9\xe7\x73\x46\x49\xf0\xb0\x9c\x1e\xf2\x31\xe9\xe6\x2c\x92\x6a\x42\x7f\x2d\x8e\x94\x42\x22\x61\x70\x8b\xa6\x37\x8e\x11\xea\x9a\x26\xc4\x33\x04\xec\x13\xda\xa9\x5e\x48\x0e\xb1\xe4\xee\x29\x74\xde\x1e\xb7\x88\x67\x12\x12\x8d\xad\xcf\xc7\x60\x9e\x2f\xa3\xa3\xf2\xee\x0f\x54\x7c\x2d\x46\xbc\x2a\xf4\x16\x33\x48\x9d\x33\x28\xed\x7f\xd9\x66\x23\x6b\xc3\x22\xf5\xd2\xaa\x4e\xc1\xa3\x6e\x72\xfe\xa9\x0f\xba\x71\xfe\x89\x84\x72\xb5\xe5\x1e\x19\x43\xcf\x9d\x72\x57\x30\x57\x62\x92\x4c\x23\x89\x85\x54\xac\xb7\x9a\x77\x23\x2f\x1c\x41\x87\x07\xaa\xa4\xc2\xe7\x74\xd5\x25\x0d\x54\xb3\x0f\x3f\x69\x9b\xb5\x90\xae\x42\x2b\x34\x51\x8d\xc3\xde\xc6\xf1\x89\x8a\x7e\x37\x14\x76\xd6\x69\x25\xbb\x4a\x2b\x6a\x6a\x7f\xfb\x3d\xaa\xbe\x6c\x57\x9c\x4a\xa8\xaf\xce\x31\x79\xbd\x4c\xa0\x87\xf6\xaf\x23\x4f\x22\x9d\x5b\xc4\xd1\x61\xa4\x2e\xdb\xb5\x83\x54\xc6\xd1\xa8\x66\x0f\x07\xe9\x2e\x26\xb4\x0b\x1b\x97\xeb\x4e\xb7\xca\x95\x25\x5a\xa6\x7e\xaa\xb3\x1b\x9d\xa1\x65\x2d\xcd\x79\x3e\x79\x76\x01\xe9\xf6\x29\xea\xc6\xc8\x14\xcd\x68\x9f\xc6\xa9\xa7\xd8\xa1\xa5\x36\x48\xec\xec\x89\x48\xbb\x30\x6b\x36\x9d\xf0\x3d\x56\x8e\x34\x3b\xbf\xeb\xbc\x16\xdd\xd0\x4b\x70\x7f\x59\xf4\x23\xb2\x8d\x16\x65\x0c\x5f\x57\xd9\xc4\x1c\xc2\xea\xeb\x5e\x31\x02\x6c\x8e\xba\xd1\xbf\x74\xdb\xc6\xa2\xeb\x0b\x7a\x28\xc7\x97\xc9\xb9\x44\x9f\xa3\xf0\xf8\x67\x8d\xc4\x5f\xb1\xac\x47\x74\x3a\xbd\xb7\xbd\xdc\x70\x5f\x40\x5f\x56\xa0\xfb\x91\x05\xe7\x68\x16\x54\xc8\x16\x76\x7f\x10\x3e\xb2\x35\xc5\xc1\xb1\xb7\x3c\x1c\x9c\x3a\xb5\x0b\xc7\x4e\x7d\x05\x13\xf4\x5a\x23\x57\x7b\x85\xa7\x5f\xc2\x43\xf4\xb0\x54\xe3\x17\x8b\xff\x6b\xb0\xf4\x39\x0e\xbe\xa4\x87\xe8\x68\xfd\xeb\x9b\x6e\xdc\x1a\x7d\xf3\x4d\x9b\xe7\x98\x1c\x6e\x7a\xf7\x36\x59\x17\x08\xd6\x31\x12\xb8\x0d\xec\x2d\x0b\xd2\x66\xd0\x00\xc0\x11\x00\xa4\x47\x20\x76\x1e\x33\x08\x3b\xba\x40\xa0\x66\x81\xc9\xd4\xe0\x6d\x12\x00\xc2\x2f\xc1\xbb\x0a\x41\x13\xa0\xe6\x02\x3d\x2c\x02\x1e\x38\x0f\xf7\x01\x6d\xb5\x07\x4b\xde\xbe\x3b\xc3\xfd\x01\xf2\x4a\xa6\x8f\x7d\x3d\xe9\xd5\x88\xf3\xb6\xc3\x09\x4f\xc6\x13\x78\x4e\xd2\x17\x6b\x7e\x47\x8c\x65\x7f\x77\xbc\x86\xf2\x22\x5a\xfa\x82\x1b\xf0\x75\xcc\xb9\x60\xd9\x0b\x59\x55\xde\x6d\xa5\xb9\x92\x00\xe2\xcb\x26\x18\xfd\x02\x94\xfd\x4f\x0b\x60\x38\xae\xc9\x4d\x30\x3e\xd9\x63\x55\x73\xf7\xc3\xfd\xfd\x62\xfd\x21\xf9\x77\x00\x4d\x7e\xa6\x59\x4c\x10\x00\x00")
+
+func _000006_gitlabUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000006_gitlabUpSql,
+		"000006_gitlab.up.sql",
+	)
+}
+
+func _000006_gitlabUpSql() (*asset, error) {
+	bytes, err := _000006_gitlabUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000006_gitlab.up.sql", size: 4172, mode: os.FileMode(420), modTime: time.Unix(1792335587, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000007_bitbucketDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\x4d\x6e\x85\x20\x14\x46\xe7\xac\x82\x7d\x30\xf2\xf5\xd1\x86\x44\x6b\xa3\xa4\x6d\x3a\x21\x4a\xef\x80\xf8\x03\xbd\xa0\x6e\xbf\x83\x26\x96\x98\x67\x14\xe7\xe7\x90\x7b\x3e\x6e\xfc\x45\xbc\x32\x42\xee\x55\xf9\x46\x8b\x4c\xf2\x4a\x64\xb9\xf8\xe2\x77\xfa\x2e\xf8\x07\x15\xcf\x94\x7f\x8a\x5a\xd6\x54\xdb\x61\x80\x31\x78\x76\x8c\xda\x65\x04\x3c\x03\x22\x38\xeb\x4d\xb0\x68\xe0\x0c\xee\xa6\xbe\x57\x08\x3f\x13\xf8\x90\xca\x2b\x84\xd9\xc0\x92\xac\xfd\x67\xff\x89\x1b\xb6\x35\xa1\x9d\x74\x07\x41\x2d\x16\x3b\xef\x1a\xbd\x96\xec\x92\x0f\xaa\x77\xd9\xf8\x96\x24\x58\x6d\xfe\xeb\x9c\xe4\x1a\x0c\x46\x1b\xd7\x44\xc5\x32\xbb\xe5\xfc\x20\x59\xcd\x80\xde\xd8\x11\xbe\xd9\x91\x14\xd7\x27\x68\xf1\x99\x57\xbd\x75\x93\xab\x7e\x3c\x4f\xfc\x06\x79\x2a\x8b\x42\x48\x46\x7e\x07\x00\xf7\x0e\x9f\x5b\x4e\x03\x00\x00")
+
+func _000007_bitbucketDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000007_bitbucketDownSql,
+		"000007_bitbucket.down.sql",
+	)
+}
+
+func _000007_bitbucketDownSql() (*asset, error) {
+	bytes, err := _000007_bitbucketDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000007_bitbucket.down.sql", size: 846, mode: os.FileMode(420), modTime: time.Unix(1792344389, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000007_bitbucketUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x95\xcd\x6e\xe3\x38\x0c\xc7\xef\x7e\x0a\x1e\xdb\xa2\x40\x81\xc5\x6e\x2f\x3d\xa5\xbb\xd9\x41\x30\x6d\x3a\x48\x33\x40\x7b\x12\x14\x8b\xb5\x35\xb1\x25\x0f\x45\x27\x93\x3e\xfd\x40\x1e\x7f\xc8\x89\x93\xe9\xc7\xa4\x47\xff\x45\x8a\x22\xf9\x23\x7d\x3d\xfe\x34\x99\x5e\x45\xd1\xbf\xb3\xf1\x68\x3e\x86\xf9\xe8\xfa\x66\x0c\x93\xff\x61\x7a\x37\x87\xf1\xc3\xe4\x7e\x7e\x0f\x0b\xcd\x8b\x32\x5e\x22\x8b\xb5\xa5\xa5\x2b\x64\x8c\x4e\xac\x90\x9c\xb6\x06\x15\x9c\x44\x00\xae\xcc\xff\xfa\xe7\x12\xe2\x54\x92\x8c\x19\x09\x56\x92\x36\xda\x24\x27\x97\x7f\x9f\xc2\x97\xd9\xe4\x76\x34\x7b\x84\xcf\xe3\xc7\xf3\x08\xa0\xf6\x74\xa0\x0d\x63\x82\x04\xa3\xd9\x6c\xf4\x78\x1e\x45\x00\x31\xa1\x64\x54\x42\x32\xb0\xce\xd1\xb1\xcc\x0b\x7e\xf6\x4e\x29\xe7\x59\x49\x19\x30\xfe\x60\xff\x6d\x64\x8e\xed\x47\x41\x7a\x25\x19\x61\x61\x6d\x86\xd2\xf8\x73\x97\x95\x49\x75\x5e\x25\x32\xfd\x7a\x73\xe3\xd5\xb2\xd4\xaa\x52\xa3\xd3\x2e\xe5\xc9\xf4\xbf\xf1\xc3\x2b\x52\x76\x70\x37\xfd\x6d\x4d\x1a\xdb\xd3\x97\x96\x96\xb0\xb0\x4e\xb3\x25\xfd\xf1\xc5\x55\xf8\x24\xcb\x8c\xc5\x82\xa4\x89\xd3\xb6\xac\x0a\x5d\x4c\xba\x60\x6d\x4d\xab\x3d\x59\x5a\x8a\xc2\x66\x3a\xde\x74\x5a\x99\x65\xa2\xd7\x90\x54\x3a\xa1\x9d\x2b\xd1\x85\x3d\xf1\xea\x5a\x2f\x75\x4f\xdb\xea\x6b\x26\x4d\x52\xca\x04\xf7\x34\x5a\x12\x1a\x16\xbb\x01\x07\x08\x28\xc8\x7e\xc3\x98\xc5\x12\xbb\x97\x36\x5a\xcf\xd7\xe9\x67\x84\x85\x4e\xb4\xe1\x03\xe4\x14\x6a\x4f\xf1\x5a\xa6\xbc\x73\x0b\x83\xd8\xbd\xe6\xe5\xcc\x0d\xb1\xb0\x45\xdd\x3e\x5c\x5e\xcd\x5d\xe1\x6b\x49\xf8\xbd\x44\xc7\x47\x02\x6f\x21\x1d\x0a\xc2\xa7\xb6\x4a\xb5\x50\x67\xb0\x19\xe8\x67\x65\xe1\x52\xd9\x09\x56\x75\x6d\x8c\x33\xeb\x8f\x6d\x49\x31\x36\xd0\x06\x9d\xaf\x8e\x95\x58\x6c\x44\x66\x13\xdd\xb1\xdb\xe9\xbd\x9e\xc5\x36\xcf\xd1\xb0\x0b\x18\x38\xb0\x87\x50\xaa\x5e\x2e\xb5\x70\x20\x97\xca\x22\xcc\x65\x7b\x97\x69\x55\x87\xee\x11\x97\x23\x25\x28\xfc\xe3\x34\xf7\xdc\x09\xa5\x0b\x26\x32\x88\xdd\x86\xed\x5d\x14\x18\xd8\xb5\x41\xda\xb5\x70\xec\x67\xa7\xb9\x90\xa5\x5b\x8a\xd8\x96\x86\x83\x8a\xb0\xe6\xac\x33\x39\x30\x0d\x0e\x29\x28\x7b\x2f\x4c\x75\xd6\x2b\x4d\xa5\xbc\x61\x29\x0f\x42\xbb\x35\x21\x7b\xc1\x7e\xd7\x88\x88\x86\x96\x23\x8d\x4a\x0f\xf3\xbd\x14\x2a\xcc\x90\x51\x85\xd0\xef\x85\xaa\xfa\x30\x7e\xdc\xb2\x8d\x60\x1b\xa8\x85\xe4\x6e\xd9\x57\x8c\xf8\x4d\x1f\x9c\x87\x79\x0f\x43\xfa\x07\xe0\x7b\x07\x4b\xef\x24\x67\xa7\x97\x07\x08\x1a\xec\x7b\xe3\xe6\xa3\x5f\x9c\x45\xf3\x14\xfd\x1f\x8a\x75\xac\x0b\xe9\x17\x8a\x24\x04\x4e\x11\x08\x57\x1a\xd7\x48\x0e\xa4\x51\x95\xe2\x9f\xef\x80\x53\xc9\x20\x8b\x82\xec\x0a\x15\x58\x82\x3a\x08\xaa\x48\x82\xe7\x17\xea\xe8\x57\xf5\x88\x6a\xd7\x9a\x9f\xfb\xb5\x6c\x12\x74\xcd\x0b\x7f\xdd\xe0\xeb\x13\x9d\x5d\xbc\x01\xec\xf0\xe5\xc7\x81\xbb\xcd\x34\xc0\xb6\x8d\x3a\x8c\xc0\xc7\x20\x48\x36\xd8\x6d\xfd\x65\x78\x74\x06\x87\xca\x7e\x88\xc3\x21\xfb\x9d\xad\x76\x77\x7b\x3b\x99\x5f\x45\x3f\x07\x00\x61\x87\xbb\x64\xdc\x0b\x00\x00")
+
+func _000007_bitbucketUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000007_bitbucketUpSql,
+		"000007_bitbucket.up.sql",
+	)
+}
+
+func _000007_bitbucketUpSql() (*asset, error) {
+	bytes, err := _000007_bitbucketUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000007_bitbucket.up.sql", size: 3036, mode: os.FileMode(420), modTime: time.Unix(1792335999, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000008_giteaDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\xd1\x4a\xc3\x30\x14\x86\xef\xfb\x14\x79\x8f\x5c\x75\x2e\x4a\x60\x75\xb2\x15\x15\x6f\x42\xd5\xc3\x38\x90\xe6\xcc\x9c\x74\x03\x9f\x5e\xb0\x68\xbb\x52\xe3\xe9\xee\xbf\x2f\xe4\xfc\xdf\xca\xdc\xd9\x7b\x5d\x14\xeb\xdd\xf6\x41\x55\x65\x6d\x76\xb6\xdc\xd8\x17\xb3\x56\x8f\xd6\x3c\x29\x7b\xab\xcc\xb3\xdd\xd7\x7b\xf5\x46\x6d\x0b\x21\xb1\xfe\x1f\xa5\x73\x80\x28\x01\x3b\x96\x71\x11\x8e\xc4\x98\x28\x22\x48\x70\x64\xee\xe4\xa0\x5b\x70\xd9\xb1\xf3\xde\x45\xf8\xe8\x80\x17\xf3\x2e\xc2\x09\xe1\xbc\x58\x1b\xbe\xd7\x8b\x13\xf6\x80\x09\x1a\x47\xf1\xd0\x04\xfc\x6c\x12\x52\x60\x9d\x01\xc7\x93\xcf\x02\x33\x5b\xcf\x72\xbe\x79\x05\x9f\x25\x5a\xf4\xc0\x89\x42\xfe\x9d\x8b\x58\x7f\x13\xd3\x4a\xb3\xe4\x78\x37\x31\x38\x74\xe9\x85\xba\x5c\x6d\x4c\x7e\x60\x77\x82\xc8\x48\x01\xde\x75\xce\xf9\xde\x5a\xc8\x8e\x67\x17\x2a\x7d\x01\x21\x3c\xc4\x10\x0a\x7d\x97\x25\xf0\x6f\x22\xa1\x74\x51\xeb\x0a\xe7\x27\xdc\x58\x2d\x6e\xb6\x55\x65\x6b\x5d\x7c\x0d\x00\xaf\x18\xcc\x5e\xd8\x04\x00\x00")
+
+func _000008_giteaDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000008_giteaDownSql,
+		"000008_gitea.down.sql",
+	)
+}
+
+func _000008_giteaDownSql() (*asset, error) {
+	bytes, err := _000008_giteaDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000008_gitea.down.sql", size: 1240, mode: os.FileMode(420), modTime: time.Unix(1792344389, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000008_giteaUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x56\x5f\x6f\xdb\x36\x10\x7f\xd7\xa7\xe0\x63\x53\x04\x28\x30\x6c\x7d\xe9\x93\xbb\x79\x83\xb1\xc4\x19\x5c\x0f\x68\x30\x0c\xc2\x49\x3a\x4b\x87\x50\xa4\xc6\xa3\xec\x39\x9f\x7e\x90\x2c\x4b\xa4\x25\xbb\x72\x16\xb5\x2f\x7d\xd4\xdd\x91\x3c\xfe\xfe\x1c\xf5\x71\xfe\xdb\x62\xf9\x21\x08\x7e\x5e\xcd\x67\xeb\xb9\x58\xcf\x3e\xde\xcd\xc5\xe2\x57\xb1\x7c\x58\x8b\xf9\xe7\xc5\xa7\xf5\x27\x91\x92\x45\x08\xb5\x49\x41\xd1\x33\x58\xd2\x8a\xc3\x2d\x1a\x26\xad\x30\x11\x6f\x02\x21\xb8\xcc\x7f\xf8\xe9\xbd\x88\x33\x30\x10\x5b\x34\x62\x0b\x66\x4f\x2a\x7d\xf3\xfe\xc7\x1b\xf1\xc7\x6a\x71\x3f\x5b\x3d\x8a\xdf\xe7\x8f\xb7\x81\x10\xcd\x4a\x16\xa4\x2c\xa6\x68\xc4\x6c\xb5\x9a\x3d\xde\x06\x81\x10\xb0\x05\x0b\x26\x2c\x8d\x14\x16\xff\xb5\x55\x75\x82\x1c\x1b\x2a\xaa\x43\xdb\x18\xe6\x40\x5d\x05\x25\x22\xa2\x94\x54\x9d\x92\x3a\x06\xaf\x56\xea\x94\x0e\x5f\xf5\x8d\x96\x7f\xde\xdd\x55\x61\x05\x39\xb6\x35\x5b\x62\x8a\x48\x92\xdd\xb7\xa1\x1d\x46\x4c\xf6\x50\x12\xdc\x74\xf0\x2c\x96\xbf\xcc\x3f\x8f\x87\x87\xc5\xc3\xf2\x4b\xf0\x1d\x4b\x6f\xc6\x90\x50\x32\x9a\xaf\x07\x7e\x6c\x10\x2c\x26\x21\x58\x61\x29\x47\xb6\x90\x17\xf6\xf9\x0b\x14\x10\x87\x90\xe4\xa4\x44\xa4\xb5\x44\x50\x2f\xa6\xc5\x45\x2c\x6c\x8f\xf0\xea\xbd\x92\x33\x7b\x5e\x4f\xa5\x07\xb2\x43\x61\x0f\xfc\xab\xa8\x33\x58\x68\x26\xab\x0d\xe1\x54\x0c\x9a\x38\xa3\x2d\x26\x2e\xf2\xb1\xd4\x0a\x47\x92\x9a\xe0\x06\x4a\x69\xc3\xc8\x80\x8a\xb3\x8b\x16\xdc\x68\xf3\xe4\x1e\x53\x7d\x73\x18\xeb\x52\x59\x47\x0a\x9b\x52\xca\xd0\xe3\x34\x03\x0e\x89\xb9\x44\x76\x57\x57\xd1\xa2\xaa\x35\xf8\x4f\x89\x6c\x7b\xc9\x1d\x3d\x91\x17\xd3\x39\x16\x90\x3a\xfb\xda\x5c\x96\xe6\x8c\x22\x73\x32\x46\x1b\x77\x7d\xdb\x93\xaf\xa6\x02\x55\xd3\x5d\xef\x2a\x75\xce\xeb\xb1\x5f\xb2\x53\x68\x3a\xa1\x76\xa1\x33\xc2\x2c\x0c\x6d\xc1\xa2\xdb\x17\x73\xe6\xde\x82\x2d\x98\x14\x9e\xd1\xf4\x0f\xb3\xba\xa0\x98\xeb\x3b\xfc\xf5\xb7\xb7\x6d\x59\x24\x03\xfc\x8e\x53\xfe\x90\x46\x1d\x03\x9c\x93\xf0\x55\x3e\x90\x10\xa1\x9c\xc8\x01\xb1\x96\xda\x5c\x14\xae\x47\x8f\x27\xcd\xf6\x72\xfb\x4e\xb2\x1e\xb0\x4e\x41\xcd\x6b\xbf\xe2\xc8\xdd\x38\xb0\x7d\x20\x1c\x98\xfb\x08\x5d\x05\x70\x4e\x12\xd9\x6a\x85\x53\x81\x2c\x35\x0f\xce\x8f\x26\x71\xf4\x77\x0b\xf3\xa5\x89\xd3\x67\x28\x29\x31\xd4\xea\xb4\xd2\xb7\x55\x67\x54\x27\xfa\x0a\x04\xb2\x85\xe6\xa5\xa8\xce\xb4\x64\x65\xf7\xf5\x7f\x7c\xd5\xa7\xc4\xa1\x7b\x98\xaf\xab\x28\x6f\x86\xd6\x24\x74\x03\x33\xa5\x0a\x71\x70\xd8\x44\x3a\xe9\x7e\x9a\x1a\xfe\xfb\x34\xc7\x3a\xcf\x51\xd9\x71\x9a\xb8\x38\xca\x0f\xd6\x18\xea\x44\xea\xf8\xc9\x7f\xfa\x5a\x5c\xfd\x99\xdc\x85\x7d\x7e\x55\x99\x47\x68\x9a\xc2\x73\xba\xf9\x7a\xc2\xaa\x33\xec\x3e\x28\xde\x86\x25\x0f\x3f\x2c\xe3\xf4\xe8\xeb\xc5\xd1\x62\x5f\x48\xc7\x9a\x6a\xdf\x77\x6f\x83\x75\x86\x1d\x9d\x7a\x23\x1a\x17\x82\x4a\x44\xf5\x3a\x8a\xf6\x05\x07\x83\x82\xad\x36\x98\x88\xaa\xc7\x0c\x05\xd7\xd8\x41\x24\xf1\x56\x00\x07\xa4\xaa\x33\xb3\x32\x3a\x3c\xb9\xe1\x71\xd7\xee\xf0\xe0\xed\xbb\x91\xd2\x1f\x58\xfc\x9a\x13\xcf\x57\xf9\xcb\x94\x7b\xe8\x73\x52\x91\x7d\x2b\x21\xf5\xd0\x3f\x15\xd4\x20\x3d\xc7\xda\x51\x03\xce\xff\xf3\x9a\x84\xe4\xcb\x73\x0e\x18\x43\x83\x9b\x96\xdb\x26\xd0\xe7\x6c\x28\xd9\xf1\xd5\x66\x39\x83\x2e\x30\xc1\x10\x45\x48\xbc\x7e\x9b\xc0\x70\xbf\xa7\x49\xbf\xdf\x3a\xeb\xf6\xfb\x7a\x13\x1a\x4d\x7a\x10\x07\x59\xef\x84\x3a\x51\x4d\x8a\x5e\x75\x7f\xfd\xd0\xf5\x9b\x44\xb4\xef\x44\xef\x87\x3b\xc1\x7f\x7f\x29\x2e\xbe\x14\x83\xc6\x73\xfc\x7d\xd6\x98\x2f\xb6\x77\x68\x70\x4b\xb8\x9b\xc8\xe5\xbe\xd7\x06\x2c\x75\x10\x23\x25\xe3\xd4\xae\x37\x1b\x8a\x09\xa4\xab\x4a\xef\x32\x53\x6b\xc5\xb7\x88\x2f\x1e\x2e\xa3\x9c\xec\x37\x14\xcc\x29\x95\x67\x74\x33\xc4\xf8\x71\x45\x7d\xe6\xc3\xfd\xfd\x62\xfd\x21\xf8\x6f\x00\xb1\xcc\xc1\xb7\xab\x14\x00\x00")
+
+func _000008_giteaUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000008_giteaUpSql,
+		"000008_gitea.up.sql",
+	)
+}
+
+func _000008_giteaUpSql() (*asset, error) {
+	bytes, err := _000008_giteaUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000008_gitea.up.sql", size: 5291, mode: os.FileMode(420), modTime: time.Unix(1792336220, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -216,6 +468,18 @@ var _bindata = map[string]func() (*asset, error){
 	"000001_init.up.sql":                   _000001_initUpSql,
 	"000002_rename_github_tables.down.sql": _000002_rename_github_tablesDownSql,
 	"000002_rename_github_tables.up.sql":   _000002_rename_github_tablesUpSql,
+	"000003_projects.down.sql":             _000003_projectsDownSql,
+	"000003_projects.up.sql":               _000003_projectsUpSql,
+	"000004_commit_comments.down.sql":      _000004_commit_commentsDownSql,
+	"000004_commit_comments.up.sql":        _000004_commit_commentsUpSql,
+	"000005_contributors.down.sql":         _000005_contributorsDownSql,
+	"000005_contributors.up.sql":           _000005_contributorsUpSql,
+	"000006_gitlab.down.sql":               _000006_gitlabDownSql,
+	"000006_gitlab.up.sql":                 _000006_gitlabUpSql,
+	"000007_bitbucket.down.sql":            _000007_bitbucketDownSql,
+	"000007_bitbucket.up.sql":              _000007_bitbucketUpSql,
+	"000008_gitea.down.sql":                _000008_giteaDownSql,
+	"000008_gitea.up.sql":                  _000008_giteaUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -263,6 +527,18 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"000001_init.up.sql":                   &bintree{_000001_initUpSql, map[string]*bintree{}},
 	"000002_rename_github_tables.down.sql": &bintree{_000002_rename_github_tablesDownSql, map[string]*bintree{}},
 	"000002_rename_github_tables.up.sql":   &bintree{_000002_rename_github_tablesUpSql, map[string]*bintree{}},
+	"000003_projects.down.sql":             &bintree{_000003_projectsDownSql, map[string]*bintree{}},
+	"000003_projects.up.sql":               &bintree{_000003_projectsUpSql, map[string]*bintree{}},
+	"000004_commit_comments.down.sql":      &bintree{_000004_commit_commentsDownSql, map[string]*bintree{}},
+	"000004_commit_comments.up.sql":        &bintree{_000004_commit_commentsUpSql, map[string]*bintree{}},
+	"000005_contributors.down.sql":         &bintree{_000005_contributorsDownSql, map[string]*bintree{}},
+	"000005_contributors.up.sql":           &bintree{_000005_contributorsUpSql, map[string]*bintree{}},
+	"000006_gitlab.down.sql":               &bintree{_000006_gitlabDownSql, map[string]*bintree{}},
+	"000006_gitlab.up.sql":                 &bintree{_000006_gitlabUpSql, map[string]*bintree{}},
+	"000007_bitbucket.down.sql":            &bintree{_000007_bitbucketDownSql, map[string]*bintree{}},
+	"000007_bitbucket.up.sql":              &bintree{_000007_bitbucketUpSql, map[string]*bintree{}},
+	"000008_gitea.down.sql":                &bintree{_000008_giteaDownSql, map[string]*bintree{}},
+	"000008_gitea.up.sql":                  &bintree{_000008_giteaUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
@@ -0,0 +1,15 @@
+BEGIN;
+
+DROP VIEW IF EXISTS github_projects;
+DROP VIEW IF EXISTS github_project_columns;
+DROP VIEW IF EXISTS github_project_cards;
+DROP VIEW IF EXISTS github_projects_v2;
+DROP VIEW IF EXISTS github_project_v2_items;
+
+DROP TABLE IF EXISTS github_projects_versioned;
+DROP TABLE IF EXISTS github_project_columns_versioned;
+DROP TABLE IF EXISTS github_project_cards_versioned;
+DROP TABLE IF EXISTS github_projects_v2_versioned;
+DROP TABLE IF EXISTS github_project_v2_items_versioned;
+
+COMMIT;
@@ -0,0 +1,109 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS github_projects_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  body text,
+  closed boolean,
+  closed_at timestamptz,
+  created_at timestamptz,
+  creator_login text NOT NULL,
+  htmlurl text,
+  id bigint,
+  name text,
+  node_id text,
+  number bigint,
+  owner_login text NOT NULL,
+  repository_name text NOT NULL,
+  state text,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS github_projects_versions ON github_projects_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS github_project_columns_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  created_at timestamptz,
+  htmlurl text,
+  id bigint,
+  name text,
+  node_id text,
+  project_node_id text NOT NULL,
+  purpose text,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS github_project_columns_versions ON github_project_columns_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS github_project_cards_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  archived boolean,
+  column_node_id text NOT NULL,
+  content_node_id text NOT NULL,
+  content_number bigint NOT NULL,
+  content_repository_name text NOT NULL,
+  content_repository_owner text NOT NULL,
+  content_type text NOT NULL,
+  created_at timestamptz,
+  creator_login text NOT NULL,
+  htmlurl text,
+  id bigint,
+  node_id text,
+  note text,
+  project_node_id text NOT NULL,
+  state text,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS github_project_cards_versions ON github_project_cards_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS github_projects_v2_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  closed boolean,
+  closed_at timestamptz,
+  created_at timestamptz,
+  creator_login text NOT NULL,
+  htmlurl text,
+  node_id text,
+  number bigint,
+  owner_login text NOT NULL,
+  public boolean,
+  readme text,
+  repository_name text NOT NULL,
+  short_description text,
+  title text,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS github_projects_v2_versions ON github_projects_v2_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS github_project_v2_items_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  archived boolean,
+  content_node_id text NOT NULL,
+  content_number bigint NOT NULL,
+  content_repository_name text NOT NULL,
+  content_repository_owner text NOT NULL,
+  content_title text NOT NULL,
+  content_type text NOT NULL,
+  created_at timestamptz,
+  creator_login text NOT NULL,
+  field_values jsonb NOT NULL,
+  node_id text,
+  project_node_id text NOT NULL,
+  type text,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS github_project_v2_items_versions ON github_project_v2_items_versioned (versions);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS comments;
+DROP VIEW IF EXISTS github_commit_comments;
+
+DROP TABLE IF EXISTS github_commit_comments_versioned;
+
+COMMIT;
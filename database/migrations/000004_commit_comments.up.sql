@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS github_commit_comments_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  author_association text,
+  body text,
+  commit_id text,
+  created_at timestamptz,
+  htmlurl text,
+  id bigint,
+  node_id text,
+  path text,
+  position bigint,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS github_commit_comments_versions ON github_commit_comments_versioned (versions);
+
+COMMIT;
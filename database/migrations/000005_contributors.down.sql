@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS users;
+DROP VIEW IF EXISTS github_contributors;
+
+DROP TABLE IF EXISTS github_contributors_versioned;
+
+COMMIT;
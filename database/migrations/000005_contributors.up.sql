@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS github_contributors_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  avatar_url text,
+  bio text,
+  company text,
+  created_at timestamptz,
+  email text,
+  followers bigint,
+  following bigint,
+  hireable boolean,
+  htmlurl text,
+  id bigint,
+  location text,
+  login text,
+  name text,
+  node_id text,
+  owned_private_repos bigint,
+  private_gists bigint,
+  public_gists bigint,
+  public_repos bigint,
+  total_private_repos bigint,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS github_contributors_versions ON github_contributors_versioned (versions);
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS comments;
+DROP MATERIALIZED VIEW IF EXISTS owners;
+DROP MATERIALIZED VIEW IF EXISTS users;
+DROP MATERIALIZED VIEW IF EXISTS repositories;
+DROP MATERIALIZED VIEW IF EXISTS issues;
+DROP MATERIALIZED VIEW IF EXISTS issue_comments;
+DROP MATERIALIZED VIEW IF EXISTS pull_requests;
+DROP MATERIALIZED VIEW IF EXISTS pull_request_reviews;
+DROP MATERIALIZED VIEW IF EXISTS pull_request_comments;
+
+DROP VIEW IF EXISTS gitlab_groups;
+DROP VIEW IF EXISTS gitlab_users;
+DROP VIEW IF EXISTS gitlab_projects;
+DROP VIEW IF EXISTS gitlab_issues;
+DROP VIEW IF EXISTS gitlab_merge_requests;
+DROP VIEW IF EXISTS gitlab_notes;
+DROP VIEW IF EXISTS gitlab_merge_request_approvals;
+
+DROP TABLE IF EXISTS gitlab_groups_versioned;
+DROP TABLE IF EXISTS gitlab_users_versioned;
+DROP TABLE IF EXISTS gitlab_projects_versioned;
+DROP TABLE IF EXISTS gitlab_issues_versioned;
+DROP TABLE IF EXISTS gitlab_merge_requests_versioned;
+DROP TABLE IF EXISTS gitlab_notes_versioned;
+DROP TABLE IF EXISTS gitlab_merge_request_approvals_versioned;
+
+COMMIT;
@@ -0,0 +1,171 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gitlab_groups_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  avatar_url text,
+  created_at timestamptz,
+  description text,
+  full_name text,
+  full_path text NOT NULL,
+  htmlurl text,
+  id bigint,
+  name text,
+  parent_id bigint,
+  path text,
+  visibility text
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_groups_versions ON gitlab_groups_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitlab_users_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  access_level bigint,
+  avatar_url text,
+  group_full_path text NOT NULL,
+  group_id bigint NOT NULL,
+  htmlurl text,
+  id bigint,
+  name text,
+  state text,
+  username text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_users_versions ON gitlab_users_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitlab_projects_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  archived boolean,
+  created_at timestamptz,
+  default_branch text,
+  description text,
+  fork boolean,
+  forks_count bigint,
+  full_name text,
+  htmlurl text,
+  http_url text,
+  id bigint,
+  last_activity_at timestamptz,
+  name text,
+  namespace_full_path text NOT NULL,
+  namespace_id bigint,
+  namespace_kind text,
+  open_issues_count bigint,
+  path text NOT NULL,
+  sshurl text,
+  star_count bigint,
+  topics text[] NOT NULL,
+  visibility text
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_projects_versions ON gitlab_projects_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitlab_issues_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  assignees text[] NOT NULL,
+  body text,
+  closed_at timestamptz,
+  closed_by_id bigint,
+  closed_by_login text,
+  comments bigint,
+  confidential boolean,
+  created_at timestamptz,
+  htmlurl text,
+  id bigint,
+  iid bigint NOT NULL,
+  labels text[] NOT NULL,
+  locked boolean,
+  milestone_id bigint,
+  milestone_title text,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  title text,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_issues_versions ON gitlab_issues_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitlab_merge_requests_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  assignees text[] NOT NULL,
+  body text,
+  closed_at timestamptz,
+  comments bigint,
+  created_at timestamptz,
+  draft boolean,
+  htmlurl text,
+  id bigint,
+  iid bigint NOT NULL,
+  labels text[] NOT NULL,
+  merge_commit_sha text,
+  merge_status text,
+  merged_at timestamptz,
+  merged_by_id bigint,
+  merged_by_login text,
+  milestone_id bigint,
+  milestone_title text,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  sha text,
+  source_branch text,
+  source_project_id bigint,
+  state text,
+  target_branch text,
+  target_project_id bigint,
+  title text,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_merge_requests_versions ON gitlab_merge_requests_versioned (versions);
+
+/*
+The notes of issues and merge requests are stored in the same table,
+noteable_type is either Issue or MergeRequest
+*/
+CREATE TABLE IF NOT EXISTS gitlab_notes_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  body text,
+  created_at timestamptz,
+  id bigint,
+  noteable_iid bigint NOT NULL,
+  noteable_type text NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  system boolean,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_notes_versions ON gitlab_notes_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitlab_merge_request_approvals_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  merge_request_iid bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitlab_merge_request_approvals_versions ON gitlab_merge_request_approvals_versioned (versions);
+
+COMMIT;
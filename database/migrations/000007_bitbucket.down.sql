@@ -0,0 +1,22 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS comments;
+DROP MATERIALIZED VIEW IF EXISTS owners;
+DROP MATERIALIZED VIEW IF EXISTS repositories;
+DROP MATERIALIZED VIEW IF EXISTS pull_requests;
+DROP MATERIALIZED VIEW IF EXISTS pull_request_reviews;
+DROP MATERIALIZED VIEW IF EXISTS pull_request_comments;
+
+DROP VIEW IF EXISTS bitbucket_workspaces;
+DROP VIEW IF EXISTS bitbucket_repositories;
+DROP VIEW IF EXISTS bitbucket_pull_requests;
+DROP VIEW IF EXISTS bitbucket_pull_request_comments;
+DROP VIEW IF EXISTS bitbucket_pull_request_participants;
+
+DROP TABLE IF EXISTS bitbucket_workspaces_versioned;
+DROP TABLE IF EXISTS bitbucket_repositories_versioned;
+DROP TABLE IF EXISTS bitbucket_pull_requests_versioned;
+DROP TABLE IF EXISTS bitbucket_pull_request_comments_versioned;
+DROP TABLE IF EXISTS bitbucket_pull_request_participants_versioned;
+
+COMMIT;
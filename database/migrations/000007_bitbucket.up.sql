@@ -0,0 +1,120 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS bitbucket_workspaces_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  created_at timestamptz,
+  htmlurl text,
+  name text,
+  private boolean,
+  slug text NOT NULL,
+  uuid text
+);
+
+CREATE INDEX IF NOT EXISTS bitbucket_workspaces_versions ON bitbucket_workspaces_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS bitbucket_repositories_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  created_at timestamptz,
+  default_branch text,
+  description text,
+  fork_policy text,
+  full_name text,
+  has_issues boolean,
+  has_wiki boolean,
+  htmlurl text,
+  language text,
+  name text,
+  parent_full_name text,
+  private boolean,
+  project_key text,
+  project_name text,
+  size bigint,
+  slug text NOT NULL,
+  updated_at timestamptz,
+  uuid text,
+  workspace_slug text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS bitbucket_repositories_versions ON bitbucket_repositories_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS bitbucket_pull_requests_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  base_ref text,
+  base_repository_full_name text,
+  base_sha text,
+  body text,
+  close_source_branch boolean,
+  closed_by_login text,
+  closed_by_uuid text,
+  comments bigint,
+  created_at timestamptz,
+  head_ref text,
+  head_repository_full_name text,
+  head_sha text,
+  htmlurl text,
+  id bigint NOT NULL,
+  merge_commit_sha text,
+  reason text,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  task_count bigint,
+  title text,
+  updated_at timestamptz,
+  user_login text NOT NULL,
+  user_name text,
+  user_uuid text
+);
+
+CREATE INDEX IF NOT EXISTS bitbucket_pull_requests_versions ON bitbucket_pull_requests_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS bitbucket_pull_request_comments_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  body text,
+  created_at timestamptz,
+  deleted boolean,
+  htmlurl text,
+  id bigint,
+  in_reply_to bigint,
+  path text,
+  position bigint,
+  pull_request_id bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  updated_at timestamptz,
+  user_login text NOT NULL,
+  user_uuid text
+);
+
+CREATE INDEX IF NOT EXISTS bitbucket_pull_request_comments_versions ON bitbucket_pull_request_comments_versioned (versions);
+
+/*
+The participants are the reviewers and the users that approved or commented
+a pull request; state is approved, changes_requested or NULL
+*/
+CREATE TABLE IF NOT EXISTS bitbucket_pull_request_participants_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  approved boolean,
+  participated_at timestamptz,
+  pull_request_id bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  role text,
+  state text,
+  user_login text NOT NULL,
+  user_uuid text
+);
+
+CREATE INDEX IF NOT EXISTS bitbucket_pull_request_participants_versions ON bitbucket_pull_request_participants_versioned (versions);
+
+COMMIT;
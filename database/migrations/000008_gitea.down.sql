@@ -0,0 +1,33 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS comments;
+DROP MATERIALIZED VIEW IF EXISTS owners;
+DROP MATERIALIZED VIEW IF EXISTS users;
+DROP MATERIALIZED VIEW IF EXISTS repositories;
+DROP MATERIALIZED VIEW IF EXISTS issues;
+DROP MATERIALIZED VIEW IF EXISTS issue_comments;
+DROP MATERIALIZED VIEW IF EXISTS pull_requests;
+DROP MATERIALIZED VIEW IF EXISTS pull_request_reviews;
+DROP MATERIALIZED VIEW IF EXISTS pull_request_comments;
+
+DROP VIEW IF EXISTS gitea_organizations;
+DROP VIEW IF EXISTS gitea_users;
+DROP VIEW IF EXISTS gitea_repositories;
+DROP VIEW IF EXISTS gitea_labels;
+DROP VIEW IF EXISTS gitea_milestones;
+DROP VIEW IF EXISTS gitea_issues;
+DROP VIEW IF EXISTS gitea_issue_comments;
+DROP VIEW IF EXISTS gitea_pull_requests;
+DROP VIEW IF EXISTS gitea_pull_request_reviews;
+
+DROP TABLE IF EXISTS gitea_organizations_versioned;
+DROP TABLE IF EXISTS gitea_users_versioned;
+DROP TABLE IF EXISTS gitea_repositories_versioned;
+DROP TABLE IF EXISTS gitea_labels_versioned;
+DROP TABLE IF EXISTS gitea_milestones_versioned;
+DROP TABLE IF EXISTS gitea_issues_versioned;
+DROP TABLE IF EXISTS gitea_issue_comments_versioned;
+DROP TABLE IF EXISTS gitea_pull_requests_versioned;
+DROP TABLE IF EXISTS gitea_pull_request_reviews_versioned;
+
+COMMIT;
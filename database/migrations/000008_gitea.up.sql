@@ -0,0 +1,219 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gitea_organizations_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  avatar_url text,
+  description text,
+  email text,
+  id bigint,
+  location text,
+  login text NOT NULL,
+  name text,
+  visibility text,
+  website text
+);
+
+CREATE INDEX IF NOT EXISTS gitea_organizations_versions ON gitea_organizations_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_users_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  avatar_url text,
+  created_at timestamptz,
+  email text,
+  id bigint,
+  is_admin boolean,
+  location text,
+  login text NOT NULL,
+  name text,
+  organization_id bigint NOT NULL,
+  organization_login text NOT NULL,
+  website text
+);
+
+CREATE INDEX IF NOT EXISTS gitea_users_versions ON gitea_users_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_repositories_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  archived boolean,
+  clone_url text,
+  created_at timestamptz,
+  default_branch text,
+  description text,
+  fork boolean,
+  forks_count bigint,
+  full_name text,
+  has_issues boolean,
+  has_pull_requests boolean,
+  has_wiki boolean,
+  homepage text,
+  htmlurl text,
+  id bigint,
+  mirror boolean,
+  name text NOT NULL,
+  open_issues_count bigint,
+  open_pull_requests_count bigint,
+  owner_id bigint,
+  owner_login text NOT NULL,
+  private boolean,
+  sshurl text,
+  stargazers_count bigint,
+  topics text[] NOT NULL,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS gitea_repositories_versions ON gitea_repositories_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_labels_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  color text,
+  description text,
+  id bigint,
+  name text,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  url text
+);
+
+CREATE INDEX IF NOT EXISTS gitea_labels_versions ON gitea_labels_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_milestones_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  closed_at timestamptz,
+  closed_issues bigint,
+  created_at timestamptz,
+  description text,
+  due_on timestamptz,
+  id bigint,
+  open_issues bigint,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  title text,
+  updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS gitea_milestones_versions ON gitea_milestones_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_issues_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  assignees text[] NOT NULL,
+  body text,
+  closed_at timestamptz,
+  comments bigint,
+  created_at timestamptz,
+  htmlurl text,
+  id bigint,
+  labels text[] NOT NULL,
+  locked boolean,
+  milestone_id bigint,
+  milestone_title text,
+  number bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  title text,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitea_issues_versions ON gitea_issues_versioned (versions);
+
+/*
+The comments of issues and pull requests are stored in the same table, as
+in github_issue_comments_versioned
+*/
+CREATE TABLE IF NOT EXISTS gitea_issue_comments_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  body text,
+  created_at timestamptz,
+  htmlurl text,
+  id bigint,
+  issue_number bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitea_issue_comments_versions ON gitea_issue_comments_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_pull_requests_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  assignees text[] NOT NULL,
+  base_ref text,
+  base_repository_name text,
+  base_repository_owner text,
+  base_sha text,
+  body text,
+  closed_at timestamptz,
+  comments bigint,
+  created_at timestamptz,
+  head_ref text,
+  head_repository_name text,
+  head_repository_owner text,
+  head_sha text,
+  htmlurl text,
+  id bigint,
+  labels text[] NOT NULL,
+  locked boolean,
+  merge_commit_sha text,
+  mergeable boolean,
+  merged boolean,
+  merged_at timestamptz,
+  merged_by_id bigint,
+  merged_by_login text,
+  milestone_id bigint,
+  milestone_title text,
+  number bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  title text,
+  updated_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitea_pull_requests_versions ON gitea_pull_requests_versioned (versions);
+
+CREATE TABLE IF NOT EXISTS gitea_pull_request_reviews_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions integer ARRAY,
+
+  body text,
+  comments bigint,
+  commit_id text,
+  htmlurl text,
+  id bigint,
+  official boolean,
+  pull_request_number bigint NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  stale boolean,
+  state text,
+  submitted_at timestamptz,
+  user_id bigint NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS gitea_pull_request_reviews_versions ON gitea_pull_request_reviews_versioned (versions);
+
+COMMIT;
@@ -0,0 +1,267 @@
+// Package sqlite Code generated by go-bindata. (@generated) DO NOT EDIT.
+// sources:
+// database/sqlite/migrations/000001_init.down.sql
+// database/sqlite/migrations/000001_init.up.sql
+package sqlite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func bindataRead(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	clErr := gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %v", name, err)
+	}
+	if clErr != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type asset struct {
+	bytes []byte
+	info  os.FileInfo
+}
+
+type bindataFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// Name return file name
+func (fi bindataFileInfo) Name() string {
+	return fi.name
+}
+
+// Size return file size
+func (fi bindataFileInfo) Size() int64 {
+	return fi.size
+}
+
+// Mode return file mode
+func (fi bindataFileInfo) Mode() os.FileMode {
+	return fi.mode
+}
+
+// ModTime return file modify time
+func (fi bindataFileInfo) ModTime() time.Time {
+	return fi.modTime
+}
+
+// IsDir return file whether a directory
+func (fi bindataFileInfo) IsDir() bool {
+	return fi.mode&os.ModeDir != 0
+}
+
+// Sys return file is sys mode
+func (fi bindataFileInfo) Sys() interface{} {
+	return nil
+}
+
+var __000001_initDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x93\xc1\x4a\xc4\x40\x0c\x40\xef\x7e\x45\xbf\x61\xaf\x7b\x52\xac\x50\x10\x14\x77\x51\x6f\xa1\xdb\x0d\x6b\xa4\x9d\xd4\x64\xa6\x82\x5f\x2f\x75\xe9\x32\x78\x98\x4d\x7a\x7f\x6f\x12\x98\x97\xfb\x97\xa7\xe7\xea\xb5\xa9\xdf\xaa\xe6\xa1\xaa\xdf\x9b\xdd\x7e\x57\x9d\x28\x7e\xa4\x03\xb0\x9c\xda\x40\x3f\x6d\x24\x0e\xba\xbd\x29\x90\x49\x51\xca\x84\xe0\xc8\x4a\x91\x85\xb0\x0c\x92\x6a\xb2\x20\xd0\xf1\x30\x60\x88\x65\x74\x4c\x7d\x0f\x82\x5f\x09\xd5\x41\x82\xe0\x44\xf8\xed\x10\x4c\xcb\xcc\x10\x19\xd9\x51\xf8\x13\x3b\x1b\x04\x1d\xf7\x69\x08\x46\xb6\x95\xa3\x89\x54\x98\x36\xa6\x17\xa7\x0d\x50\xc4\xa1\xfc\x68\xc7\x21\x0a\x1d\x52\xe4\x39\x94\x33\xb9\xbf\xbd\x7b\xac\xaf\x64\x07\x13\x8a\x12\x07\x3c\x6e\x8b\xd2\x5f\x81\x56\x38\x8f\xd1\xea\x9c\xbb\x74\xd1\x97\x9f\xb6\x5a\x79\x52\xab\xa4\x25\xdc\x55\xae\x77\xdb\x7f\x39\x9b\x67\x5e\xf2\x72\xf1\x4b\xe4\x6e\x6d\xee\xdd\x29\xcd\xe9\x7b\xe7\x2c\x57\x60\xf5\xf2\x83\xc8\x9d\xdf\x01\x00\x02\xb2\x1f\x5f\x90\x05\x00\x00")
+
+func _000001_initDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000001_initDownSql,
+		"000001_init.down.sql",
+	)
+}
+
+func _000001_initDownSql() (*asset, error) {
+	bytes, err := _000001_initDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000001_init.down.sql", size: 1424, mode: os.FileMode(420), modTime: time.Unix(1792340838, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __000001_initUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x59\x51\x6f\xdb\x36\x10\x7e\xf7\xaf\xe0\x63\x0b\x38\x05\x16\x6c\x7d\xd9\x53\x37\x78\x40\xd6\xac\xed\x9a\x0c\x58\x31\x0c\xc4\x49\x3a\x49\x5c\x28\x52\x25\x8f\xf6\xdc\x5f\x3f\x50\xb6\x25\xd2\xa2\x6c\xa7\x8d\xd3\x06\xd8\x5b\xcc\x3b\x9e\x8e\xf7\x7d\xf7\x1d\x23\x5d\x5c\xb0\xdb\x1a\x19\x18\x03\x6b\xcb\x74\xc9\xa8\x46\xf6\x4e\x5b\xaa\x0c\xde\xfc\x7e\xcd\x6c\x5e\x63\x03\x0c\x0c\xb2\x5f\x6f\xde\xbe\xd9\x39\x5a\xd2\x06\x0b\x06\x96\x11\xfe\x4b\x73\xbf\x6b\x76\x71\xc1\x96\x68\xac\xd0\xca\x76\xfe\x77\xd8\x12\x5b\x09\xaa\x99\xd2\xcc\xb6\x90\xa3\x9d\x33\x7c\x51\xbd\x60\x7f\x7d\x37\xbf\xfc\x7b\xce\x48\xb3\x52\x48\x42\xe3\xb7\x37\x1b\xd7\xeb\xab\xd7\x8b\xd9\xec\xe7\xf7\x8b\x57\xb7\x0b\x76\xfb\xea\xa7\xeb\x05\xbb\xfa\x85\xbd\x79\x7b\xcb\x16\x7f\x5e\xdd\xdc\xde\xb0\x4a\x50\xed\x32\xae\x4d\x05\x4a\x7c\x02\xf2\x8f\xe3\xdb\xe7\x62\xc1\x9e\xcd\x18\xb3\xae\xb9\xfc\xe1\x25\xcb\x6b\x30\x90\xfb\xf0\x4b\x30\x6b\xa1\xaa\x67\x2f\xbf\x7f\xce\xde\xbd\xbf\xfa\xed\xd5\xfb\x0f\xec\xf5\xe2\xc3\x7c\xc6\x86\x8c\xfd\x39\xba\xe7\xbc\xf9\xe3\xfa\x7a\x3e\x9b\x31\x06\x4b\x20\x30\xdc\x19\xb9\x39\xe4\x8c\xb1\x5c\x4b\x09\x99\x36\x40\xda\x58\x26\x14\x61\x85\xc6\x87\xc9\x0d\x02\x61\xc1\x81\x18\x89\x06\x2d\x41\xd3\xfa\xf5\x02\x6d\x6e\x44\xeb\xb3\xec\x83\x60\x03\x62\x08\x59\x53\x23\xc3\x47\x88\x22\x8c\x2b\x75\x25\x86\x9d\x0a\x1a\x1c\x7e\xe8\x02\xb9\x28\xfa\xdf\x7a\xa5\xb0\xe0\xad\x11\x4b\x20\xe4\x06\x5b\x1d\x65\xd8\xba\x4c\x8a\x7c\xbc\x4e\x9a\x40\x4e\x6f\x73\x6d\x31\x3a\xd8\xec\xf9\x8f\xa7\x40\xe4\x2c\x9a\xc7\x83\x26\x13\xba\xff\x3b\xd7\x4d\x0b\x6a\x3d\xfc\x9e\x40\x27\x46\xa2\xd4\x52\xea\x15\xc6\xc0\x6e\x16\x85\xaa\xc2\xc5\x5a\x18\x84\x4c\x22\xcb\xb4\x96\x08\xea\x14\x1c\x73\x88\x48\x70\x1f\x60\x03\xaa\xf3\x21\x6c\x50\x91\x3d\x9f\x21\x76\xec\x73\x84\x20\x5b\x43\x25\x2c\xc5\x86\x0d\x73\xa6\xd6\x1f\x91\x51\xdd\xa3\x04\x69\x23\xf0\x5c\xc4\xf2\x68\xf3\x06\x4d\x85\x3c\xd7\x4d\x23\x28\xc4\x78\x63\x35\x98\x81\xc5\x8d\xd3\xd8\x6a\x3f\x3a\xb0\x75\xc2\x6a\xf2\x5a\x2c\xb1\x08\xd7\xa6\x55\xa3\x04\x27\x89\x67\x06\x54\x5e\xf7\x44\x48\x89\x49\x21\xac\x67\x62\x14\xb6\xd4\xe6\x6e\xff\xb7\xe5\xb9\x76\x8a\x42\x20\x4a\x27\x25\x8f\x98\x57\x83\xe5\xc2\x5a\x87\x36\xdc\xee\x57\x57\xe2\x4e\x44\x6b\xba\xc1\x16\x2a\x3c\x55\xc6\x40\x55\x2e\x74\x3f\x4c\xf8\x16\xd5\x36\x8f\x71\xd6\x5e\xe6\xcc\x64\x1b\x74\xc6\x03\xfc\x37\x9c\xd6\x2d\x8e\x6d\x5b\xb6\x86\x47\x6c\x9d\xad\x13\xd8\x58\x5b\x87\xe7\xb4\x04\xa6\x82\x4f\x68\x12\xa9\x92\x6e\x45\x3e\x62\x59\xba\x03\x7c\xac\x15\x50\x5e\x8f\x22\x9d\xd8\x1b\xdb\x7a\x9d\xa7\x2b\xac\x15\x95\x42\x4c\x9c\x25\xd3\x45\xa0\xb3\x52\xdb\xc4\xb9\xb6\xcb\xd9\x7a\x0a\xb6\xc1\x61\x02\x3a\xdf\x8a\xa8\x62\xf9\x99\x6a\x9e\x63\x4c\xcc\x50\x26\xce\x21\x75\x7e\x17\x77\x51\x23\x24\x5a\xd2\xaa\xa7\x66\xe4\x3f\x58\x49\x90\x4c\x30\x6a\x9f\xd4\xca\x35\x19\x9a\x30\x95\x5e\xcd\xd6\x43\x17\x46\x21\x02\x87\x8e\xf5\x63\x0f\x4b\x9e\xb4\xbb\x47\x0c\x99\x1c\x62\x99\xb3\xd3\xfd\xe3\x6c\xba\x7d\xee\xc3\x41\xbe\x03\xeb\x4c\x0a\xed\xa8\xd6\x86\x83\xb5\x3a\x17\xf1\x4c\x8d\xb9\xf8\x59\xf4\xe8\xba\x88\xc7\x58\x1d\x84\xf5\x01\x40\xfc\x0a\x40\xb5\x5e\xfa\x0d\x7e\x74\x68\xcf\x86\x53\x51\x08\x8f\x4e\xd4\xb3\x07\x84\xe4\x10\xae\x7e\xdc\x1a\x2c\xc7\x9b\xba\x41\x7c\x14\x82\x7d\xaf\x09\x1c\x3a\x37\x5b\xc3\x84\xc5\xd9\xe4\xa6\x88\x73\x35\xa8\x0a\x0b\x5e\x7a\x71\x08\xcf\xbd\x15\xb8\x7d\x84\x93\xb2\xd6\xdd\x3a\x4e\x52\xba\x02\x25\x8e\x4a\x5c\x23\x14\xe9\x62\x6d\x2d\x47\x8a\xb5\xef\x35\x51\xac\xce\x2d\x59\xac\xce\x92\x2e\xd6\xe7\x49\x73\x03\x42\x11\x08\x3f\xd9\x73\x50\xbc\xd1\x85\x28\xd7\x91\x52\x07\xd7\xb5\x3e\xa7\xde\xb0\x7f\x51\xef\x16\x63\xa5\xef\x56\x46\xe5\xdd\x2e\x4f\x8f\xad\xc1\x21\xd1\x89\x4f\x65\x84\x18\x5c\x0a\x5c\xf5\xa2\x1d\x46\xff\xc6\xa6\x4b\x28\x5a\x7c\x93\xf6\x99\xb4\x2b\x1e\x24\x1b\x5e\x05\x38\x1c\xa6\xf1\x3e\x6a\x51\xda\x07\x26\xcb\x83\x5f\x07\xac\xcb\x1a\x41\x5f\x13\xa4\x1d\xa9\xbe\xf2\x4d\x60\x04\xe0\xa4\xa0\x8a\xb2\xe4\xb5\x53\x77\x27\x42\x2d\x94\x57\x4a\xb9\xe6\xa4\x0f\x31\x40\x1b\x51\x09\x05\x92\x8f\x33\xe9\x4d\x1d\xf6\x7e\xe8\x05\x71\x5a\xa0\xe1\x9f\xbf\xa4\xc3\x89\xd4\x8a\x40\xd9\x36\x7c\x7c\x92\xa7\x79\x8d\xd9\xd6\xf3\xa9\xd1\xec\x9e\xfa\x71\x8c\x05\x4f\x13\xbb\xd6\xe8\x7f\x30\x3f\x17\x68\x31\x32\xdd\xed\x2b\x9c\xf8\x53\xf7\xb1\x09\xc0\x3a\x20\x75\xf2\xb4\x27\xe0\xb9\xc3\x24\x05\x6e\xdc\xb4\x47\x5f\x61\x1c\x85\x3a\x9e\x00\x29\x58\xef\x07\x0f\xcf\xb5\x74\xcd\xb9\xde\xb0\x7f\x66\x83\x1c\x2a\xe8\x2e\xef\x70\x3d\xaa\x50\xeb\x4c\xab\xed\xc3\xd6\x08\x4c\x71\xa6\x0a\x25\xdf\x1a\x76\x90\x4c\x9f\x30\xd7\x8a\x50\xd1\x09\x0e\xd3\x13\x63\xe7\x72\x94\x70\x09\xc7\x09\x8d\xd9\x79\xa6\x5f\xc0\x9d\xa7\xf5\x82\x0a\xf8\x60\x4a\x13\x9e\xce\x94\x87\xee\x25\xcb\x97\x97\x67\xea\xa3\x47\x16\xb8\x2f\x54\xb1\xcd\xa7\xa8\x30\x5d\x83\x50\x04\x4d\x7d\x94\x75\xb6\xd6\x86\x78\xea\x7d\xf8\xf1\xff\x52\xee\xd9\xdc\xcb\x4b\x2e\x08\x9b\x47\xed\xef\x6f\xbb\x7f\xfb\x0a\x9f\xbf\xbd\x4b\x81\xb2\xe0\x4b\x90\x2e\xf5\xca\x68\x9f\x86\x3b\xc8\x26\x0b\xd7\xe7\xf6\x85\xdc\xf0\xd5\x35\x22\x73\xa4\xff\xff\xc0\xf9\x10\x1f\x38\xbf\xed\x0f\x93\xff\x0d\x00\x50\x52\xac\xcc\x23\x21\x00\x00")
+
+func _000001_initUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000001_initUpSql,
+		"000001_init.up.sql",
+	)
+}
+
+func _000001_initUpSql() (*asset, error) {
+	bytes, err := _000001_initUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000001_init.up.sql", size: 8483, mode: os.FileMode(420), modTime: time.Unix(1792340838, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+// Asset loads and returns the asset for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func Asset(name string) ([]byte, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("Asset %s can't read by error: %v", name, err)
+		}
+		return a.bytes, nil
+	}
+	return nil, fmt.Errorf("Asset %s not found", name)
+}
+
+// MustAsset is like Asset but panics when Asset would return an error.
+// It simplifies safe initialization of global variables.
+func MustAsset(name string) []byte {
+	a, err := Asset(name)
+	if err != nil {
+		panic("asset: Asset(" + name + "): " + err.Error())
+	}
+
+	return a
+}
+
+// AssetInfo loads and returns the asset info for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func AssetInfo(name string) (os.FileInfo, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("AssetInfo %s can't read by error: %v", name, err)
+		}
+		return a.info, nil
+	}
+	return nil, fmt.Errorf("AssetInfo %s not found", name)
+}
+
+// AssetNames returns the names of the assets.
+func AssetNames() []string {
+	names := make([]string, 0, len(_bindata))
+	for name := range _bindata {
+		names = append(names, name)
+	}
+	return names
+}
+
+// _bindata is a table, holding each asset generator, mapped to its name.
+var _bindata = map[string]func() (*asset, error){
+	"000001_init.down.sql": _000001_initDownSql,
+	"000001_init.up.sql":   _000001_initUpSql,
+}
+
+// AssetDir returns the file names below a certain
+// directory embedded in the file by go-bindata.
+// For example if you run go-bindata on data/... and data contains the
+// following hierarchy:
+//     data/
+//       foo.txt
+//       img/
+//         a.png
+//         b.png
+// then AssetDir("data") would return []string{"foo.txt", "img"}
+// AssetDir("data/img") would return []string{"a.png", "b.png"}
+// AssetDir("foo.txt") and AssetDir("notexist") would return an error
+// AssetDir("") will return []string{"data"}.
+func AssetDir(name string) ([]string, error) {
+	node := _bintree
+	if len(name) != 0 {
+		cannonicalName := strings.Replace(name, "\\", "/", -1)
+		pathList := strings.Split(cannonicalName, "/")
+		for _, p := range pathList {
+			node = node.Children[p]
+			if node == nil {
+				return nil, fmt.Errorf("Asset %s not found", name)
+			}
+		}
+	}
+	if node.Func != nil {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(node.Children))
+	for childName := range node.Children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}
+
+type bintree struct {
+	Func     func() (*asset, error)
+	Children map[string]*bintree
+}
+
+var _bintree = &bintree{nil, map[string]*bintree{
+	"000001_init.down.sql": &bintree{_000001_initDownSql, map[string]*bintree{}},
+	"000001_init.up.sql":   &bintree{_000001_initUpSql, map[string]*bintree{}},
+}}
+
+// RestoreAsset restores an asset under the given directory
+func RestoreAsset(dir, name string) error {
+	data, err := Asset(name)
+	if err != nil {
+		return err
+	}
+	info, err := AssetInfo(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(_filePath(dir, filepath.Dir(name)), os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(_filePath(dir, name), data, info.Mode())
+	if err != nil {
+		return err
+	}
+	err = os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreAssets restores an asset under the given directory recursively
+func RestoreAssets(dir, name string) error {
+	children, err := AssetDir(name)
+	// File
+	if err != nil {
+		return RestoreAsset(dir, name)
+	}
+	// Dir
+	for _, child := range children {
+		err = RestoreAssets(dir, filepath.Join(name, child))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func _filePath(dir, name string) string {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
+}
@@ -0,0 +1,31 @@
+DROP VIEW IF EXISTS github_organizations;
+DROP VIEW IF EXISTS github_users;
+DROP VIEW IF EXISTS github_repositories;
+DROP VIEW IF EXISTS github_issues;
+DROP VIEW IF EXISTS github_issue_comments;
+DROP VIEW IF EXISTS github_pull_requests;
+DROP VIEW IF EXISTS github_pull_request_reviews;
+DROP VIEW IF EXISTS github_pull_request_comments;
+DROP VIEW IF EXISTS github_commit_comments;
+DROP VIEW IF EXISTS github_projects;
+DROP VIEW IF EXISTS github_project_columns;
+DROP VIEW IF EXISTS github_project_cards;
+DROP VIEW IF EXISTS github_projects_v2;
+DROP VIEW IF EXISTS github_project_v2_items;
+DROP VIEW IF EXISTS github_contributors;
+
+DROP TABLE IF EXISTS github_organizations_versioned;
+DROP TABLE IF EXISTS github_users_versioned;
+DROP TABLE IF EXISTS github_repositories_versioned;
+DROP TABLE IF EXISTS github_issues_versioned;
+DROP TABLE IF EXISTS github_issue_comments_versioned;
+DROP TABLE IF EXISTS github_pull_requests_versioned;
+DROP TABLE IF EXISTS github_pull_request_reviews_versioned;
+DROP TABLE IF EXISTS github_pull_request_comments_versioned;
+DROP TABLE IF EXISTS github_commit_comments_versioned;
+DROP TABLE IF EXISTS github_projects_versioned;
+DROP TABLE IF EXISTS github_project_columns_versioned;
+DROP TABLE IF EXISTS github_project_cards_versioned;
+DROP TABLE IF EXISTS github_projects_v2_versioned;
+DROP TABLE IF EXISTS github_project_v2_items_versioned;
+DROP TABLE IF EXISTS github_contributors_versioned;
@@ -0,0 +1,366 @@
+-- The arrays of the PostgreSQL schema are JSON arrays stored as text, the
+-- versions are kept with no spaces, e.g. [1,2], to filter them with LIKE
+
+CREATE TABLE IF NOT EXISTS github_organizations_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  avatar_url text,
+  collaborators integer,
+  created_at timestamp,
+  description text,
+  email text,
+  htmlurl text,
+  id integer,
+  login text,
+  name text,
+  node_id text,
+  owned_private_repos integer,
+  public_repos integer,
+  total_private_repos integer,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_users_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  avatar_url text,
+  bio text,
+  company text,
+  created_at timestamp,
+  email text,
+  followers integer,
+  following integer,
+  hireable boolean,
+  htmlurl text,
+  id integer,
+  location text,
+  login text,
+  name text,
+  node_id text,
+  organization_id integer NOT NULL,
+  organization_login text NOT NULL,
+  owned_private_repos integer,
+  private_gists integer,
+  public_gists integer,
+  public_repos integer,
+  total_private_repos integer,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_repositories_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  allow_merge_commit boolean,
+  allow_rebase_merge boolean,
+  allow_squash_merge boolean,
+  archived boolean,
+  created_at timestamp,
+  default_branch text,
+  description text,
+  disabled boolean,
+  fork boolean,
+  forks_count integer,
+  full_name text,
+  has_issues boolean,
+  has_wiki boolean,
+  homepage text,
+  htmlurl text,
+  id integer,
+  language text,
+  name text,
+  node_id text,
+  open_issues_count integer,
+  owner_id integer NOT NULL,
+  owner_login text NOT NULL,
+  owner_type text NOT NULL,
+  private boolean,
+  pushed_at timestamp,
+  sshurl text,
+  stargazers_count integer,
+  topics text NOT NULL,
+  updated_at timestamp,
+  watchers_count integer
+);
+
+CREATE TABLE IF NOT EXISTS github_issues_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  assignees text NOT NULL,
+  body text,
+  closed_at timestamp,
+  closed_by_id integer NOT NULL,
+  closed_by_login text NOT NULL,
+  comments integer,
+  created_at timestamp,
+  htmlurl text,
+  id integer,
+  labels text NOT NULL,
+  locked boolean,
+  milestone_id text NOT NULL,
+  milestone_title text NOT NULL,
+  node_id text,
+  number integer,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  title text,
+  updated_at timestamp,
+  user_id integer NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_issue_comments_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  author_association text,
+  body text,
+  created_at timestamp,
+  htmlurl text,
+  id integer,
+  issue_number integer NOT NULL,
+  node_id text,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  updated_at timestamp,
+  user_id integer NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_pull_requests_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  additions integer,
+  assignees text NOT NULL,
+  author_association text,
+  base_ref text NOT NULL,
+  base_repository_name text NOT NULL,
+  base_repository_owner text NOT NULL,
+  base_sha text NOT NULL,
+  base_user text NOT NULL,
+  body text,
+  changed_files integer,
+  closed_at timestamp,
+  comments integer,
+  commits integer,
+  created_at timestamp,
+  deletions integer,
+  head_ref text NOT NULL,
+  head_repository_name text NOT NULL,
+  head_repository_owner text NOT NULL,
+  head_sha text NOT NULL,
+  head_user text NOT NULL,
+  htmlurl text,
+  id integer,
+  labels text NOT NULL,
+  maintainer_can_modify boolean,
+  merge_commit_sha text,
+  mergeable boolean,
+  merged boolean,
+  merged_at timestamp,
+  merged_by_id integer NOT NULL,
+  merged_by_login text NOT NULL,
+  milestone_id text NOT NULL,
+  milestone_title text NOT NULL,
+  node_id text,
+  number integer,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  review_comments integer,
+  state text,
+  title text,
+  updated_at timestamp,
+  user_id integer NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_pull_request_reviews_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  body text,
+  commit_id text,
+  htmlurl text,
+  id integer,
+  node_id text,
+  pull_request_number integer NOT NULL,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  state text,
+  submitted_at timestamp,
+  user_id integer NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_pull_request_comments_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  author_association text,
+  body text,
+  commit_id text,
+  created_at timestamp,
+  diff_hunk text,
+  htmlurl text,
+  id integer,
+  in_reply_to integer,
+  node_id text,
+  original_commit_id text,
+  original_position integer,
+  path text,
+  position integer,
+  pull_request_number integer NOT NULL,
+  pull_request_review_id integer,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  updated_at timestamp,
+  user_id integer NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_commit_comments_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  author_association text,
+  body text,
+  commit_id text,
+  created_at timestamp,
+  htmlurl text,
+  id integer,
+  node_id text,
+  path text,
+  position integer,
+  repository_name text NOT NULL,
+  repository_owner text NOT NULL,
+  updated_at timestamp,
+  user_id integer NOT NULL,
+  user_login text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_projects_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  body text,
+  closed boolean,
+  closed_at timestamp,
+  created_at timestamp,
+  creator_login text NOT NULL,
+  htmlurl text,
+  id integer,
+  name text,
+  node_id text,
+  number integer,
+  owner_login text NOT NULL,
+  repository_name text NOT NULL,
+  state text,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_project_columns_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  created_at timestamp,
+  htmlurl text,
+  id integer,
+  name text,
+  node_id text,
+  project_node_id text NOT NULL,
+  purpose text,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_project_cards_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  archived boolean,
+  column_node_id text NOT NULL,
+  content_node_id text NOT NULL,
+  content_number integer NOT NULL,
+  content_repository_name text NOT NULL,
+  content_repository_owner text NOT NULL,
+  content_type text NOT NULL,
+  created_at timestamp,
+  creator_login text NOT NULL,
+  htmlurl text,
+  id integer,
+  node_id text,
+  note text,
+  project_node_id text NOT NULL,
+  state text,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_projects_v2_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  closed boolean,
+  closed_at timestamp,
+  created_at timestamp,
+  creator_login text NOT NULL,
+  htmlurl text,
+  node_id text,
+  number integer,
+  owner_login text NOT NULL,
+  public boolean,
+  readme text,
+  repository_name text NOT NULL,
+  short_description text,
+  title text,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_project_v2_items_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  archived boolean,
+  content_node_id text NOT NULL,
+  content_number integer NOT NULL,
+  content_repository_name text NOT NULL,
+  content_repository_owner text NOT NULL,
+  content_title text NOT NULL,
+  content_type text NOT NULL,
+  created_at timestamp,
+  creator_login text NOT NULL,
+  field_values text NOT NULL,
+  node_id text,
+  project_node_id text NOT NULL,
+  type text,
+  updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS github_contributors_versioned (
+  sum256 character varying(64) PRIMARY KEY,
+  versions text NOT NULL,
+
+  avatar_url text,
+  bio text,
+  company text,
+  created_at timestamp,
+  email text,
+  followers integer,
+  following integer,
+  hireable boolean,
+  htmlurl text,
+  id integer,
+  location text,
+  login text,
+  name text,
+  node_id text,
+  owned_private_repos integer,
+  private_gists integer,
+  public_gists integer,
+  public_repos integer,
+  total_private_repos integer,
+  updated_at timestamp
+);
@@ -0,0 +1,36 @@
+// Package sqlite contains the schema of the SQLite databases, an alternative
+// to PostgreSQL to keep the downloaded data in a single file without a server
+package sqlite
+
+import (
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Migrate updates the schema of the SQLite database in the given file to the
+// latest version, the file is created if it does not exist
+func Migrate(path string) error {
+	s := bindata.Resource(AssetNames(),
+		func(name string) ([]byte, error) {
+			return Asset(name)
+		})
+
+	d, err := bindata.WithInstance(s)
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.NewWithSourceInstance("go-bindata", d, "sqlite3://"+path)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	_, err = m.Close()
+	return err
+}
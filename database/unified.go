@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// unifiedViews are the materialized views that expose the data of all the
+// providers with a common schema
+var unifiedViews = []string{
+	"owners",
+	"users",
+	"repositories",
+	"issues",
+	"issue_comments",
+	"pull_requests",
+	"pull_request_reviews",
+	"pull_request_comments",
+	"comments",
+}
+
+// combinedViews are the queries of the unified views that read other unified
+// views, created before them in unifiedViews. Their rows are in the union
+// along with the ones of the providers
+var combinedViews = map[string]string{
+	// all the comments: the ones of issues and PRs, and the ones of the
+	// providers that are not part of the other views, like commit comments
+	"comments": `
+		SELECT repository_owner, repository_name, repository_full_name, 'issue' AS kind,
+			issue_number AS number, NULL::text AS commit_sha, NULL::text AS path, NULL::bigint AS position,
+			created_at, body, user_id, user_login, html_url
+		FROM issue_comments
+		UNION ALL
+		SELECT repository_owner, repository_name, repository_full_name, 'pull_request' AS kind,
+			pull_request_number AS number, NULL::text AS commit_sha, NULL::text AS path, NULL::bigint AS position,
+			created_at, body, user_id, user_login, html_url
+		FROM pull_request_comments`,
+}
+
+// providersViews contains, for each provider, the query returning its rows
+// for each unified view. The columns of every query must match the ones of
+// the other providers; a provider does not need to be part of every view
+var providersViews = []map[string]func(v int) string{
+	githubViews,
+	gitlabViews,
+	bitbucketViews,
+	giteaViews,
+}
+
+// UnifiedViewQuery returns the query of the given unified view for the
+// version v, it is the union of the rows of all the providers
+func UnifiedViewQuery(view string, v int) string {
+	var parts []string
+	if query, ok := combinedViews[view]; ok {
+		parts = append(parts, fmt.Sprintf("(%s)", query))
+	}
+
+	for _, views := range providersViews {
+		if query, ok := views[view]; ok {
+			parts = append(parts, fmt.Sprintf("(%s)", query(v)))
+		}
+	}
+
+	return strings.Join(parts, "\nUNION ALL\n")
+}
+
+// SetUnifiedViews recreates the unified views with the data of the version v
+func SetUnifiedViews(ctx context.Context, db *sql.DB, v int) error {
+	// the views are dropped before the ones they read
+	for i := len(unifiedViews) - 1; i >= 0; i-- {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s", unifiedViews[i]))
+		if err != nil {
+			return fmt.Errorf("failed to drop unified view %s: %v", unifiedViews[i], err)
+		}
+	}
+
+	for _, view := range unifiedViews {
+		_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE MATERIALIZED VIEW %s AS %s`, view, UnifiedViewQuery(view, v)))
+		if err != nil {
+			return fmt.Errorf("failed to create unified view %v: %v", view, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import "fmt"
+
+// bitbucketViews are the Bitbucket Cloud rows of the unified views. Workspaces
+// are owners, and the approvals of the participants are pull request reviews.
+// Bitbucket users do not have a numeric ID, and it does not report when a pull
+// request was closed or merged
+var bitbucketViews = map[string]func(v int) string{
+	"owners": func(v int) string {
+		return fmt.Sprintf(`SELECT slug AS login, name FROM bitbucket_workspaces_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"repositories": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT workspace_slug AS owner, slug AS name, full_name, private, description
+			FROM bitbucket_repositories_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_requests": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				id AS number,
+				CASE WHEN state = 'OPEN' THEN 'OPEN' WHEN state = 'MERGED' THEN 'MERGED' ELSE 'CLOSED' END AS state,
+				title, body, created_at, NULL::timestamptz AS closed_at, NULL::timestamptz AS merged_at, updated_at,
+				NULL::bigint AS commits, comments, NULL::bigint AS changed_files, NULL::bigint AS additions,
+				NULL::bigint AS deletions, NULL::bigint AS reviews,
+				NULL::bigint AS user_id, user_login,
+				split_part(base_repository_full_name, '/', 2) AS base_repository_name,
+				split_part(base_repository_full_name, '/', 1) AS base_repository_owner,
+				split_part(base_repository_full_name, '/', 2) || '/' || split_part(base_repository_full_name, '/', 1) AS base_repository_full_name,
+				head_ref, head_sha, merge_commit_sha, htmlurl AS html_url, '{}'::text[] AS labels
+			FROM bitbucket_pull_requests_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_reviews": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				pull_request_id AS pull_request_number, participated_at AS created_at, NULL::bigint AS user_id, user_login,
+				NULL::text AS html_url, CASE WHEN approved THEN 'APPROVED' ELSE 'COMMENTED' END AS state
+			FROM bitbucket_pull_request_participants_versioned
+			WHERE (approved OR state IS NOT NULL) AND %v = ANY(versions)`, v)
+	},
+	"pull_request_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				pull_request_id AS pull_request_number, created_at, body, NULL::bigint AS user_id, user_login, htmlurl AS html_url
+			FROM bitbucket_pull_request_comments_versioned
+			WHERE NOT deleted AND %v = ANY(versions)`, v)
+	},
+}
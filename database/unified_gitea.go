@@ -0,0 +1,72 @@
+package database
+
+import "fmt"
+
+// giteaViews are the Gitea rows of the unified views, its schema is close to
+// the GitHub one
+var giteaViews = map[string]func(v int) string{
+	"owners": func(v int) string {
+		return fmt.Sprintf(`SELECT login, name FROM gitea_organizations_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"users": func(v int) string {
+		return fmt.Sprintf(`SELECT login, name FROM gitea_users_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"repositories": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT owner_login AS owner, name, full_name, private, description
+			FROM gitea_repositories_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"issues": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				number, UPPER(state) AS state, title, body,
+				created_at, closed_at, updated_at, comments, user_id, user_login, htmlurl AS html_url, labels
+			FROM gitea_issues_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"issue_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+				c.issue_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM gitea_issue_comments_versioned AS c
+			JOIN gitea_issues_versioned AS i ON
+				i.repository_owner = c.repository_owner AND
+				i.repository_name = c.repository_name AND
+				i.number = c.issue_number
+			WHERE %v = ANY(c.versions) AND %v = ANY(i.versions)`, v, v)
+	},
+	"pull_requests": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				number, CASE WHEN merged THEN 'MERGED' ELSE UPPER(state) END AS state,
+				title, body, created_at, closed_at, merged_at, updated_at,
+				NULL::bigint AS commits, comments, NULL::bigint AS changed_files, NULL::bigint AS additions,
+				NULL::bigint AS deletions, NULL::bigint AS reviews,
+				user_id, user_login, base_repository_name, base_repository_owner,
+				base_repository_name || '/' || base_repository_owner AS base_repository_full_name,
+				head_ref, head_sha, merge_commit_sha, htmlurl AS html_url, labels
+			FROM gitea_pull_requests_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_reviews": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				pull_request_number, submitted_at as created_at, user_id, user_login, htmlurl AS html_url,
+				CASE WHEN state IN ('REQUEST_CHANGES', 'COMMENT') THEN 'COMMENTED' ELSE state END
+			FROM gitea_pull_request_reviews_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+				c.issue_number AS pull_request_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM gitea_issue_comments_versioned AS c
+			JOIN gitea_pull_requests_versioned AS p ON
+				p.repository_owner = c.repository_owner AND
+				p.repository_name = c.repository_name AND
+				p.number = c.issue_number
+			WHERE %v = ANY(c.versions) AND %v = ANY(p.versions)
+			UNION
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+				c.pull_request_number, c.submitted_at as created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM gitea_pull_request_reviews_versioned as c
+			WHERE c.body <> '' AND %v = ANY(c.versions)`, v, v, v)
+	},
+}
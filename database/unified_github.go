@@ -0,0 +1,83 @@
+package database
+
+import "fmt"
+
+// githubViews are the GitHub rows of the unified views
+var githubViews = map[string]func(v int) string{
+	"owners": func(v int) string {
+		return fmt.Sprintf(`SELECT login, name FROM github_organizations_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"users": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT login, name FROM github_users_versioned WHERE %v = ANY(versions)
+			UNION
+			SELECT login, name FROM github_contributors_versioned WHERE %v = ANY(versions)`, v, v)
+	},
+	"repositories": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT owner_login AS owner, name, full_name, private, description
+			FROM github_repositories_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"issues": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				number, state, title, body,
+				created_at, closed_at, updated_at, comments, user_id, user_login, htmlurl AS html_url, labels
+			FROM github_issues_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"issue_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+				c.issue_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM github_issue_comments_versioned AS c
+			JOIN github_issues_versioned AS i ON
+				i.repository_owner = c.repository_owner AND
+				i.repository_name = c.repository_name AND
+				i.number = c.issue_number WHERE %v = ANY(c.versions)`, v)
+	},
+	"pull_requests": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				number, state, title, body, created_at, closed_at, merged_at, updated_at,
+				commits, comments, changed_files, additions, deletions, review_comments AS reviews,
+				user_id, user_login, base_repository_name, base_repository_owner,
+				base_repository_name || '/' || base_repository_owner AS base_repository_full_name,
+				head_ref, head_sha, merge_commit_sha, htmlurl AS html_url, labels
+			FROM github_pull_requests_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_reviews": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				pull_request_number, submitted_at as created_at, user_id, user_login, htmlurl AS html_url,
+				CASE WHEN state = 'CHANGES_REQUESTED' THEN 'COMMENTED' ELSE state END
+			FROM github_pull_request_reviews_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+				issue_number AS pull_request_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM github_issue_comments_versioned AS c
+			JOIN github_pull_requests_versioned AS p ON
+				p.repository_owner = c.repository_owner AND
+				p.repository_name = c.repository_name AND
+				p.number = c.issue_number
+			WHERE %v = ANY(c.versions)
+			UNION
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+					c.pull_request_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM github_pull_request_comments_versioned as c
+			WHERE %v = ANY(c.versions)
+			UNION
+			SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+					c.pull_request_number, c.submitted_at as created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+			FROM github_pull_request_reviews_versioned as c
+			WHERE c.body <> '' AND %v = ANY(c.versions)`, v, v, v)
+	},
+	"comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				'commit' AS kind, NULL::bigint AS number, commit_id AS commit_sha, path, position,
+				created_at, body, user_id, user_login, htmlurl AS html_url
+			FROM github_commit_comments_versioned WHERE %v = ANY(versions)`, v)
+	},
+}
@@ -0,0 +1,70 @@
+package database
+
+import "fmt"
+
+// gitlabViews are the GitLab rows of the unified views. Groups are owners,
+// projects are repositories and merge requests are pull requests
+var gitlabViews = map[string]func(v int) string{
+	"owners": func(v int) string {
+		return fmt.Sprintf(`SELECT full_path AS login, name FROM gitlab_groups_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"users": func(v int) string {
+		return fmt.Sprintf(`SELECT username AS login, name FROM gitlab_users_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"repositories": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT namespace_full_path AS owner, path AS name, full_name, visibility <> 'public' AS private, description
+			FROM gitlab_projects_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"issues": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				iid AS number, CASE WHEN state = 'opened' THEN 'OPEN' ELSE 'CLOSED' END AS state, title, body,
+				created_at, closed_at, updated_at, comments, user_id, user_login, htmlurl AS html_url, labels
+			FROM gitlab_issues_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"issue_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT n.repository_owner, n.repository_name, n.repository_owner || '/' || n.repository_name AS repository_full_name,
+				n.noteable_iid AS issue_number, n.created_at, n.body, n.user_id, n.user_login,
+				i.htmlurl || '#note_' || n.id AS html_url
+			FROM gitlab_notes_versioned AS n
+			JOIN gitlab_issues_versioned AS i ON
+				i.repository_owner = n.repository_owner AND
+				i.repository_name = n.repository_name AND
+				i.iid = n.noteable_iid
+			WHERE n.noteable_type = 'Issue' AND NOT n.system AND %v = ANY(n.versions) AND %v = ANY(i.versions)`, v, v)
+	},
+	"pull_requests": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				iid AS number,
+				CASE WHEN state = 'opened' THEN 'OPEN' WHEN state = 'merged' THEN 'MERGED' ELSE 'CLOSED' END AS state,
+				title, body, created_at, closed_at, merged_at, updated_at,
+				NULL::bigint AS commits, comments, NULL::bigint AS changed_files, NULL::bigint AS additions,
+				NULL::bigint AS deletions, NULL::bigint AS reviews,
+				user_id, user_login, repository_name AS base_repository_name, repository_owner AS base_repository_owner,
+				repository_name || '/' || repository_owner AS base_repository_full_name,
+				source_branch AS head_ref, sha AS head_sha, merge_commit_sha, htmlurl AS html_url, labels
+			FROM gitlab_merge_requests_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_reviews": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+				merge_request_iid AS pull_request_number, NULL::timestamptz AS created_at, user_id, user_login,
+				NULL::text AS html_url, 'APPROVED' AS state
+			FROM gitlab_merge_request_approvals_versioned WHERE %v = ANY(versions)`, v)
+	},
+	"pull_request_comments": func(v int) string {
+		return fmt.Sprintf(`
+			SELECT n.repository_owner, n.repository_name, n.repository_owner || '/' || n.repository_name AS repository_full_name,
+				n.noteable_iid AS pull_request_number, n.created_at, n.body, n.user_id, n.user_login,
+				m.htmlurl || '#note_' || n.id AS html_url
+			FROM gitlab_notes_versioned AS n
+			JOIN gitlab_merge_requests_versioned AS m ON
+				m.repository_owner = n.repository_owner AND
+				m.repository_name = n.repository_name AND
+				m.iid = n.noteable_iid
+			WHERE n.noteable_type = 'MergeRequest' AND NOT n.system AND %v = ANY(n.versions) AND %v = ANY(m.versions)`, v, v)
+	},
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// The versioned tables of every provider have the columns sum256, the hash
+// identifying a row, and versions, the versions the row belongs to. A view
+// named after each table, without the _versioned suffix, exposes the rows of
+// the active version
+
+// UpsertVersioned returns the statement that inserts a row in the versioned
+// table, with the arguments sum256, versions and the given columns, or appends
+// the version in the extra last argument to the row with the same sum256. The
+// version is not appended again to a row that already has it, like one saved
+// by two downloads of the same version
+func UpsertVersioned(table, cols string) string {
+	n := strings.Count(cols, ",") + 3
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	return fmt.Sprintf(`INSERT INTO %[1]s
+		(sum256, versions, %[2]s)
+		VALUES (%[3]s)
+		ON CONFLICT (sum256)
+		DO UPDATE
+		SET versions = array_append(%[1]s.versions, $%[4]d::integer)
+		WHERE $%[4]d::integer <> ALL(%[1]s.versions)`,
+		table, cols, strings.Join(placeholders, ", "), n+1)
+}
+
+// InsertVersioned stores the given values of a row in a versioned table for
+// the version v; the row is identified by the sha256 of its values, so an
+// unchanged row is not duplicated in a new version
+func InsertVersioned(ctx context.Context, tx *sql.Tx, v int, table string, cols string, values ...interface{}) error {
+	// the values are encoded as JSON, the pointers are dereferenced
+	st, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(st)
+	args := append([]interface{}{fmt.Sprintf("%x", hash), pq.Array([]int{v})}, values...)
+	_, err = tx.ExecContext(ctx, UpsertVersioned(table, cols), append(args, v)...)
+	return err
+}
+
+// SetVersionedViews recreates the view of each versioned table with its rows
+// of the version v
+func SetVersionedViews(ctx context.Context, db *sql.DB, tables []string, v int) error {
+	for _, table := range tables {
+		var cols string
+		viewName := strings.Replace(table, "_versioned", "", 1)
+		err := db.QueryRowContext(ctx, `SELECT STRING_AGG(column_name, ', ') as cols
+			FROM information_schema.columns
+			WHERE table_name = $1
+			AND table_schema = 'public'
+			AND column_name NOT IN ('sum256', 'versions')`, table).Scan(&cols)
+		if err != nil {
+			return fmt.Errorf("failed to get columns for %s view: %v", viewName, err)
+		}
+
+		_, err = db.ExecContext(ctx, fmt.Sprintf(`CREATE OR REPLACE VIEW %s AS
+		SELECT %s
+		FROM %s WHERE %v = ANY(versions)`, viewName, cols, table, v))
+		if err != nil {
+			return fmt.Errorf("failed to create VIEW %s: %v", viewName, err)
+		}
+	}
+
+	return nil
+}
+
+// CleanupVersioned deletes from the versioned tables all the rows that do not
+// belong to currentVersion
+func CleanupVersioned(ctx context.Context, db *sql.DB, tables []string, currentVersion int) error {
+	for _, table := range tables {
+		// Delete all entries that do not belong to currentVersion
+		_, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %v <> ALL(versions)`, table, currentVersion))
+		if err != nil {
+			return fmt.Errorf("failed in cleanup method, delete: %v", err)
+		}
+
+		// All remaining entries belong to currentVersion, replace the list of versions
+		// with an array of 1 entry
+		_, err = db.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET versions = array[%v]`, table, currentVersion))
+		if err != nil {
+			return fmt.Errorf("failed in cleanup method, update: %v", err)
+		}
+	}
+
+	return nil
+}
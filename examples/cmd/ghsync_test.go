@@ -0,0 +1,118 @@
+package main
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github/chaos"
+	"github.com/src-d/metadata-retrieval/github/fakeserver"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// ghsync runs the ghsync command for the organizations of the server, with
+// the given transport in front of it, and returns the saved data
+func ghsync(require *require.Assertions, server *fakeserver.Server, wrap func(rt http.RoundTripper) http.RoundTripper) []string {
+	storer := &testutils.Snapshot{}
+	c := &Ghsync{Orgs: "acme"}
+	c.Tokens = []string{"token"}
+	c.Version = 1
+	c.Concurrency = 1
+	c.MaxRetries = 10
+	c.RetryMaxElapsed = time.Minute
+	c.transport = wrap(server.Client().Transport)
+	c.storer = storer
+
+	// the command logs with the default logger, created by the cli package
+	if log.DefaultLogger == nil {
+		log.DefaultLogger = log.New(nil)
+	}
+
+	require.NoError(c.Execute(nil))
+	return storer.Lines()
+}
+
+func TestGhsyncChaos(t *testing.T) {
+	require := require.New(t)
+
+	size := fakeserver.DefaultSize
+	size.Repositories = 3
+	server := fakeserver.New(fakeserver.Seed(5, "acme", size))
+	defer server.Close()
+
+	clean := ghsync(require, server, func(rt http.RoundTripper) http.RoundTripper { return rt })
+	require.NotEmpty(clean)
+
+	// the command waits a minute after the abuse responses without
+	// Retry-After, they are not injected
+	kinds := []chaos.Fault{chaos.Latency, chaos.ConnectionReset, chaos.TruncatedBody, chaos.BadGateway, chaos.Abuse, chaos.RateLimit}
+	var faults *chaos.Transport
+	lines := ghsync(require, server, func(rt http.RoundTripper) http.RoundTripper {
+		faults = chaos.NewTransport(rt, chaos.Schedule{
+			Seed:           1,
+			Rate:           0.1,
+			Faults:         kinds,
+			MaxConsecutive: 2,
+			Latency:        20 * time.Millisecond,
+		})
+
+		return faults
+	})
+
+	require.Equal(clean, lines)
+	for _, f := range kinds {
+		require.True(faults.Injected()[f] > 0, "%s was not injected", f)
+	}
+}
+
+func TestGhsyncSQLite(t *testing.T) {
+	require := require.New(t)
+
+	size := fakeserver.DefaultSize
+	size.Repositories = 3
+	server := fakeserver.New(fakeserver.Seed(5, "acme", size))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ghsync-sqlite")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	if log.DefaultLogger == nil {
+		log.DefaultLogger = log.New(nil)
+	}
+
+	path := filepath.Join(dir, "ghsync.db")
+	for _, v := range []int{1, 2} {
+		c := &Ghsync{Orgs: "acme"}
+		c.DB = "sqlite://" + path
+		c.Tokens = []string{"token"}
+		c.Version = v
+		c.Cleanup = true
+		c.Concurrency = 1
+		c.transport = server.Client().Transport
+		require.NoError(c.Execute(nil), "version %d", v)
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(err)
+	defer db.Close()
+
+	var login string
+	require.NoError(db.QueryRow("SELECT login FROM github_organizations").Scan(&login))
+	require.Equal("acme", login)
+
+	var repositories, versions int
+	require.NoError(db.QueryRow("SELECT COUNT(*) FROM github_repositories").Scan(&repositories))
+	require.Equal(3, repositories)
+
+	// the rows of the first version were cleaned up
+	require.NoError(db.QueryRow("SELECT COUNT(*) FROM github_repositories_versioned WHERE versions <> '[2]'").Scan(&versions))
+	require.Equal(0, versions)
+}
@@ -22,8 +22,12 @@ type logTransport struct {
 func (t *logTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	t0 := time.Now()
 
-	reqBody, _ := ioutil.ReadAll(r.Body)
-	r.Body = ioutil.NopCloser(bytes.NewBuffer(reqBody))
+	// the REST requests of the other providers are GETs without body
+	var reqBody []byte
+	if r.Body != nil {
+		reqBody, _ = ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewBuffer(reqBody))
+	}
 
 	resp, err := t.T.RoundTrip(r)
 	if err != nil {
@@ -3,19 +3,26 @@ package main
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 
-	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/provider"
 )
 
+// DownloadersPool hands out its Downloaders to the concurrent jobs, choosing
+// the one whose token has the most remaining requests
 type DownloadersPool struct {
-	Size    int
-	pool    chan *github.Downloader
+	Size int
+	all  []provider.Downloader
+	// pool hands out each Downloader to one job at a time, also the ones of
+	// the owners
+	pool *scheduler
+	// owners, when set, restricts each owner to its own Downloader
+	owners  map[string]provider.Downloader
 	started bool
 	ended   bool
 	t0      time.Time
-	stats0  map[*github.Downloader]*downloaderStats
+	stats0  map[provider.Downloader]*downloaderStats
 }
 
 type DownloaderPoolStats struct {
@@ -28,31 +35,64 @@ type downloaderStats struct {
 	Time time.Time
 }
 
-type downloaderBuilder = func(c *http.Client) (*github.Downloader, error)
+func NewDownloadersPool(downloaders []provider.Downloader) (*DownloadersPool, error) {
+	return &DownloadersPool{
+		Size: len(downloaders),
+		all:  downloaders,
+		pool: newScheduler(downloaders),
+	}, nil
+}
 
-func NewDownloadersPool(downloaders []*github.Downloader) (*DownloadersPool, error) {
-	ch := make(chan *github.Downloader, len(downloaders))
+// NewOwnersDownloadersPool returns a pool where each owner (user or
+// organization login) can only be downloaded with its own Downloader, like
+// the ones authenticated as a GitHub App installation
+func NewOwnersDownloadersPool(downloaders map[string]provider.Downloader) (*DownloadersPool, error) {
+	var all []provider.Downloader
+	owners := make(map[string]provider.Downloader)
+	for owner, d := range downloaders {
+		owners[strings.ToLower(owner)] = d
+		all = append(all, d)
+	}
 
-	for _, d := range downloaders {
-		ch <- d
+	dp, err := NewDownloadersPool(all)
+	if err != nil {
+		return nil, err
 	}
 
-	return &DownloadersPool{
-		Size: len(downloaders),
-		pool: ch,
-	}, nil
+	dp.owners = owners
+	return dp, nil
+}
+
+func (dp *DownloadersPool) WithDownloader(f func(d provider.Downloader) error) error {
+	return dp.withDownloader(nil, f)
 }
 
-func (dp *DownloadersPool) WithDownloader(f func(d *github.Downloader) error) error {
+// WithOwnerDownloader is like WithDownloader, but it uses the Downloader of
+// the owner if the pool was created with NewOwnersDownloadersPool. It waits
+// until the Downloader is not used by another job
+func (dp *DownloadersPool) WithOwnerDownloader(owner string, f func(d provider.Downloader) error) error {
+	if dp.owners == nil {
+		return dp.withDownloader(nil, f)
+	}
+
+	owned, ok := dp.owners[strings.ToLower(owner)]
+	if !ok {
+		return fmt.Errorf("there is no downloader for %s", owner)
+	}
+
+	return dp.withDownloader(func(d provider.Downloader) bool { return d == owned }, f)
+}
+
+// withDownloader calls f with an idle Downloader of the pool, among the ones
+// for which match returns true, any of them when it is nil
+func (dp *DownloadersPool) withDownloader(match func(provider.Downloader) bool, f func(d provider.Downloader) error) error {
 	if !dp.started || dp.ended {
 		return fmt.Errorf("invalid state: started=%v, ended=%v",
 			dp.started, dp.ended)
 	}
 
-	item := <-dp.pool
-	defer func() {
-		dp.pool <- item
-	}()
+	item := dp.pool.acquireMatching(match)
+	defer dp.pool.release(item)
 
 	return f(item)
 }
@@ -95,7 +135,7 @@ func (dp *DownloadersPool) End(ctx context.Context) (*DownloaderPoolStats, error
 //
 // NB: this return incorrect result for api usage if a rate reset occurs between
 // `Begin()` and `End()`.
-func (dp *DownloadersPool) calculateStats(t1 time.Time, stats1 map[*github.Downloader]*downloaderStats) (*DownloaderPoolStats, error) {
+func (dp *DownloadersPool) calculateStats(t1 time.Time, stats1 map[provider.Downloader]*downloaderStats) (*DownloaderPoolStats, error) {
 	var rateUsages []*RateUsage
 
 	elapsed := t1.Sub(dp.t0)
@@ -105,6 +145,10 @@ func (dp *DownloadersPool) calculateStats(t1 time.Time, stats1 map[*github.Downl
 			return nil, fmt.Errorf("cannot find stats for downloader")
 		}
 
+		if s0.Rate == provider.UnknownRate || s1.Rate == provider.UnknownRate {
+			continue
+		}
+
 		used := s0.Rate - s1.Rate
 		rateUsages = append(rateUsages, &RateUsage{
 			Used:  used,
@@ -118,28 +162,23 @@ func (dp *DownloadersPool) calculateStats(t1 time.Time, stats1 map[*github.Downl
 	}, nil
 }
 
-func (dp *DownloadersPool) stats(ctx context.Context) (map[*github.Downloader]*downloaderStats, error) {
-	stats := make(map[*github.Downloader]*downloaderStats)
-	for i := 0; i < dp.Size; i++ {
-		err := dp.WithDownloader(func(d *github.Downloader) error {
-			dStats, err := dp.singleStats(ctx, d)
-			if err != nil {
-				return err
-			}
-
-			stats[d] = dStats
-			return nil
-		})
-
+// stats returns the stats of all the Downloaders, it must not be called while
+// they are in use
+func (dp *DownloadersPool) stats(ctx context.Context) (map[provider.Downloader]*downloaderStats, error) {
+	stats := make(map[provider.Downloader]*downloaderStats)
+	for _, d := range dp.all {
+		dStats, err := dp.singleStats(ctx, d)
 		if err != nil {
 			return nil, err
 		}
+
+		stats[d] = dStats
 	}
 
 	return stats, nil
 }
 
-func (dp *DownloadersPool) singleStats(ctx context.Context, d *github.Downloader) (*downloaderStats, error) {
+func (dp *DownloadersPool) singleStats(ctx context.Context, d provider.Downloader) (*downloaderStats, error) {
 	rate, err := d.RateRemaining(ctx)
 	if err != nil {
 		return nil, err
@@ -155,3 +194,28 @@ type RateUsage struct {
 	Used  int
 	Speed float64
 }
+
+// ProvidersPools contains a DownloadersPool for each provider, so each one
+// uses its own tokens
+type ProvidersPools map[string]*DownloadersPool
+
+// Size returns the number of Downloaders of all the pools
+func (pp ProvidersPools) Size() int {
+	var size int
+	for _, dp := range pp {
+		size += dp.Size
+	}
+
+	return size
+}
+
+// WithOwnerDownloader calls WithOwnerDownloader on the pool of the given
+// provider
+func (pp ProvidersPools) WithOwnerDownloader(providerName string, owner string, f func(d provider.Downloader) error) error {
+	dp, ok := pp[providerName]
+	if !ok {
+		return fmt.Errorf("there is no downloader for provider %s", providerName)
+	}
+
+	return dp.WithOwnerDownloader(owner, f)
+}
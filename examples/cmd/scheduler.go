@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/provider"
+)
+
+// rateStatuser is implemented by the Downloaders that know the rate limit
+// status of their token
+type rateStatuser interface {
+	RateStatus() github.RateStatus
+}
+
+// rateDownloader is a Downloader with the RateLimitTransport of its HTTP
+// client, that captures the rate limit status of its token
+type rateDownloader struct {
+	provider.Downloader
+	transport *github.RateLimitTransport
+}
+
+// RateStatus returns the status of the GraphQL rate limit, the one the
+// downloads spend. The REST API requests have their own "core" rate limit
+func (d rateDownloader) RateStatus() github.RateStatus {
+	return d.transport.ResourceRateStatus(github.DefaultRateResource)
+}
+
+// unknownHeadroom is the headroom of the Downloaders without a known rate
+// limit status, like the ones not used yet or after their reset: they are
+// preferred over the ones that already spent part of their quota
+const unknownHeadroom = math.MaxInt32
+
+// scheduler hands out the idle Downloader with the most remaining requests.
+// The Downloaders that exhausted their quota are parked until their reset
+type scheduler struct {
+	mu   sync.Mutex
+	idle []provider.Downloader
+	// released is closed, and replaced, every time a Downloader is released
+	released chan struct{}
+	now      func() time.Time
+}
+
+func newScheduler(downloaders []provider.Downloader) *scheduler {
+	idle := make([]provider.Downloader, len(downloaders))
+	copy(idle, downloaders)
+
+	return &scheduler{
+		idle:     idle,
+		released: make(chan struct{}),
+		now:      time.Now,
+	}
+}
+
+// acquire blocks until there is an idle Downloader that is not parked, and
+// returns the one with the best headroom. The Downloaders with the same
+// headroom are handed out in FIFO order
+func (s *scheduler) acquire() provider.Downloader {
+	return s.acquireMatching(nil)
+}
+
+// acquireMatching is like acquire, but it only hands out the Downloaders for
+// which match returns true, any of them when it is nil
+func (s *scheduler) acquireMatching(match func(provider.Downloader) bool) provider.Downloader {
+	for {
+		s.mu.Lock()
+		i, wait := s.best(match)
+		if i >= 0 {
+			d := s.idle[i]
+			s.idle = append(s.idle[:i], s.idle[i+1:]...)
+			s.mu.Unlock()
+			return d
+		}
+
+		released := s.released
+		s.mu.Unlock()
+
+		if wait <= 0 {
+			<-released
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-released:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// release returns a Downloader to the idle ones
+func (s *scheduler) release(d provider.Downloader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idle = append(s.idle, d)
+	close(s.released)
+	s.released = make(chan struct{})
+}
+
+// best returns the index of the matching idle Downloader with the best
+// headroom of their GraphQL rate limit, or -1 if all of them are parked, with
+// the time until the first reset. The wait is 0 if there is no matching idle
+// Downloader
+func (s *scheduler) best(match func(provider.Downloader) bool) (index int, wait time.Duration) {
+	now := s.now()
+	index, best := -1, 0
+	for i, d := range s.idle {
+		if match != nil && !match(d) {
+			continue
+		}
+
+		headroom := unknownHeadroom
+		if r, ok := d.(rateStatuser); ok {
+			status := r.RateStatus()
+			if remaining, known := status.Headroom(now); known {
+				if remaining <= 0 {
+					untilReset := status.Reset.Sub(now)
+					if wait == 0 || untilReset < wait {
+						wait = untilReset
+					}
+
+					continue
+				}
+
+				headroom = remaining
+			}
+		}
+
+		if index < 0 || headroom > best {
+			index, best = i, headroom
+		}
+	}
+
+	return index, wait
+}
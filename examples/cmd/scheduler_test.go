@@ -0,0 +1,177 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/provider"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// statusDownloader is a Downloader with a fixed rate limit status
+type statusDownloader struct {
+	provider.Downloader
+	name   string
+	status github.RateStatus
+}
+
+func (d *statusDownloader) RateStatus() github.RateStatus {
+	return d.status
+}
+
+func newStatusDownloader(name string, remaining int, reset time.Time) *statusDownloader {
+	return &statusDownloader{
+		name:   name,
+		status: github.RateStatus{Known: true, Remaining: remaining, Reset: reset},
+	}
+}
+
+func downloaderName(d provider.Downloader) string {
+	return d.(*statusDownloader).name
+}
+
+func TestSchedulerBestHeadroom(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(time.Hour)
+	low := newStatusDownloader("low", 10, reset)
+	high := newStatusDownloader("high", 4000, reset)
+	exhausted := newStatusDownloader("exhausted", 0, reset)
+	s := newScheduler([]provider.Downloader{low, exhausted, high})
+
+	d1 := s.acquire()
+	require.Equal("high", downloaderName(d1))
+	d2 := s.acquire()
+	require.Equal("low", downloaderName(d2))
+
+	// the one with the most requests is handed out again once released
+	s.release(d2)
+	s.release(d1)
+	require.Equal("high", downloaderName(s.acquire()))
+}
+
+func TestSchedulerUnknownStatusFIFO(t *testing.T) {
+	require := require.New(t)
+
+	// a status after its reset is unknown, like the one of an unused token
+	past := time.Now().Add(-time.Minute)
+	a := newStatusDownloader("a", 0, past)
+	b := &statusDownloader{name: "b"}
+	c := newStatusDownloader("c", 4000, time.Now().Add(time.Hour))
+	s := newScheduler([]provider.Downloader{a, b, c})
+
+	require.Equal("a", downloaderName(s.acquire()))
+	require.Equal("b", downloaderName(s.acquire()))
+	require.Equal("c", downloaderName(s.acquire()))
+}
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+// newRateDownloader returns a rateDownloader whose transport got a response
+// with the given remaining requests for each rate limit resource
+func newRateDownloader(t *testing.T, remaining map[string]int, reset time.Time) rateDownloader {
+	transport := github.NewRateLimitTransport(roundTripFunc(func(req *http.Request) *http.Response {
+		resource := req.URL.Path[1:]
+		header := http.Header{}
+		header.Set("X-RateLimit-Resource", resource)
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining[resource]))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: ioutil.NopCloser(strings.NewReader("{}"))}
+	}), &testutils.LoggerMock{})
+
+	for resource := range remaining {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/"+resource, nil)
+		require.NoError(t, err)
+		// an exhausted rate limit is returned as an error, it is expected
+		_, _ = transport.RoundTrip(req)
+	}
+
+	return rateDownloader{Downloader: &statusDownloader{name: "rate"}, transport: transport}
+}
+
+func TestSchedulerGraphQLRateStatus(t *testing.T) {
+	require := require.New(t)
+
+	// the REST API rate limit is exhausted, but not the GraphQL one
+	reset := time.Now().Add(time.Hour)
+	core := newRateDownloader(t, map[string]int{"core": 0, "graphql": 4000}, reset)
+	graphql := newRateDownloader(t, map[string]int{"core": 5000, "graphql": 10}, reset)
+	require.Equal(4000, core.RateStatus().Remaining)
+
+	s := newScheduler([]provider.Downloader{graphql, core})
+	require.Equal(core, s.acquire())
+	require.Equal(graphql, s.acquire())
+}
+
+func TestSchedulerParked(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(100 * time.Millisecond)
+	exhausted := newStatusDownloader("exhausted", 0, reset)
+	s := newScheduler([]provider.Downloader{exhausted})
+
+	t0 := time.Now()
+	require.Equal("exhausted", downloaderName(s.acquire()))
+	require.True(time.Since(t0) >= 100*time.Millisecond, "the downloader should be parked until its reset")
+}
+
+func TestSchedulerWaitRelease(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(time.Hour)
+	busy := newStatusDownloader("busy", 10, reset)
+	exhausted := newStatusDownloader("exhausted", 0, reset)
+	s := newScheduler([]provider.Downloader{busy, exhausted})
+
+	d := s.acquire()
+	require.Equal("busy", downloaderName(d))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.release(d)
+	}()
+
+	require.Equal("busy", downloaderName(s.acquire()))
+}
+
+func TestOwnersPoolLease(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(time.Hour)
+	acme := newStatusDownloader("acme", 4000, reset)
+	other := newStatusDownloader("other", 10, reset)
+	dp, err := NewOwnersDownloadersPool(map[string]provider.Downloader{"acme": acme, "other": other})
+	require.NoError(err)
+	dp.started = true
+
+	// the Downloader of the owner is not handed out while another job uses it
+	owned := make(chan string)
+	require.NoError(dp.WithDownloader(func(d provider.Downloader) error {
+		require.Equal("acme", downloaderName(d))
+		go dp.WithOwnerDownloader("ACME", func(d provider.Downloader) error {
+			owned <- downloaderName(d)
+			return nil
+		})
+
+		select {
+		case name := <-owned:
+			t.Fatalf("%s was handed out twice", name)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		return nil
+	}))
+
+	require.Equal("acme", <-owned)
+}
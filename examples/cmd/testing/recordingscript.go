@@ -1,10 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
-	"encoding/gob"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -12,19 +9,19 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/github/recorder"
 	"github.com/src-d/metadata-retrieval/testutils"
 
-	"github.com/motemen/go-loghttp"
 	"golang.org/x/oauth2"
 	"gopkg.in/src-d/go-log.v1"
 )
 
 // This script will use the downloader to crawl using the graphql API a repository (default: src-d/gitbase)
-// and an organization (default: src-d) and will record requests (graphql queries) and responses (json body data)
-// into a map stored in a gob file.
+// and an organization (default: src-d) and will record the requests and responses, including the failed
+// ones, into cassettes replayed by the tests, see the recorder package, along with the test oracles.
+// The fixtures are refreshed with `make record-fixtures`.
 // NB: script is assumed to run from the project root (for correct file storage)
 func main() {
 	var (
@@ -35,46 +32,27 @@ func main() {
 	flag.StringVar(&repo, "repo", "gitbase", "a GitHub repository")
 	flag.Parse()
 
-	// Variables to hold graphql queries (keys) and responses (values)
-	var (
-		reqResp map[string]string = make(map[string]string)
-		query   string
-		ctx     context.Context
-	)
-	ctx = context.Background()
-	// Create a with authentication and add logging (recording)
+	ctx := context.Background()
+	// Create a client with authentication
 	client := oauth2.NewClient(
 		ctx,
 		oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
 		))
 
-	client.Transport = &loghttp.Transport{
-		Transport: client.Transport,
-		LogRequest: func(req *http.Request) {
-			query = cloneRequest(req)
-			// https://stackoverflow.com/a/19006050/869151
-			req.Close = true
-		},
-		LogResponse: func(resp *http.Response) {
-			// TODO(@kyrcha): also record other types of responses
-			if resp.StatusCode == http.StatusOK {
-				reqResp[query] = cloneResponse(resp)
-			}
-		},
-	}
-
+	// record a repo crawl
+	filename := fmt.Sprintf("repository_%s_%s", org, repo)
 	memory := new(testutils.Memory)
-	downloader, err := github.NewDownloader(client, memory)
+	// the repository cassette is several MB, it is kept gzipped
+	err := record(filename+".cassette.json.gz", client, func(downloader *github.Downloader) error {
+		log.Infof("Start recording a repo")
+		defer log.Infof("End recording a repo")
+		return downloader.DownloadRepository(ctx, org, repo, 0)
+	}, memory)
 	if err != nil {
 		panic(err)
 	}
 
-	// record a repo crawl
-	log.Infof("Start recording a repo")
-	downloader.DownloadRepository(ctx, org, repo, 0)
-	log.Infof("End recording a repo")
-
 	// create a struct with the oracle
 	repoOracles := testutils.TestOracles{
 		RepositoryTestOracles: []testutils.RepositoryTestOracle{
@@ -98,28 +76,23 @@ func main() {
 		},
 	}
 
-	// store the results
-	dt := time.Now()
-	filename := fmt.Sprintf("repository_%s_%s_%s", org, repo, dt.Format("2006-01-02"))
-
-	err = encodeAndStore(filename+".gob.gz", reqResp)
+	err = encodeAndStoreTests(filename+".json", repoOracles)
 	if err != nil {
 		panic(err)
 	}
 
-	err = encodeAndStoreTests(filename+".json", repoOracles)
+	// record an org crawl
+	filename = fmt.Sprintf("organization_%s", org)
+	memory = new(testutils.Memory)
+	err = record(filename+".cassette.json", client, func(downloader *github.Downloader) error {
+		log.Infof("Start recording an org")
+		defer log.Infof("End recording an org")
+		return downloader.DownloadOrganization(ctx, org, 0)
+	}, memory)
 	if err != nil {
 		panic(err)
 	}
 
-	// reset map
-	reqResp = make(map[string]string)
-
-	// record an org crawl
-	log.Infof("Start recording an org")
-	downloader.DownloadOrganization(ctx, org, 0)
-	log.Infof("End recording an org")
-
 	// create a struct with the oracle
 	orgOracles := testutils.TestOracles{
 		OrganizationTestOracles: []testutils.OrganizationTestOracle{
@@ -135,51 +108,30 @@ func main() {
 		},
 	}
 
-	filename = fmt.Sprintf("organization_%s_%s", org, dt.Format("2006-01-02"))
-
-	// store the results
-	err = encodeAndStore(filename+".gob.gz", reqResp)
-	if err != nil {
-		panic(err)
-	}
 	err = encodeAndStoreTests(filename+".json", orgOracles)
 	if err != nil {
 		panic(err)
 	}
 }
 
-func cloneRequest(req *http.Request) string {
-	savecl := req.ContentLength
-	bodyBytes, _ := ioutil.ReadAll(req.Body)
-	defer req.Body.Close()
-	// recreate request body
-	req.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-	req.ContentLength = savecl
-	return string(bodyBytes)
-}
-
-func cloneResponse(resp *http.Response) string {
-	// consume response body
-	savecl := resp.ContentLength
-	bodyBytes, _ := ioutil.ReadAll(resp.Body)
-	defer resp.Body.Close()
-	// recreate response body
-	resp.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-	resp.ContentLength = savecl
-	// save response body
-	return string(bodyBytes)
-}
+// record runs the download with a Downloader that records its HTTP
+// interactions into the given cassette
+func record(cassette string, client *http.Client, download func(*github.Downloader) error, memory *testutils.Memory) error {
+	r, err := recorder.New(filepath.Join("testdata", cassette), recorder.Record, client.Transport)
+	if err != nil {
+		return err
+	}
 
-func encodeAndStore(filename string, reqResp map[string]string) error {
-	filepath := filepath.Join("testdata", filename)
-	encodeFile, err := os.Create(filepath)
+	downloader, err := github.NewDownloader(r.Client(), memory)
 	if err != nil {
 		return err
 	}
-	defer encodeFile.Close()
-	zw := gzip.NewWriter(encodeFile)
-	defer zw.Close()
-	return gob.NewEncoder(zw).Encode(reqResp)
+
+	if err := download(downloader); err != nil {
+		return err
+	}
+
+	return r.Save()
 }
 
 func encodeAndStoreTests(filename string, tests testutils.TestOracles) error {
@@ -0,0 +1,159 @@
+// Package api contains the Gitea (and Forgejo) REST API v1 resources
+// downloaded by the gitea Downloader
+package api
+
+import "time"
+
+// User represents https://try.gitea.io/api/swagger#model-User
+type User struct {
+	AvatarURL string    `json:"avatar_url"` // avatar_url text,
+	Created   time.Time `json:"created"`    // created_at timestamptz,
+	Email     string    `json:"email"`      // email text,
+	FullName  string    `json:"full_name"`  // name text,
+	ID        int64     `json:"id"`         // id bigint,
+	IsAdmin   bool      `json:"is_admin"`   // is_admin boolean,
+	Location  string    `json:"location"`   // location text,
+	Login     string    `json:"login"`      // login text NOT NULL,
+	Website   string    `json:"website"`    // website text,
+}
+
+// Organization represents https://try.gitea.io/api/swagger#model-Organization
+type Organization struct {
+	AvatarURL   string `json:"avatar_url"`  // avatar_url text,
+	Description string `json:"description"` // description text,
+	Email       string `json:"email"`       // email text,
+	FullName    string `json:"full_name"`   // name text,
+	ID          int64  `json:"id"`          // id bigint,
+	Location    string `json:"location"`    // location text,
+	Name        string `json:"name"`        // login text NOT NULL,
+	Visibility  string `json:"visibility"`  // visibility text,
+	Website     string `json:"website"`     // website text,
+}
+
+// Repository represents https://try.gitea.io/api/swagger#model-Repository
+type Repository struct {
+	Archived        bool      `json:"archived"`          // archived boolean,
+	CloneURL        string    `json:"clone_url"`         // clone_url text,
+	CreatedAt       time.Time `json:"created_at"`        // created_at timestamptz,
+	DefaultBranch   string    `json:"default_branch"`    // default_branch text,
+	Description     string    `json:"description"`       // description text,
+	Fork            bool      `json:"fork"`              // fork boolean,
+	ForksCount      int       `json:"forks_count"`       // forks_count bigint,
+	FullName        string    `json:"full_name"`         // full_name text,
+	HasIssues       bool      `json:"has_issues"`        // has_issues boolean,
+	HasPullRequests bool      `json:"has_pull_requests"` // has_pull_requests boolean,
+	HasWiki         bool      `json:"has_wiki"`          // has_wiki boolean,
+	HTMLURL         string    `json:"html_url"`          // htmlurl text,
+	ID              int64     `json:"id"`                // id bigint,
+	Mirror          bool      `json:"mirror"`            // mirror boolean,
+	Name            string    `json:"name"`              // name text NOT NULL,
+	OpenIssuesCount int       `json:"open_issues_count"` // open_issues_count bigint,
+	OpenPRCounter   int       `json:"open_pr_counter"`   // open_pull_requests_count bigint,
+	Owner           User      `json:"owner"`             // owner_id bigint, owner_login text NOT NULL,
+	Private         bool      `json:"private"`           // private boolean,
+	SSHURL          string    `json:"ssh_url"`           // sshurl text,
+	StarsCount      int       `json:"stars_count"`       // stargazers_count bigint,
+	Topics          []string  `json:"topics"`            // topics text[] NOT NULL,
+	UpdatedAt       time.Time `json:"updated_at"`        // updated_at timestamptz,
+	Website         string    `json:"website"`           // homepage text,
+}
+
+// Label represents https://try.gitea.io/api/swagger#model-Label
+type Label struct {
+	Color       string `json:"color"`       // color text,
+	Description string `json:"description"` // description text,
+	ID          int64  `json:"id"`          // id bigint,
+	Name        string `json:"name"`        // name text,
+	URL         string `json:"url"`         // url text,
+}
+
+// Milestone represents https://try.gitea.io/api/swagger#model-Milestone
+type Milestone struct {
+	ClosedAt     *time.Time `json:"closed_at"`     // closed_at timestamptz,
+	ClosedIssues int        `json:"closed_issues"` // closed_issues bigint,
+	Created      time.Time  `json:"created_at"`    // created_at timestamptz,
+	Deadline     *time.Time `json:"due_on"`        // due_on timestamptz,
+	Description  string     `json:"description"`   // description text,
+	ID           int64      `json:"id"`            // id bigint,
+	OpenIssues   int        `json:"open_issues"`   // open_issues bigint,
+	State        string     `json:"state"`         // state text,
+	Title        string     `json:"title"`         // title text,
+	Updated      *time.Time `json:"updated_at"`    // updated_at timestamptz,
+}
+
+// Issue represents https://try.gitea.io/api/swagger#model-Issue
+type Issue struct {
+	Assignees []User     `json:"assignees"`  // assignees text[] NOT NULL,
+	Body      string     `json:"body"`       // body text,
+	ClosedAt  *time.Time `json:"closed_at"`  // closed_at timestamptz,
+	Comments  int        `json:"comments"`   // comments bigint,
+	CreatedAt time.Time  `json:"created_at"` // created_at timestamptz,
+	HTMLURL   string     `json:"html_url"`   // htmlurl text,
+	ID        int64      `json:"id"`         // id bigint,
+	IsLocked  bool       `json:"is_locked"`  // locked boolean,
+	Labels    []Label    `json:"labels"`     // labels text[] NOT NULL,
+	Milestone *Milestone `json:"milestone"`  // milestone_id bigint, milestone_title text,
+	Number    int        `json:"number"`     // number bigint NOT NULL,
+	State     string     `json:"state"`      // state text,
+	Title     string     `json:"title"`      // title text,
+	UpdatedAt time.Time  `json:"updated_at"` // updated_at timestamptz,
+	User      User       `json:"user"`       // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// PRBranch is the head or the base of a pull request
+type PRBranch struct {
+	Ref        string      `json:"ref"`
+	Sha        string      `json:"sha"`
+	Repository *Repository `json:"repo"`
+}
+
+// PullRequest represents https://try.gitea.io/api/swagger#model-PullRequest
+type PullRequest struct {
+	Assignees      []User     `json:"assignees"`        // assignees text[] NOT NULL,
+	Base           PRBranch   `json:"base"`             // base_ref text, base_sha text, base_repository_name text, base_repository_owner text,
+	Body           string     `json:"body"`             // body text,
+	ClosedAt       *time.Time `json:"closed_at"`        // closed_at timestamptz,
+	Comments       int        `json:"comments"`         // comments bigint,
+	CreatedAt      time.Time  `json:"created_at"`       // created_at timestamptz,
+	Head           PRBranch   `json:"head"`             // head_ref text, head_sha text, head_repository_name text, head_repository_owner text,
+	HTMLURL        string     `json:"html_url"`         // htmlurl text,
+	ID             int64      `json:"id"`               // id bigint,
+	IsLocked       bool       `json:"is_locked"`        // locked boolean,
+	Labels         []Label    `json:"labels"`           // labels text[] NOT NULL,
+	Mergeable      bool       `json:"mergeable"`        // mergeable boolean,
+	Merged         bool       `json:"merged"`           // merged boolean,
+	MergedAt       *time.Time `json:"merged_at"`        // merged_at timestamptz,
+	MergedBy       *User      `json:"merged_by"`        // merged_by_id bigint, merged_by_login text,
+	MergeCommitSHA *string    `json:"merge_commit_sha"` // merge_commit_sha text,
+	Milestone      *Milestone `json:"milestone"`        // milestone_id bigint, milestone_title text,
+	Number         int        `json:"number"`           // number bigint NOT NULL,
+	State          string     `json:"state"`            // state text,
+	Title          string     `json:"title"`            // title text,
+	UpdatedAt      time.Time  `json:"updated_at"`       // updated_at timestamptz,
+	User           User       `json:"user"`             // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// Comment represents https://try.gitea.io/api/swagger#model-Comment, the
+// comments of issues and pull requests
+type Comment struct {
+	Body      string    `json:"body"`       // body text,
+	CreatedAt time.Time `json:"created_at"` // created_at timestamptz,
+	HTMLURL   string    `json:"html_url"`   // htmlurl text,
+	ID        int64     `json:"id"`         // id bigint,
+	UpdatedAt time.Time `json:"updated_at"` // updated_at timestamptz,
+	User      User      `json:"user"`       // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// PullReview represents https://try.gitea.io/api/swagger#model-PullReview
+type PullReview struct {
+	Body          string    `json:"body"`           // body text,
+	CommentsCount int       `json:"comments_count"` // comments bigint,
+	CommitID      string    `json:"commit_id"`      // commit_id text,
+	HTMLURL       string    `json:"html_url"`       // htmlurl text,
+	ID            int64     `json:"id"`             // id bigint,
+	Official      bool      `json:"official"`       // official boolean,
+	Stale         bool      `json:"stale"`          // stale boolean,
+	State         string    `json:"state"`          // state text,
+	Submitted     time.Time `json:"submitted_at"`   // submitted_at timestamptz,
+	User          *User     `json:"user"`           // user_id bigint NOT NULL, user_login text NOT NULL,
+}
@@ -0,0 +1,461 @@
+// Package gitea downloads the metadata of Gitea and Forgejo organizations and
+// repositories using the REST API v1, and stores it with the same model as
+// the github package.
+//
+// Gitea answers with the same status codes as GitHub for unauthorized and
+// failed requests, so the HTTP client can use the github package transports:
+//
+//	github.SetRateLimitTransport(client, logger)
+//	github.SetRetryTransport(client)
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/src-d/metadata-retrieval/gitea/api"
+	"github.com/src-d/metadata-retrieval/utils/ctxlog"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// limit is the page size requested for the paginated resources, the default
+// maximum of a Gitea instance
+const limit = 50
+
+// Storer is an interface required by Downloader to persist the downloaded data
+type Storer interface {
+	SaveOrganization(ctx context.Context, organization *api.Organization) error
+	SaveUser(ctx context.Context, orgID int64, orgLogin string, user *api.User) error
+	SaveRepository(ctx context.Context, repository *api.Repository) error
+	SaveLabel(ctx context.Context, repositoryOwner, repositoryName string, label *api.Label) error
+	SaveMilestone(ctx context.Context, repositoryOwner, repositoryName string, milestone *api.Milestone) error
+	SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error
+	SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *api.Comment) error
+	SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *api.PullRequest) error
+	SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *api.Comment) error
+	SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *api.PullReview) error
+
+	Begin() error
+	Commit() error
+	Rollback() error
+	Version(v int)
+	SetActiveVersion(ctx context.Context, v int) error
+	Cleanup(ctx context.Context, currentVersion int) error
+}
+
+// Downloader fetches Gitea data using the REST API v1
+type Downloader struct {
+	storer  Storer
+	client  *http.Client
+	baseURL string
+}
+
+// NewDownloader creates a new Downloader that will store the Gitea metadata
+// in the given Storer. The HTTP client is expected to have the proper
+// authentication setup. The base URL is the one of the API of the instance,
+// e.g. https://gitea.example.com/api/v1/
+func NewDownloader(httpClient *http.Client, baseURL string, storer Storer) (*Downloader, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if baseURL == "" {
+		return nil, fmt.Errorf("the Gitea API URL is required")
+	}
+
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid Gitea API URL %q: %v", baseURL, err)
+	}
+
+	return &Downloader{
+		storer:  storer,
+		client:  httpClient,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+	}, nil
+}
+
+// linkNextRegexp matches the next page URL of a Link header
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// get requests the given URL, decodes the JSON response into v and returns
+// the URL of the next page, empty if it was the last one
+func (d Downloader) get(ctx context.Context, u string, v interface{}) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := d.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	path := strings.TrimPrefix(u, d.baseURL)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("GET %s: could not decode the response: %v", path, err)
+	}
+
+	match := linkNextRegexp.FindStringSubmatch(resp.Header.Get("Link"))
+	if match == nil {
+		return "", nil
+	}
+
+	return match[1], nil
+}
+
+// downloadPages requests all the pages of the given resource path, following
+// the Link header; process is called with the raw JSON of each page
+func (d Downloader) downloadPages(ctx context.Context, name string, path string, params url.Values, process func(page json.RawMessage) error) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", fmt.Sprint(limit))
+
+	u := d.baseURL + path + "?" + params.Encode()
+	for u != "" {
+		var res json.RawMessage
+		next, err := d.get(ctx, u, &res)
+		if err != nil {
+			return fmt.Errorf("query to %s failed: %v", name, err)
+		}
+
+		if err := process(res); err != nil {
+			return fmt.Errorf("can not process %s: %v", name, err)
+		}
+
+		u = next
+	}
+
+	return nil
+}
+
+// repositoryPath returns the API path of a repository
+func repositoryPath(owner string, name string) string {
+	return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+// DownloadRepository downloads the metadata for the given repository and all
+// its resources (labels, milestones, issues, PRs, comments, reviews)
+func (d Downloader) DownloadRepository(ctx context.Context, owner string, name string, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"owner": owner, "repo": name})
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var repository api.Repository
+	_, err = d.get(ctx, d.baseURL+repositoryPath(owner, name), &repository)
+	if err != nil {
+		return fmt.Errorf("repository query failed: %v", err)
+	}
+
+	err = d.storer.SaveRepository(ctx, &repository)
+	if err != nil {
+		return fmt.Errorf("failed to save repository %v: %v", repository.FullName, err)
+	}
+
+	err = d.downloadLabels(ctx, owner, name)
+	if err != nil {
+		return err
+	}
+
+	err = d.downloadMilestones(ctx, owner, name)
+	if err != nil {
+		return err
+	}
+
+	if repository.HasIssues {
+		err = d.downloadIssues(ctx, owner, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if repository.HasPullRequests {
+		err = d.downloadPullRequests(ctx, owner, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d Downloader) downloadLabels(ctx context.Context, owner string, name string) error {
+	path := repositoryPath(owner, name) + "/labels"
+	return d.downloadPages(ctx, "labels", path, nil, func(page json.RawMessage) error {
+		var labels []api.Label
+		if err := json.Unmarshal(page, &labels); err != nil {
+			return err
+		}
+
+		for _, label := range labels {
+			if err := d.storer.SaveLabel(ctx, owner, name, &label); err != nil {
+				return fmt.Errorf("failed to save label %v: %v", label.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d Downloader) downloadMilestones(ctx context.Context, owner string, name string) error {
+	params := url.Values{"state": []string{"all"}}
+	path := repositoryPath(owner, name) + "/milestones"
+	return d.downloadPages(ctx, "milestones", path, params, func(page json.RawMessage) error {
+		var milestones []api.Milestone
+		if err := json.Unmarshal(page, &milestones); err != nil {
+			return err
+		}
+
+		for _, milestone := range milestones {
+			if err := d.storer.SaveMilestone(ctx, owner, name, &milestone); err != nil {
+				return fmt.Errorf("failed to save milestone %v: %v", milestone.Title, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d Downloader) downloadIssues(ctx context.Context, owner string, name string) error {
+	logger := ctxlog.Get(ctx)
+	logger.Infof("start downloading issues")
+	defer logger.Infof("finished downloading issues")
+
+	// the pull requests are issues too, they are downloaded on their own
+	params := url.Values{"state": []string{"all"}, "type": []string{"issues"}}
+	path := repositoryPath(owner, name) + "/issues"
+	return d.downloadPages(ctx, "issues", path, params, func(page json.RawMessage) error {
+		var issues []api.Issue
+		if err := json.Unmarshal(page, &issues); err != nil {
+			return err
+		}
+
+		for _, issue := range issues {
+			if err := d.storer.SaveIssue(ctx, owner, name, &issue); err != nil {
+				return fmt.Errorf("failed to save issue #%v: %v", issue.Number, err)
+			}
+
+			if issue.Comments == 0 {
+				continue
+			}
+
+			comments, err := d.comments(ctx, owner, name, issue.Number)
+			if err != nil {
+				return err
+			}
+
+			for _, comment := range comments {
+				if err := d.storer.SaveIssueComment(ctx, owner, name, issue.Number, &comment); err != nil {
+					return fmt.Errorf("failed to save comment %v of issue #%v: %v", comment.ID, issue.Number, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d Downloader) downloadPullRequests(ctx context.Context, owner string, name string) error {
+	logger := ctxlog.Get(ctx)
+	logger.Infof("start downloading pull requests")
+	defer logger.Infof("finished downloading pull requests")
+
+	params := url.Values{"state": []string{"all"}}
+	path := repositoryPath(owner, name) + "/pulls"
+	return d.downloadPages(ctx, "pull requests", path, params, func(page json.RawMessage) error {
+		var prs []api.PullRequest
+		if err := json.Unmarshal(page, &prs); err != nil {
+			return err
+		}
+
+		for _, pr := range prs {
+			if err := d.storer.SavePullRequest(ctx, owner, name, &pr); err != nil {
+				return fmt.Errorf("failed to save PR #%v: %v", pr.Number, err)
+			}
+
+			if pr.Comments > 0 {
+				comments, err := d.comments(ctx, owner, name, pr.Number)
+				if err != nil {
+					return err
+				}
+
+				for _, comment := range comments {
+					if err := d.storer.SavePullRequestComment(ctx, owner, name, pr.Number, &comment); err != nil {
+						return fmt.Errorf("failed to save comment %v of PR #%v: %v", comment.ID, pr.Number, err)
+					}
+				}
+			}
+
+			if err := d.downloadReviews(ctx, owner, name, pr.Number); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// comments returns the comments of an issue or a pull request, they are not
+// paginated
+func (d Downloader) comments(ctx context.Context, owner string, name string, number int) ([]api.Comment, error) {
+	var comments []api.Comment
+	path := fmt.Sprintf("%s/issues/%d/comments", repositoryPath(owner, name), number)
+	if _, err := d.get(ctx, d.baseURL+path, &comments); err != nil {
+		return nil, fmt.Errorf("query to comments failed: %v", err)
+	}
+
+	return comments, nil
+}
+
+func (d Downloader) downloadReviews(ctx context.Context, owner string, name string, number int) error {
+	path := fmt.Sprintf("%s/pulls/%d/reviews", repositoryPath(owner, name), number)
+	return d.downloadPages(ctx, "reviews", path, nil, func(page json.RawMessage) error {
+		var reviews []api.PullReview
+		if err := json.Unmarshal(page, &reviews); err != nil {
+			return err
+		}
+
+		for _, review := range reviews {
+			if err := d.storer.SavePullRequestReview(ctx, owner, name, number, &review); err != nil {
+				return fmt.Errorf("failed to save review %v of PR #%v: %v", review.ID, number, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListRepositories returns the names of the repositories of the given
+// organization
+func (d Downloader) ListRepositories(ctx context.Context, org string, noForks bool) ([]string, error) {
+	path := "orgs/" + url.PathEscape(org) + "/repos"
+
+	repositories := []string{}
+	err := d.downloadPages(ctx, "repositories", path, nil, func(page json.RawMessage) error {
+		var res []api.Repository
+		if err := json.Unmarshal(page, &res); err != nil {
+			return err
+		}
+
+		for _, repository := range res {
+			if noForks && repository.Fork {
+				continue
+			}
+
+			repositories = append(repositories, repository.Name)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
+
+// DownloadOrganization downloads the metadata for the given organization and
+// its member users
+func (d Downloader) DownloadOrganization(ctx context.Context, name string, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"org": name})
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var organization api.Organization
+	_, err = d.get(ctx, d.baseURL+"orgs/"+url.PathEscape(name), &organization)
+	if err != nil {
+		return fmt.Errorf("organization query failed: %v", err)
+	}
+
+	err = d.storer.SaveOrganization(ctx, &organization)
+	if err != nil {
+		return fmt.Errorf("failed to save organization %v: %v", name, err)
+	}
+
+	err = d.downloadUsers(ctx, &organization)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d Downloader) downloadUsers(ctx context.Context, organization *api.Organization) error {
+	path := "orgs/" + url.PathEscape(organization.Name) + "/members"
+	return d.downloadPages(ctx, "users", path, nil, func(page json.RawMessage) error {
+		var users []api.User
+		if err := json.Unmarshal(page, &users); err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			if err := d.storer.SaveUser(ctx, organization.ID, organization.Name, &user); err != nil {
+				return fmt.Errorf("failed to save user %v: %v", user.Login, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetCurrent enables the given version as the current one accessible in the DB
+func (d Downloader) SetCurrent(ctx context.Context, version int) error {
+	err := d.storer.SetActiveVersion(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to set current DB version to %v: %v", version, err)
+	}
+	return nil
+}
+
+// Cleanup deletes from the DB all records that do not belong to the currentVersion
+func (d Downloader) Cleanup(ctx context.Context, currentVersion int) error {
+	err := d.storer.Cleanup(ctx, currentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to do cleanup for DB version %v: %v", currentVersion, err)
+	}
+	return nil
+}
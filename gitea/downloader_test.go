@@ -0,0 +1,153 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-log.v1"
+)
+
+const (
+	recFile = "../testdata/gitea-recordings.json"
+	testURL = "https://gitea.example.com/api/v1/"
+)
+
+// newReplayer returns the replayer of the recorded responses, indexed by the
+// request URI relative to the API base URL
+func newReplayer(t *testing.T) *testutils.Replayer {
+	replayer, err := testutils.NewReplayer(recFile, "/api/v1/", testutils.RecordedResponse{
+		Status: http.StatusNotFound,
+		Body:   []byte(`{"message":"not found"}`),
+	})
+	require.NoError(t, err)
+
+	return replayer
+}
+
+// getRoundTripDownloader returns a Downloader that replays the recorded
+// responses through the github package transports
+func getRoundTripDownloader(t *testing.T, storer Storer, transport http.RoundTripper) *Downloader {
+	client := &http.Client{Transport: transport}
+	github.SetRateLimitTransport(client, log.New(nil))
+	github.SetRetryTransport(client)
+
+	downloader, err := NewDownloader(client, testURL, storer)
+	require.NoError(t, err)
+
+	return downloader
+}
+
+func TestDownloadRepository(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.GiteaMemory{}
+	downloader := getRoundTripDownloader(t, storer, newReplayer(t))
+
+	err := downloader.DownloadRepository(context.TODO(), "acme", "api", 1)
+	require.NoError(err)
+
+	require.Equal("acme/api", storer.Repository.FullName)
+	require.Equal([]string{"go"}, storer.Repository.Topics)
+	require.Len(storer.Labels, 1)
+	require.Len(storer.Milestones, 1)
+
+	// the issues are paginated with the Link header
+	require.Len(storer.Issues, 2)
+	require.Equal("v1", storer.Issues[0].Milestone.Title)
+	require.Equal("bug", storer.Issues[0].Labels[0].Name)
+	require.Len(storer.IssueComments, 2)
+
+	require.Len(storer.PRs, 2)
+	require.False(storer.PRs[0].Merged)
+	require.True(storer.PRs[1].Merged)
+	require.Equal("alice", storer.PRs[1].MergedBy.Login)
+	require.Nil(storer.PRs[1].Head.Repository)
+	require.Len(storer.PRComments, 1)
+
+	require.Len(storer.PRReviews, 2)
+	require.Equal("APPROVED", storer.PRReviews[0].State)
+	require.Nil(storer.PRReviews[1].User)
+}
+
+func TestDownloadRepositoryRetry(t *testing.T) {
+	require := require.New(t)
+
+	replayer := newReplayer(t)
+	failures := 0
+	storer := &testutils.GiteaMemory{}
+	downloader := getRoundTripDownloader(t, storer, testutils.RoundTripFunc(func(req *http.Request) *http.Response {
+		if failures < 2 {
+			failures++
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Status:     "502 Bad Gateway",
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				Header:     make(http.Header),
+			}
+		}
+
+		return replayer.Response(req)
+	}))
+
+	err := downloader.DownloadRepository(context.TODO(), "acme", "api", 1)
+	require.NoError(err)
+	require.Equal(2, failures)
+	require.Len(storer.PRs, 2)
+}
+
+func TestDownloadRepositoryUnauthorized(t *testing.T) {
+	requests := 0
+	downloader := getRoundTripDownloader(t, &testutils.GiteaMemory{}, testutils.RoundTripFunc(func(req *http.Request) *http.Response {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Status:     "401 Unauthorized",
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"message":"token is required"}`)),
+			Header:     make(http.Header),
+		}
+	}))
+
+	err := downloader.DownloadRepository(context.TODO(), "acme", "api", 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unauthorized: token is required")
+	// an unauthorized request is not retried
+	require.Equal(t, 1, requests)
+}
+
+func TestDownloadOrganization(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.GiteaMemory{}
+	downloader := getRoundTripDownloader(t, storer, newReplayer(t))
+
+	err := downloader.DownloadOrganization(context.TODO(), "acme", 1)
+	require.NoError(err)
+	require.Equal("ACME", storer.Organization.FullName)
+	require.Len(storer.Users, 2)
+}
+
+func TestListRepositories(t *testing.T) {
+	require := require.New(t)
+
+	downloader := getRoundTripDownloader(t, &testutils.GiteaMemory{}, newReplayer(t))
+
+	repositories, err := downloader.ListRepositories(context.TODO(), "acme", false)
+	require.NoError(err)
+	require.Equal([]string{"api", "api-fork", "web"}, repositories)
+
+	repositories, err = downloader.ListRepositories(context.TODO(), "acme", true)
+	require.NoError(err)
+	require.Equal([]string{"api", "web"}, repositories)
+}
+
+func TestNewDownloaderRequiresURL(t *testing.T) {
+	_, err := NewDownloader(nil, "", &testutils.GiteaMemory{})
+	require.Error(t, err)
+}
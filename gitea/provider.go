@@ -0,0 +1,39 @@
+package gitea
+
+import (
+	"context"
+
+	"github.com/src-d/metadata-retrieval/gitea/store"
+	"github.com/src-d/metadata-retrieval/provider"
+)
+
+func init() {
+	provider.Register("gitea", newProvider)
+}
+
+// newProvider creates a Downloader for the provider registry, the base URL of
+// the instance API is required
+func newProvider(c provider.Config) (provider.Downloader, error) {
+	var storer Storer = &store.Stdout{}
+	if c.DB != nil {
+		storer = store.NewDB(c.DB)
+	}
+
+	d, err := NewDownloader(c.HTTPClient, c.BaseURL, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerDownloader{d}, nil
+}
+
+// providerDownloader implements provider.Downloader
+type providerDownloader struct {
+	*Downloader
+}
+
+// RateRemaining returns provider.UnknownRate, Gitea does not have a rate
+// limit
+func (d providerDownloader) RateRemaining(ctx context.Context) (int, error) {
+	return provider.UnknownRate, nil
+}
@@ -0,0 +1,388 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/database"
+	"github.com/src-d/metadata-retrieval/gitea/api"
+
+	"github.com/lib/pq"
+)
+
+type DB struct {
+	*sql.DB
+	tx *sql.Tx
+	v  int
+}
+
+func NewDB(db *sql.DB) *DB {
+	return &DB{DB: db}
+}
+
+func (s *DB) Begin() error {
+	var err error
+	s.tx, err = s.DB.Begin()
+	return err
+}
+
+func (s *DB) Commit() error {
+	return s.tx.Commit()
+}
+
+func (s *DB) Rollback() error {
+	return s.tx.Rollback()
+}
+
+func (s *DB) Version(v int) {
+	s.v = v
+}
+
+const (
+	organizationsCols      = "avatar_url, description, email, id, location, login, name, visibility, website"
+	usersCols              = "avatar_url, created_at, email, id, is_admin, location, login, name, organization_id, organization_login, website"
+	repositoriesCols       = "archived, clone_url, created_at, default_branch, description, fork, forks_count, full_name, has_issues, has_pull_requests, has_wiki, homepage, htmlurl, id, mirror, name, open_issues_count, open_pull_requests_count, owner_id, owner_login, private, sshurl, stargazers_count, topics, updated_at"
+	labelsCols             = "color, description, id, name, repository_name, repository_owner, url"
+	milestonesCols         = "closed_at, closed_issues, created_at, description, due_on, id, open_issues, repository_name, repository_owner, state, title, updated_at"
+	issuesCols             = "assignees, body, closed_at, comments, created_at, htmlurl, id, labels, locked, milestone_id, milestone_title, number, repository_name, repository_owner, state, title, updated_at, user_id, user_login"
+	issueCommentsCols      = "body, created_at, htmlurl, id, issue_number, repository_name, repository_owner, updated_at, user_id, user_login"
+	pullRequestsCols       = "assignees, base_ref, base_repository_name, base_repository_owner, base_sha, body, closed_at, comments, created_at, head_ref, head_repository_name, head_repository_owner, head_sha, htmlurl, id, labels, locked, merge_commit_sha, mergeable, merged, merged_at, merged_by_id, merged_by_login, milestone_id, milestone_title, number, repository_name, repository_owner, state, title, updated_at, user_id, user_login"
+	pullRequestReviewsCols = "body, comments, commit_id, htmlurl, id, official, pull_request_number, repository_name, repository_owner, stale, state, submitted_at, user_id, user_login"
+)
+
+var tables = []string{
+	"gitea_organizations_versioned",
+	"gitea_users_versioned",
+	"gitea_repositories_versioned",
+	"gitea_labels_versioned",
+	"gitea_milestones_versioned",
+	"gitea_issues_versioned",
+	"gitea_issue_comments_versioned",
+	"gitea_pull_requests_versioned",
+	"gitea_pull_request_reviews_versioned",
+}
+
+func (s *DB) SetActiveVersion(ctx context.Context, v int) error {
+	// Unified schema
+
+	if err := database.SetUnifiedViews(ctx, s.DB, v); err != nil {
+		return err
+	}
+
+	// Gitea schema without versions
+
+	return database.SetVersionedViews(ctx, s.DB, tables, v)
+}
+
+func (s *DB) Cleanup(ctx context.Context, currentVersion int) error {
+	return database.CleanupVersioned(ctx, s.DB, tables, currentVersion)
+}
+
+// insert stores the given values of a row in a versioned table
+func (s *DB) insert(ctx context.Context, table string, cols string, args ...interface{}) error {
+	return database.InsertVersioned(ctx, s.tx, s.v, table, cols, args...)
+}
+
+func (s *DB) SaveOrganization(ctx context.Context, organization *api.Organization) error {
+	err := s.insert(ctx, "gitea_organizations_versioned", organizationsCols,
+		organization.AvatarURL,   // avatar_url text,
+		organization.Description, // description text,
+		organization.Email,       // email text,
+		organization.ID,          // id bigint,
+		organization.Location,    // location text,
+		organization.Name,        // login text NOT NULL,
+		organization.FullName,    // name text,
+		organization.Visibility,  // visibility text,
+		organization.Website,     // website text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveOrganization: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveUser(ctx context.Context, orgID int64, orgLogin string, user *api.User) error {
+	err := s.insert(ctx, "gitea_users_versioned", usersCols,
+		user.AvatarURL, // avatar_url text,
+		user.Created,   // created_at timestamptz,
+		user.Email,     // email text,
+		user.ID,        // id bigint,
+		user.IsAdmin,   // is_admin boolean,
+		user.Location,  // location text,
+		user.Login,     // login text NOT NULL,
+		user.FullName,  // name text,
+		orgID,          // organization_id bigint NOT NULL,
+		orgLogin,       // organization_login text NOT NULL,
+		user.Website,   // website text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveUser: %v", err)
+	}
+	return nil
+}
+
+// nonNil returns an empty slice for a nil one, to store it in a NOT NULL
+// array column
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+func (s *DB) SaveRepository(ctx context.Context, repository *api.Repository) error {
+	err := s.insert(ctx, "gitea_repositories_versioned", repositoriesCols,
+		repository.Archived,                 // archived boolean,
+		repository.CloneURL,                 // clone_url text,
+		repository.CreatedAt,                // created_at timestamptz,
+		repository.DefaultBranch,            // default_branch text,
+		repository.Description,              // description text,
+		repository.Fork,                     // fork boolean,
+		repository.ForksCount,               // forks_count bigint,
+		repository.FullName,                 // full_name text,
+		repository.HasIssues,                // has_issues boolean,
+		repository.HasPullRequests,          // has_pull_requests boolean,
+		repository.HasWiki,                  // has_wiki boolean,
+		repository.Website,                  // homepage text,
+		repository.HTMLURL,                  // htmlurl text,
+		repository.ID,                       // id bigint,
+		repository.Mirror,                   // mirror boolean,
+		repository.Name,                     // name text NOT NULL,
+		repository.OpenIssuesCount,          // open_issues_count bigint,
+		repository.OpenPRCounter,            // open_pull_requests_count bigint,
+		repository.Owner.ID,                 // owner_id bigint,
+		repository.Owner.Login,              // owner_login text NOT NULL,
+		repository.Private,                  // private boolean,
+		repository.SSHURL,                   // sshurl text,
+		repository.StarsCount,               // stargazers_count bigint,
+		pq.Array(nonNil(repository.Topics)), // topics text[] NOT NULL,
+		repository.UpdatedAt,                // updated_at timestamptz,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveRepository: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveLabel(ctx context.Context, repositoryOwner, repositoryName string, label *api.Label) error {
+	err := s.insert(ctx, "gitea_labels_versioned", labelsCols,
+		label.Color,       // color text,
+		label.Description, // description text,
+		label.ID,          // id bigint,
+		label.Name,        // name text,
+		repositoryName,    // repository_name text NOT NULL,
+		repositoryOwner,   // repository_owner text NOT NULL,
+		label.URL,         // url text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveLabel: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveMilestone(ctx context.Context, repositoryOwner, repositoryName string, milestone *api.Milestone) error {
+	err := s.insert(ctx, "gitea_milestones_versioned", milestonesCols,
+		milestone.ClosedAt,     // closed_at timestamptz,
+		milestone.ClosedIssues, // closed_issues bigint,
+		milestone.Created,      // created_at timestamptz,
+		milestone.Description,  // description text,
+		milestone.Deadline,     // due_on timestamptz,
+		milestone.ID,           // id bigint,
+		milestone.OpenIssues,   // open_issues bigint,
+		repositoryName,         // repository_name text NOT NULL,
+		repositoryOwner,        // repository_owner text NOT NULL,
+		milestone.State,        // state text,
+		milestone.Title,        // title text,
+		milestone.Updated,      // updated_at timestamptz,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveMilestone: %v", err)
+	}
+	return nil
+}
+
+// logins returns the login of each user, never nil
+func logins(users []api.User) []string {
+	res := make([]string, len(users))
+	for i, user := range users {
+		res[i] = user.Login
+	}
+
+	return res
+}
+
+// labelNames returns the name of each label, never nil
+func labelNames(labels []api.Label) []string {
+	res := make([]string, len(labels))
+	for i, label := range labels {
+		res[i] = label.Name
+	}
+
+	return res
+}
+
+// milestone returns the ID and the title of an optional milestone
+func milestone(m *api.Milestone) (int64, string) {
+	if m == nil {
+		return 0, ""
+	}
+
+	return m.ID, m.Title
+}
+
+func (s *DB) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error {
+	milestoneID, milestoneTitle := milestone(issue.Milestone)
+
+	err := s.insert(ctx, "gitea_issues_versioned", issuesCols,
+		pq.Array(logins(issue.Assignees)),  // assignees text[] NOT NULL,
+		issue.Body,                         // body text,
+		issue.ClosedAt,                     // closed_at timestamptz,
+		issue.Comments,                     // comments bigint,
+		issue.CreatedAt,                    // created_at timestamptz,
+		issue.HTMLURL,                      // htmlurl text,
+		issue.ID,                           // id bigint,
+		pq.Array(labelNames(issue.Labels)), // labels text[] NOT NULL,
+		issue.IsLocked,                     // locked boolean,
+		milestoneID,                        // milestone_id bigint,
+		milestoneTitle,                     // milestone_title text,
+		issue.Number,                       // number bigint NOT NULL,
+		repositoryName,                     // repository_name text NOT NULL,
+		repositoryOwner,                    // repository_owner text NOT NULL,
+		issue.State,                        // state text,
+		issue.Title,                        // title text,
+		issue.UpdatedAt,                    // updated_at timestamptz,
+		issue.User.ID,                      // user_id bigint NOT NULL,
+		issue.User.Login,                   // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveIssue: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *api.Comment) error {
+	err := s.insert(ctx, "gitea_issue_comments_versioned", issueCommentsCols,
+		comment.Body,       // body text,
+		comment.CreatedAt,  // created_at timestamptz,
+		comment.HTMLURL,    // htmlurl text,
+		comment.ID,         // id bigint,
+		issueNumber,        // issue_number bigint NOT NULL,
+		repositoryName,     // repository_name text NOT NULL,
+		repositoryOwner,    // repository_owner text NOT NULL,
+		comment.UpdatedAt,  // updated_at timestamptz,
+		comment.User.ID,    // user_id bigint NOT NULL,
+		comment.User.Login, // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveIssueComment: %v", err)
+	}
+	return nil
+}
+
+// branchRepository returns the owner and the name of the repository of the
+// head or the base of a pull request, empty if it was deleted
+func branchRepository(b api.PRBranch) (string, string) {
+	if b.Repository == nil {
+		return "", ""
+	}
+
+	return b.Repository.Owner.Login, b.Repository.Name
+}
+
+func (s *DB) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *api.PullRequest) error {
+	milestoneID, milestoneTitle := milestone(pr.Milestone)
+	baseOwner, baseName := branchRepository(pr.Base)
+	headOwner, headName := branchRepository(pr.Head)
+
+	var mergedByID int64
+	var mergedByLogin string
+	if pr.MergedBy != nil {
+		mergedByID = pr.MergedBy.ID
+		mergedByLogin = pr.MergedBy.Login
+	}
+
+	err := s.insert(ctx, "gitea_pull_requests_versioned", pullRequestsCols,
+		pq.Array(logins(pr.Assignees)),  // assignees text[] NOT NULL,
+		pr.Base.Ref,                     // base_ref text,
+		baseName,                        // base_repository_name text,
+		baseOwner,                       // base_repository_owner text,
+		pr.Base.Sha,                     // base_sha text,
+		pr.Body,                         // body text,
+		pr.ClosedAt,                     // closed_at timestamptz,
+		pr.Comments,                     // comments bigint,
+		pr.CreatedAt,                    // created_at timestamptz,
+		pr.Head.Ref,                     // head_ref text,
+		headName,                        // head_repository_name text,
+		headOwner,                       // head_repository_owner text,
+		pr.Head.Sha,                     // head_sha text,
+		pr.HTMLURL,                      // htmlurl text,
+		pr.ID,                           // id bigint,
+		pq.Array(labelNames(pr.Labels)), // labels text[] NOT NULL,
+		pr.IsLocked,                     // locked boolean,
+		pr.MergeCommitSHA,               // merge_commit_sha text,
+		pr.Mergeable,                    // mergeable boolean,
+		pr.Merged,                       // merged boolean,
+		pr.MergedAt,                     // merged_at timestamptz,
+		mergedByID,                      // merged_by_id bigint,
+		mergedByLogin,                   // merged_by_login text,
+		milestoneID,                     // milestone_id bigint,
+		milestoneTitle,                  // milestone_title text,
+		pr.Number,                       // number bigint NOT NULL,
+		repositoryName,                  // repository_name text NOT NULL,
+		repositoryOwner,                 // repository_owner text NOT NULL,
+		pr.State,                        // state text,
+		pr.Title,                        // title text,
+		pr.UpdatedAt,                    // updated_at timestamptz,
+		pr.User.ID,                      // user_id bigint NOT NULL,
+		pr.User.Login,                   // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("savePullRequest: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *api.Comment) error {
+	return s.SaveIssueComment(ctx, repositoryOwner, repositoryName, pullRequestNumber, comment)
+}
+
+func (s *DB) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *api.PullReview) error {
+	var userID int64
+	var userLogin string
+	if review.User != nil {
+		userID = review.User.ID
+		userLogin = review.User.Login
+	}
+
+	err := s.insert(ctx, "gitea_pull_request_reviews_versioned", pullRequestReviewsCols,
+		review.Body,          // body text,
+		review.CommentsCount, // comments bigint,
+		review.CommitID,      // commit_id text,
+		review.HTMLURL,       // htmlurl text,
+		review.ID,            // id bigint,
+		review.Official,      // official boolean,
+		pullRequestNumber,    // pull_request_number bigint NOT NULL,
+		repositoryName,       // repository_name text NOT NULL,
+		repositoryOwner,      // repository_owner text NOT NULL,
+		review.Stale,         // stale boolean,
+		review.State,         // state text,
+		review.Submitted,     // submitted_at timestamptz,
+		userID,               // user_id bigint NOT NULL,
+		userLogin,            // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("savePullRequestReview: %v", err)
+	}
+	return nil
+}
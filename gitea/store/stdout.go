@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/gitea/api"
+)
+
+type Stdout struct{}
+
+func (s *Stdout) SaveOrganization(ctx context.Context, organization *api.Organization) error {
+	fmt.Printf("organization data fetched for %s\n", organization.Name)
+	return nil
+}
+
+func (s *Stdout) SaveUser(ctx context.Context, orgID int64, orgLogin string, user *api.User) error {
+	fmt.Printf("user data fetched for %s\n", user.Login)
+	return nil
+}
+
+func (s *Stdout) SaveRepository(ctx context.Context, repository *api.Repository) error {
+	fmt.Printf("repository data fetched for %s\n", repository.FullName)
+	return nil
+}
+
+func (s *Stdout) SaveLabel(ctx context.Context, repositoryOwner, repositoryName string, label *api.Label) error {
+	fmt.Printf("label data fetched for %s\n", label.Name)
+	return nil
+}
+
+func (s *Stdout) SaveMilestone(ctx context.Context, repositoryOwner, repositoryName string, milestone *api.Milestone) error {
+	fmt.Printf("milestone data fetched for %s\n", milestone.Title)
+	return nil
+}
+
+func (s *Stdout) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error {
+	fmt.Printf("issue data fetched for #%v %s\n", issue.Number, issue.Title)
+	return nil
+}
+
+func (s *Stdout) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *api.Comment) error {
+	fmt.Printf("  issue comment data fetched by %s at %v: %q\n", comment.User.Login, comment.CreatedAt, trim(comment.Body))
+	return nil
+}
+
+func (s *Stdout) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *api.PullRequest) error {
+	fmt.Printf("PR data fetched for #%v %s\n", pr.Number, pr.Title)
+	return nil
+}
+
+func (s *Stdout) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *api.Comment) error {
+	fmt.Printf("  pr comment data fetched by %s at %v: %q\n", comment.User.Login, comment.CreatedAt, trim(comment.Body))
+	return nil
+}
+
+func (s *Stdout) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *api.PullReview) error {
+	fmt.Printf("  PR Review data fetched at %v: %q\n", review.Submitted, trim(review.Body))
+	return nil
+}
+
+func (s *Stdout) Begin() error {
+	return nil
+}
+
+func (s *Stdout) Commit() error {
+	return nil
+}
+
+func (s *Stdout) Rollback() error {
+	return nil
+}
+
+func (s *Stdout) Version(v int) {
+}
+
+func (s *Stdout) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+func (s *Stdout) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
+
+func trim(s string) string {
+	if len(s) > 40 {
+		return s[0:39] + "..."
+	}
+
+	return s
+}
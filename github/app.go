@@ -0,0 +1,218 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultRESTURL is the base URL of the github.com REST API
+	DefaultRESTURL = "https://api.github.com/"
+
+	// appJWTExpiration is the lifetime of the JWTs used to authenticate as a
+	// GitHub App, GitHub does not accept more than 10 minutes
+	appJWTExpiration = 9 * time.Minute
+	// installationTokenRefreshMargin is the time before its expiration when
+	// an installation token is refreshed, so the requests in flight do not
+	// use an expired token
+	installationTokenRefreshMargin = 5 * time.Minute
+	// installationsPerPage is the page size used to list the installations
+	installationsPerPage = 100
+)
+
+// App authenticates as a GitHub App to request the access tokens of its
+// installations
+// https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/
+type App struct {
+	id      int64
+	key     *rsa.PrivateKey
+	client  *http.Client
+	restURL string
+	now     func() time.Time
+}
+
+// NewApp returns an App with the given ID and PEM encoded private key. The
+// REST API base URL is DefaultRESTURL when empty; a nil httpClient uses
+// http.DefaultClient
+func NewApp(httpClient *http.Client, restURL string, id int64, privateKey []byte) (*App, error) {
+	key, err := parseRSAPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if restURL == "" {
+		restURL = DefaultRESTURL
+	}
+
+	return &App{
+		id:      id,
+		key:     key,
+		client:  httpClient,
+		restURL: strings.TrimSuffix(restURL, "/") + "/",
+		now:     time.Now,
+	}, nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("the GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the GitHub App private key: %v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the GitHub App private key is not a RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// JWT returns a new JSON Web Token signed with the App private key
+// https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/#authenticating-as-a-github-app
+func (a *App) JWT() (string, error) {
+	now := a.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		// the issued time is set in the past to allow for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTExpiration).Unix(),
+		"iss": a.id,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("could not sign the GitHub App JWT: %v", err)
+	}
+
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
+
+// do sends a REST API request authenticated as the App and decodes the JSON
+// response into v
+func (a *App) do(method string, path string, expectedStatus int, v interface{}) error {
+	req, err := http.NewRequest(method, a.restURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	jwt, err := a.JWT()
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		errorResponse := &apiErrorResponse{}
+		if err := readAPIErrorResponse(resp, errorResponse); err != nil {
+			return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, errorResponse.Message)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Installation is an installation of a GitHub App in a user or organization
+// account
+type Installation struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+	} `json:"account"`
+}
+
+// Installations returns all the installations of the App
+// https://developer.github.com/v3/apps/#list-installations
+func (a *App) Installations() ([]Installation, error) {
+	var installations []Installation
+	for page := 1; ; page++ {
+		var res []Installation
+		path := fmt.Sprintf("app/installations?per_page=%d&page=%d", installationsPerPage, page)
+		if err := a.do(http.MethodGet, path, http.StatusOK, &res); err != nil {
+			return nil, fmt.Errorf("could not list the GitHub App installations: %v", err)
+		}
+
+		installations = append(installations, res...)
+		if len(res) < installationsPerPage {
+			return installations, nil
+		}
+	}
+}
+
+// TokenSource returns an oauth2.TokenSource of access tokens for the given
+// installation. The tokens are reused until they are about to expire
+func (a *App) TokenSource(installationID int64) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &installationTokenSource{app: a, id: installationID})
+}
+
+// installationTokenSource requests a new installation access token on each
+// call to Token
+type installationTokenSource struct {
+	app *App
+	id  int64
+}
+
+// Token implements the oauth2.TokenSource interface
+// https://developer.github.com/v3/apps/#create-a-new-installation-token
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	var res struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	path := fmt.Sprintf("app/installations/%d/access_tokens", s.id)
+	if err := s.app.do(http.MethodPost, path, http.StatusCreated, &res); err != nil {
+		return nil, fmt.Errorf("could not create an access token for the installation %d: %v", s.id, err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: res.Token,
+		TokenType:   "token",
+		Expiry:      res.ExpiresAt.Add(-installationTokenRefreshMargin),
+	}, nil
+}
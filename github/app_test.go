@@ -0,0 +1,150 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(t *testing.T, restURL string) (*App, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	app, err := NewApp(nil, restURL, 42, keyPEM)
+	require.NoError(t, err)
+
+	return app, key
+}
+
+func TestAppJWT(t *testing.T) {
+	require := require.New(t)
+
+	app, key := newTestApp(t, "")
+	now := time.Now()
+	app.now = func() time.Time { return now }
+
+	jwt, err := app.JWT()
+	require.NoError(err)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(err)
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(err)
+
+	var claims struct {
+		Iat int64
+		Exp int64
+		Iss int64
+	}
+	require.NoError(json.Unmarshal(payload, &claims))
+	require.Equal(int64(42), claims.Iss)
+	require.True(claims.Iat < now.Unix())
+	require.True(claims.Exp-claims.Iat <= int64((10 * time.Minute).Seconds()))
+}
+
+func TestNewAppInvalidKey(t *testing.T) {
+	_, err := NewApp(nil, "", 42, []byte("not a key"))
+	require.Error(t, err)
+}
+
+func TestAppInstallations(t *testing.T) {
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal("/app/installations", r.URL.Path)
+		require.True(strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+
+		n := installationsPerPage
+		if r.URL.Query().Get("page") == "2" {
+			n = 1
+		}
+
+		installations := make([]string, n)
+		for i := range installations {
+			installations[i] = fmt.Sprintf(`{"id":%d,"account":{"login":"org-%d"}}`, i, i)
+		}
+
+		w.Write([]byte("[" + strings.Join(installations, ",") + "]"))
+	}))
+	defer server.Close()
+
+	app, _ := newTestApp(t, server.URL)
+	installations, err := app.Installations()
+	require.NoError(err)
+	require.Len(installations, installationsPerPage+1)
+	require.Equal("org-1", installations[1].Account.Login)
+}
+
+func TestAppTokenSource(t *testing.T) {
+	require := require.New(t)
+
+	var requests int
+	var expiresIn time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(http.MethodPost, r.Method)
+		require.Equal("/app/installations/7/access_tokens", r.URL.Path)
+
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"token-%d","expires_at":%q}`,
+			requests, time.Now().Add(expiresIn).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	app, _ := newTestApp(t, server.URL)
+
+	// a token far from its expiration is reused
+	expiresIn = time.Hour
+	ts := app.TokenSource(7)
+	for i := 0; i < 2; i++ {
+		token, err := ts.Token()
+		require.NoError(err)
+		require.Equal("token-1", token.AccessToken)
+	}
+
+	// a token close to its expiration is refreshed
+	expiresIn = installationTokenRefreshMargin - time.Minute
+	ts = app.TokenSource(7)
+	for i := 0; i < 2; i++ {
+		token, err := ts.Token()
+		require.NoError(err)
+		require.Equal(fmt.Sprintf("token-%d", i+2), token.AccessToken)
+	}
+}
+
+func TestAppTokenSourceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	app, _ := newTestApp(t, server.URL)
+	_, err := app.TokenSource(7).Token()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Not Found")
+}
@@ -0,0 +1,293 @@
+// Package chaos provides an http.RoundTripper that injects network and GitHub
+// API faults in the requests, following a seeded schedule, to test that the
+// downloaders recover from them.
+//
+// The same Schedule always injects the same faults in the same requests, as
+// long as they are sent in the same order, so a failing run can be repeated.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault is a kind of failure injected by a Transport
+type Fault int
+
+const (
+	// Latency delays the request, that is then sent
+	Latency Fault = iota
+	// ConnectionReset fails the request with a connection reset error, after
+	// it reached the server
+	ConnectionReset
+	// TruncatedBody cuts the body of the response by half, reading it fails
+	// with io.ErrUnexpectedEOF
+	TruncatedBody
+	// BadGateway is a 502 response, like the ones GitHub returns when a query
+	// takes too long
+	BadGateway
+	// Abuse is a 403 response of the abuse detection mechanism with a
+	// Retry-After header
+	Abuse
+	// AbuseWithoutRetryAfter is a 403 response of the abuse detection
+	// mechanism without a Retry-After header
+	AbuseWithoutRetryAfter
+	// RateLimit is a 403 response with X-RateLimit-Remaining: 0
+	RateLimit
+)
+
+// Faults are all the kinds of faults
+var Faults = []Fault{
+	Latency, ConnectionReset, TruncatedBody, BadGateway,
+	Abuse, AbuseWithoutRetryAfter, RateLimit,
+}
+
+var faultNames = map[Fault]string{
+	Latency:                "latency",
+	ConnectionReset:        "connection reset",
+	TruncatedBody:          "truncated body",
+	BadGateway:             "502 bad gateway",
+	Abuse:                  "403 abuse",
+	AbuseWithoutRetryAfter: "403 abuse without Retry-After",
+	RateLimit:              "403 rate limit",
+}
+
+func (f Fault) String() string {
+	if name, ok := faultNames[f]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("fault %d", int(f))
+}
+
+const (
+	// abuseDocumentationURL is the documentation_url of the abuse responses,
+	// it is used to detect them when they have no Retry-After header
+	abuseDocumentationURL     = "https://developer.github.com/v3/#abuse-rate-limits"
+	rateLimitDocumentationURL = "https://developer.github.com/v3/#rate-limiting"
+)
+
+// Schedule decides which requests get a fault, and which one
+type Schedule struct {
+	// Seed of the random choices
+	Seed int64
+	// Rate is the probability of injecting a fault in each request, from 0
+	// to 1
+	Rate float64
+	// Faults are the kinds of faults injected, all of them when it is empty
+	Faults []Fault
+	// MaxConsecutive bounds the number of consecutive requests with a fault,
+	// so the retries of a request eventually reach the server. 0 means no
+	// bound
+	MaxConsecutive int
+	// Latency is the maximum delay of the Latency faults
+	Latency time.Duration
+	// RetryAfter is the wait requested by the Abuse responses in their
+	// Retry-After header, and the time until the reset of the RateLimit ones.
+	// Both are given in seconds, so it is truncated
+	RetryAfter time.Duration
+}
+
+// Transport is an http.RoundTripper that injects the faults of a Schedule in
+// the requests sent through T
+type Transport struct {
+	T        http.RoundTripper
+	schedule Schedule
+
+	mu          sync.Mutex
+	rand        *rand.Rand
+	requests    int
+	consecutive int
+	injected    map[Fault]int
+}
+
+// NewTransport returns a Transport that sends the requests through rt,
+// injecting the faults of the given Schedule
+func NewTransport(rt http.RoundTripper, s Schedule) *Transport {
+	if len(s.Faults) == 0 {
+		s.Faults = Faults
+	}
+
+	return &Transport{
+		T:        rt,
+		schedule: s,
+		rand:     rand.New(rand.NewSource(s.Seed)),
+		injected: make(map[Fault]int),
+	}
+}
+
+// Requests returns the number of requests received, including the ones
+// answered with faults
+func (t *Transport) Requests() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.requests
+}
+
+// Injected returns the number of faults injected of each kind
+func (t *Transport) Injected() map[Fault]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	injected := make(map[Fault]int, len(t.injected))
+	for f, n := range t.injected {
+		injected[f] = n
+	}
+
+	return injected
+}
+
+// next returns the fault of the next request, if any, and the delay of the
+// Latency faults
+func (t *Transport) next() (fault Fault, delay time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.requests++
+	s := t.schedule
+	if s.MaxConsecutive > 0 && t.consecutive >= s.MaxConsecutive {
+		t.consecutive = 0
+		return 0, 0, false
+	}
+
+	if t.rand.Float64() >= s.Rate {
+		t.consecutive = 0
+		return 0, 0, false
+	}
+
+	fault = s.Faults[t.rand.Intn(len(s.Faults))]
+	if fault == Latency && s.Latency > 0 {
+		delay = time.Duration(t.rand.Int63n(int64(s.Latency)))
+	}
+
+	t.consecutive++
+	t.injected[fault]++
+	return fault, delay, true
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, delay, ok := t.next()
+	if !ok {
+		return t.T.RoundTrip(req)
+	}
+
+	switch fault {
+	case Latency:
+		if err := sleep(req.Context(), delay); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+
+		return t.T.RoundTrip(req)
+	case ConnectionReset:
+		resp, err := t.T.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Body.Close()
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case TruncatedBody:
+		resp, err := t.T.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Body = &truncatedBody{Reader: bytes.NewReader(body[:len(body)/2])}
+		return resp, nil
+	case BadGateway:
+		closeBody(req)
+		return response(req, http.StatusBadGateway, http.Header{"Content-Type": {"text/html"}},
+			"<html><body><h1>502 Bad Gateway</h1></body></html>"), nil
+	case Abuse, AbuseWithoutRetryAfter:
+		closeBody(req)
+		header := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+		if fault == Abuse {
+			header.Set("Retry-After", strconv.Itoa(int(t.schedule.RetryAfter/time.Second)))
+		}
+
+		return response(req, http.StatusForbidden, header,
+			`{"message":"You have triggered an abuse detection mechanism. Please wait a few minutes before you try again.","documentation_url":"`+abuseDocumentationURL+`"}`), nil
+	default:
+		closeBody(req)
+		reset := time.Now().Add(t.schedule.RetryAfter)
+		return response(req, http.StatusForbidden, http.Header{
+			"Content-Type":          {"application/json; charset=utf-8"},
+			"X-Ratelimit-Limit":     {"5000"},
+			"X-Ratelimit-Remaining": {"0"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+		}, `{"message":"API rate limit exceeded for user ID 1.","documentation_url":"`+rateLimitDocumentationURL+`"}`), nil
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// closeBody closes the body of a request that is not sent, the
+// RoundTrippers must always close it
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+func response(req *http.Request, statusCode int, header http.Header, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// truncatedBody is the body of a response whose connection was closed before
+// reading all of it
+type truncatedBody struct {
+	*bytes.Reader
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+func (b *truncatedBody) Close() error {
+	return nil
+}
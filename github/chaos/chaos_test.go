@@ -0,0 +1,155 @@
+package chaos
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"viewer":{"login":"octocat"}}}`))
+	}))
+}
+
+// results sends n requests and returns the outcome of each one
+func results(require *require.Assertions, client *http.Client, url string, n int) []string {
+	var list []string
+	for i := 0; i < n; i++ {
+		resp, err := client.Get(url)
+		if err != nil {
+			list = append(list, "error")
+			continue
+		}
+
+		_, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			list = append(list, "truncated")
+			continue
+		}
+
+		list = append(list, resp.Status)
+	}
+
+	return list
+}
+
+func TestSchedule(t *testing.T) {
+	require := require.New(t)
+
+	server := newServer()
+	defer server.Close()
+
+	schedule := Schedule{Seed: 1, Rate: 0.5, MaxConsecutive: 2, RetryAfter: time.Minute}
+	first := NewTransport(http.DefaultTransport, schedule)
+	second := NewTransport(http.DefaultTransport, schedule)
+
+	outcomes := results(require, &http.Client{Transport: first}, server.URL, 100)
+	require.Equal(outcomes, results(require, &http.Client{Transport: second}, server.URL, 100))
+	require.Equal(first.Injected(), second.Injected())
+	require.Equal(100, first.Requests())
+
+	var total int
+	for _, f := range Faults {
+		require.True(first.Injected()[f] > 0, f.String())
+		total += first.Injected()[f]
+	}
+
+	require.True(total > 25 && total < 75, "%d faults", total)
+
+	// another seed injects other faults
+	schedule.Seed = 2
+	third := NewTransport(http.DefaultTransport, schedule)
+	require.NotEqual(outcomes, results(require, &http.Client{Transport: third}, server.URL, 100))
+
+	// there are at most 2 consecutive faults
+	schedule.Rate = 1
+	schedule.Faults = []Fault{BadGateway}
+	limited := NewTransport(http.DefaultTransport, schedule)
+	require.Equal([]string{"502 Bad Gateway", "502 Bad Gateway", "200 OK", "502 Bad Gateway", "502 Bad Gateway", "200 OK"},
+		results(require, &http.Client{Transport: limited}, server.URL, 6))
+
+	// without faults
+	none := NewTransport(http.DefaultTransport, Schedule{Seed: 1})
+	require.Equal([]string{"200 OK", "200 OK", "200 OK"}, results(require, &http.Client{Transport: none}, server.URL, 3))
+	require.Empty(none.Injected())
+}
+
+func TestFaults(t *testing.T) {
+	require := require.New(t)
+
+	server := newServer()
+	defer server.Close()
+
+	client := func(f Fault) *http.Client {
+		return &http.Client{Transport: NewTransport(http.DefaultTransport, Schedule{
+			Seed:       1,
+			Rate:       1,
+			Faults:     []Fault{f},
+			Latency:    50 * time.Millisecond,
+			RetryAfter: 30 * time.Second,
+		})}
+	}
+
+	// latency
+	resp, err := client(Latency).Get(server.URL)
+	require.NoError(err)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client(Latency).Do(req.WithContext(ctx))
+	require.Error(err)
+
+	// connection reset
+	_, err = client(ConnectionReset).Get(server.URL)
+	require.Error(err)
+	require.Contains(err.Error(), syscall.ECONNRESET.Error())
+
+	// truncated body
+	resp, err = client(TruncatedBody).Get(server.URL)
+	require.NoError(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.Error(err)
+	require.Equal(`{"data":{"viewer":{`, string(body))
+
+	// 502
+	resp, err = client(BadGateway).Get(server.URL)
+	require.NoError(err)
+	require.Equal(http.StatusBadGateway, resp.StatusCode)
+
+	// abuse
+	resp, err = client(Abuse).Get(server.URL)
+	require.NoError(err)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	require.Equal("30", resp.Header.Get("Retry-After"))
+	body, err = ioutil.ReadAll(resp.Body)
+	require.NoError(err)
+	require.Contains(string(body), "abuse detection mechanism")
+
+	resp, err = client(AbuseWithoutRetryAfter).Get(server.URL)
+	require.NoError(err)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	require.Empty(resp.Header.Get("Retry-After"))
+
+	// rate limit
+	now := time.Now()
+	resp, err = client(RateLimit).Get(server.URL)
+	require.NoError(err)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	require.Equal("0", resp.Header.Get("X-RateLimit-Remaining"))
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	require.NoError(err)
+	require.InDelta(now.Add(30*time.Second).Unix(), reset, 1)
+}
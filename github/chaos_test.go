@@ -0,0 +1,71 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github/chaos"
+	"github.com/src-d/metadata-retrieval/github/fakeserver"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// chaosClient returns a client of the server that injects the faults of the
+// schedule, with the transports that recover from them. The lockouts after the
+// rate and abuse limits end without waiting for them
+func chaosClient(server *fakeserver.Server, schedule chaos.Schedule) (*http.Client, *chaos.Transport) {
+	client := server.Client()
+	faults := chaos.NewTransport(client.Transport, schedule)
+
+	rateLimit := NewRateLimitTransport(faults, &testutils.LoggerMock{})
+	rateLimit.sleep = func(d time.Duration) {
+		rateLimit.Lock()
+		rateLimit.lockedUntil = make(map[string]time.Time)
+		rateLimit.Unlock()
+	}
+
+	client.Transport = NewRetryTransport(rateLimit, fastRetryPolicy())
+	return client, faults
+}
+
+func TestDownloadRepositoryChaos(t *testing.T) {
+	require := require.New(t)
+
+	dataset := fakeserver.Seed(4, "acme", fakeserver.DefaultSize)
+	server := fakeserver.New(dataset)
+	defer server.Close()
+
+	clean := &testutils.Snapshot{}
+	d, err := NewDownloader(server.Client(), clean)
+	require.NoError(err)
+	require.NoError(d.DownloadRepository(context.TODO(), "acme", "repository-1", 0))
+
+	injected := make(map[chaos.Fault]int)
+	for seed := int64(1); seed <= 3; seed++ {
+		client, faults := chaosClient(server, chaos.Schedule{
+			Seed:           seed,
+			Rate:           0.2,
+			MaxConsecutive: 2,
+			Latency:        20 * time.Millisecond,
+		})
+
+		storer := &testutils.Snapshot{}
+		d, err := NewDownloader(client, storer)
+		require.NoError(err)
+
+		require.NoError(d.DownloadRepository(context.TODO(), "acme", "repository-1", 0), "seed %d", seed)
+		require.Equal(clean.Lines(), storer.Lines(), "seed %d", seed)
+
+		for f, n := range faults.Injected() {
+			injected[f] += n
+		}
+	}
+
+	// all the faults were recovered
+	for _, f := range chaos.Faults {
+		require.True(injected[f] > 0, "%s was not injected", f)
+	}
+}
@@ -0,0 +1,184 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// contributorsBatchSize is the number of user profiles requested per query
+const contributorsBatchSize = 50
+
+// Contributors keeps track of the contributor profiles already downloaded for
+// each version. The same instance can be shared by several Downloaders, so
+// each profile is fetched only once per version even if the user contributed
+// to more than one repository. The profiles are marked as downloaded once the
+// transaction that saved them is committed: the Downloaders that see the same
+// users at the same time may both download them, but none is missing when one
+// of them fails
+type Contributors struct {
+	sync.Mutex
+	seen map[int]map[string]bool
+}
+
+// NewContributors returns an empty Contributors
+func NewContributors() *Contributors {
+	return &Contributors{seen: make(map[int]map[string]bool)}
+}
+
+// missing returns the given user node IDs that were not downloaded yet for
+// the version
+func (c *Contributors) missing(version int, ids []string) []string {
+	c.Lock()
+	defer c.Unlock()
+
+	var missing []string
+	for _, id := range ids {
+		if !c.seen[version][id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing
+}
+
+// add marks the given user node IDs as downloaded for the version, after
+// their profiles are committed
+func (c *Contributors) add(version int, ids []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	seen, ok := c.seen[version]
+	if !ok {
+		seen = make(map[string]bool)
+		c.seen[version] = seen
+	}
+
+	for _, id := range ids {
+		seen[id] = true
+	}
+}
+
+// actorsRecorder is a Storer that records the users seen in the saved
+// resources, before passing them to the wrapped Storer
+type actorsRecorder struct {
+	Storer
+
+	seen map[string]bool
+	ids  []string
+}
+
+func newActorsRecorder(storer Storer) *actorsRecorder {
+	return &actorsRecorder{Storer: storer, seen: make(map[string]bool)}
+}
+
+// record keeps the node ID of the actors that are users; bots, mannequins
+// and deleted accounts do not have a user profile
+func (s *actorsRecorder) record(actors ...graphql.Actor) {
+	for _, actor := range actors {
+		if actor.Typename != "User" || actor.User.ID == "" || s.seen[actor.User.ID] {
+			continue
+		}
+
+		s.seen[actor.User.ID] = true
+		s.ids = append(s.ids, actor.User.ID)
+	}
+}
+
+func (s *actorsRecorder) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *graphql.Issue, assignees []string, labels []string) error {
+	s.record(issue.Author)
+	for _, node := range issue.ClosedBy.Nodes {
+		s.record(node.ClosedEvent.Actor)
+	}
+
+	return s.Storer.SaveIssue(ctx, repositoryOwner, repositoryName, issue, assignees, labels)
+}
+
+func (s *actorsRecorder) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *graphql.IssueComment) error {
+	s.record(comment.Author)
+	return s.Storer.SaveIssueComment(ctx, repositoryOwner, repositoryName, issueNumber, comment)
+}
+
+func (s *actorsRecorder) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *graphql.PullRequest, assignees []string, labels []string) error {
+	s.record(pr.Author, pr.MergedBy)
+	return s.Storer.SavePullRequest(ctx, repositoryOwner, repositoryName, pr, assignees, labels)
+}
+
+func (s *actorsRecorder) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *graphql.IssueComment) error {
+	s.record(comment.Author)
+	return s.Storer.SavePullRequestComment(ctx, repositoryOwner, repositoryName, pullRequestNumber, comment)
+}
+
+func (s *actorsRecorder) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) error {
+	s.record(review.Author)
+	return s.Storer.SavePullRequestReview(ctx, repositoryOwner, repositoryName, pullRequestNumber, review)
+}
+
+func (s *actorsRecorder) SavePullRequestReviewComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, pullRequestReviewID int, comment *graphql.PullRequestReviewComment) error {
+	s.record(comment.Author)
+	return s.Storer.SavePullRequestReviewComment(ctx, repositoryOwner, repositoryName, pullRequestNumber, pullRequestReviewID, comment)
+}
+
+func (s *actorsRecorder) SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error {
+	s.record(comment.Author)
+	return s.Storer.SaveCommitComment(ctx, repositoryOwner, repositoryName, comment)
+}
+
+type contributorsQ struct {
+	Nodes []struct {
+		User graphql.UserExtendedFields `graphql:"... on User"`
+	} `graphql:"nodes(ids: $ids)"`
+}
+
+// downloadContributors downloads and saves the profiles of the given users
+// that were not downloaded yet for the version. They are marked as downloaded
+// by the caller once the transaction is committed
+func (d Downloader) downloadContributors(ctx context.Context, version int, ids []string) error {
+	missing := d.contributors.missing(version, ids)
+	for i := 0; i < len(missing); i += contributorsBatchSize {
+		end := i + contributorsBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		if err := d.downloadContributorsBatch(ctx, missing[i:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d Downloader) downloadContributorsBatch(ctx context.Context, ids []string) error {
+	nodeIDs := make([]githubv4.ID, len(ids))
+	for i, id := range ids {
+		nodeIDs[i] = githubv4.ID(id)
+	}
+
+	var q contributorsQ
+	err := d.query(ctx, &q, map[string]interface{}{"ids": nodeIDs})
+	if err != nil {
+		return fmt.Errorf("contributors query failed: %v", err)
+	}
+
+	for _, node := range q.Nodes {
+		if node.User.ID == "" {
+			continue
+		}
+
+		user, err := d.userExtended(ctx, node.User)
+		if err != nil {
+			return err
+		}
+
+		if err := d.storer.SaveContributor(ctx, user); err != nil {
+			return fmt.Errorf("failed to save contributor %v: %v", user.Login, err)
+		}
+	}
+
+	return nil
+}
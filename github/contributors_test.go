@@ -0,0 +1,103 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContributorsMissing(t *testing.T) {
+	require := require.New(t)
+
+	c := NewContributors()
+	require.Equal([]string{"a", "b"}, c.missing(1, []string{"a", "b"}))
+
+	c.add(1, []string{"a", "b"})
+	require.Equal([]string{"c"}, c.missing(1, []string{"a", "c"}))
+	require.Equal([]string{"a"}, c.missing(2, []string{"a"}))
+}
+
+func TestActorsRecorder(t *testing.T) {
+	require := require.New(t)
+
+	r := newActorsRecorder(&testutils.Memory{})
+
+	user := graphql.Actor{Login: "alice", Typename: "User"}
+	user.User.ID = "U1"
+	bot := graphql.Actor{Login: "dependabot", Typename: "Bot"}
+
+	issue := &graphql.Issue{}
+	issue.Author = user
+	require.NoError(r.SaveIssue(context.TODO(), "src-d", "gitbase", issue, nil, nil))
+
+	comment := &graphql.IssueComment{Author: bot}
+	require.NoError(r.SaveIssueComment(context.TODO(), "src-d", "gitbase", 1, comment))
+
+	pr := &graphql.PullRequest{}
+	pr.Author = user
+	pr.MergedBy = graphql.Actor{Login: "bob", Typename: "User"}
+	pr.MergedBy.User.ID = "U2"
+	require.NoError(r.SavePullRequest(context.TODO(), "src-d", "gitbase", pr, nil, nil))
+
+	require.Equal([]string{"U1", "U2"}, r.ids)
+}
+
+func TestDownloadContributors(t *testing.T) {
+	require := require.New(t)
+
+	var requested [][]string
+	client := githubv4.NewClient(&http.Client{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			var body struct {
+				Variables struct {
+					IDs []string
+				}
+			}
+			require.NoError(json.NewDecoder(req.Body).Decode(&body))
+			requested = append(requested, body.Variables.IDs)
+
+			var nodes []string
+			for _, id := range body.Variables.IDs {
+				nodes = append(nodes, fmt.Sprintf(`{"id":%q,"login":"login-%s"}`, id, id))
+			}
+
+			data := fmt.Sprintf(`{"data":{"nodes":[%s]}}`, strings.Join(nodes, ","))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(data)),
+				Header:     make(http.Header),
+			}
+		}),
+	})
+
+	contributors := NewContributors()
+	storer1, storer2 := &testutils.Memory{}, &testutils.Memory{}
+	d1 := &Downloader{storer: storer1, client: client, contributors: contributors}
+	d2 := &Downloader{storer: storer2, client: client, contributors: contributors}
+
+	// the profiles of a transaction that was not committed, like when the
+	// download fails, are downloaded again
+	require.NoError(d1.downloadContributors(context.TODO(), 0, []string{"U1", "U2"}))
+	require.NoError(d2.downloadContributors(context.TODO(), 0, []string{"U2", "U3"}))
+	require.Equal([][]string{{"U1", "U2"}, {"U2", "U3"}}, requested)
+
+	contributors.add(0, []string{"U1", "U2"})
+	requested = nil
+	require.NoError(d2.downloadContributors(context.TODO(), 0, []string{"U2", "U3"}))
+
+	require.Equal([][]string{{"U3"}}, requested)
+	require.Len(storer1.Contributors, 2)
+	require.Len(storer2.Contributors, 3)
+	require.Equal("login-U3", storer2.Contributors[2].Login)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/src-d/metadata-retrieval/github/graphql"
 	"github.com/src-d/metadata-retrieval/utils/ctxlog"
@@ -31,8 +32,24 @@ var (
 	pullRequestReviewCommentsType = connectionType{"pullRequestReviewComments", 5, false}
 	labelsType                    = connectionType{"labels", 2, false}
 	membersWithRole               = connectionType{"membersWithRole", 100, true}
+	projectsType                  = connectionType{"projects", 10, true}
+	projectColumnsType            = connectionType{"projectColumns", 10, false}
+	projectCardsType              = connectionType{"projectCards", 25, false}
+	projectsV2Type                = connectionType{"projectsV2", 10, true}
+	projectV2ItemsType            = connectionType{"projectV2Items", 25, false}
+	projectV2FieldValuesType      = connectionType{"projectV2FieldValues", 20, false}
+	commitCommentsType            = connectionType{"commitComments", 50, true}
 )
 
+// connectionTypes are all the connections, to find the ones requested by a
+// query from its variables
+var connectionTypes = []connectionType{
+	topicsType, assigneesType, issuesType, issueCommentsType, pullRequestsType,
+	pullRequestReviewsType, pullRequestReviewCommentsType, labelsType, membersWithRole,
+	projectsType, projectColumnsType, projectCardsType,
+	projectsV2Type, projectV2ItemsType, projectV2FieldValuesType, commitCommentsType,
+}
+
 // Storer is an interface required by Downloader to persist the downloaded data
 type Storer interface {
 	SaveOrganization(ctx context.Context, organization *graphql.Organization) error
@@ -44,6 +61,13 @@ type Storer interface {
 	SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *graphql.IssueComment) error
 	SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) error
 	SavePullRequestReviewComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, pullRequestReviewID int, comment *graphql.PullRequestReviewComment) error
+	SaveProject(ctx context.Context, owner, repositoryName string, project *graphql.Project) error
+	SaveProjectColumn(ctx context.Context, projectID string, column *graphql.ProjectColumn) error
+	SaveProjectCard(ctx context.Context, projectID string, columnID string, card *graphql.ProjectCard) error
+	SaveProjectV2(ctx context.Context, owner, repositoryName string, project *graphql.ProjectV2) error
+	SaveProjectV2Item(ctx context.Context, projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) error
+	SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error
+	SaveContributor(ctx context.Context, user *graphql.UserExtended) error
 
 	Begin() error
 	Commit() error
@@ -57,23 +81,140 @@ type Storer interface {
 type Downloader struct {
 	storer Storer
 	client *githubv4.Client
+
+	// projects enables downloading the organization and repository projects,
+	// set by WithProjects
+	projects bool
+	// projectsV2 enables downloading the projects (v2) along with the classic
+	// ones, older GitHub Enterprise Server versions do not have them
+	projectsV2 bool
+	// commitComments enables downloading the comments made on repository commits
+	commitComments bool
+	// contributors, when set, enables downloading the profiles of the users
+	// that contributed to the downloaded repositories
+	contributors *Contributors
+
+	// graphqlURL and restURL are the API endpoints of a GitHub Enterprise
+	// Server, empty for github.com
+	graphqlURL string
+	restURL    string
+
+	// restFallback enables filling the fields missing in the GraphQL API
+	// with the REST client
+	restFallback bool
+	rest         *RESTClient
+
+	// pageSizes, when set, enables adapting the page sizes of each
+	// repository and organization, and pages are the ones of the downloaded
+	// one
+	pageSizes *PageSizes
+	pages     pageSizer
+
+	// skipped records the items that could not be downloaded because of
+	// FORBIDDEN or NOT_FOUND errors, only set by DownloadRepository and
+	// DownloadOrganization, and skippedLog keeps the ones of the last download
+	skipped    *skippedItems
+	skippedLog *skippedLog
+}
+
+// Option configures optional behaviour of a Downloader
+type Option func(*Downloader)
+
+// WithContributors makes DownloadRepository download the profile of every
+// user seen in the repository resources, even if they are not organization
+// members. The same Contributors can be given to several Downloaders to
+// share the deduplication of the profiles
+func WithContributors(contributors *Contributors) Option {
+	return func(d *Downloader) {
+		d.contributors = contributors
+	}
+}
+
+// WithProjects makes the Downloader download the classic projects and the
+// projects (v2) of the organizations and repositories. The token needs the
+// read:project scope, the downloads fail with INSUFFICIENT_SCOPES otherwise
+func WithProjects() Option {
+	return func(d *Downloader) {
+		d.projects = true
+	}
+}
+
+// WithRESTFallback makes the Downloader request the REST API for the fields
+// that the GraphQL API does not provide: the email and gists of the users, and
+// the comment that a PR review comment replies to. It costs one more request
+// for each user and for each pull request with reviews
+func WithRESTFallback() Option {
+	return func(d *Downloader) {
+		d.restFallback = true
+	}
+}
+
+// WithPageSizes makes the Downloader adapt the page sizes of the connections
+// of each repository and organization: the queries that time out are retried
+// with smaller pages, instead of retrying them as they are, and the pages grow
+// after fast queries. It starts with the sizes of the given PageSizes, learned
+// in previous downloads, and keeps there the ones it learns
+func WithPageSizes(pageSizes *PageSizes) Option {
+	return func(d *Downloader) {
+		d.pageSizes = pageSizes
+	}
 }
 
 // NewDownloader creates a new Downloader that will store the GitHub metadata
 // in the given DB. The HTTP client is expected to have the proper
 // authentication setup
-func NewDownloader(httpClient *http.Client, storer Storer) (*Downloader, error) {
-	return &Downloader{
-		storer: storer,
-		client: githubv4.NewClient(httpClient),
-	}, nil
+func NewDownloader(httpClient *http.Client, storer Storer, opts ...Option) (*Downloader, error) {
+	d := &Downloader{
+		storer:         storer,
+		projectsV2:     true,
+		commitComments: true,
+		skippedLog:     &skippedLog{},
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if d.restFallback {
+		d.rest = NewRESTClient(httpClient, d.restURL)
+	}
+
+	graphqlClient := newGraphQLHTTPClient(httpClient)
+	if d.graphqlURL == "" {
+		d.client = githubv4.NewClient(graphqlClient)
+		return d, nil
+	}
+
+	if d.restURL == "" {
+		return nil, fmt.Errorf("the REST API URL of the server with the GraphQL endpoint %s is required", d.graphqlURL)
+	}
+
+	d.client = githubv4.NewEnterpriseClient(d.graphqlURL, graphqlClient)
+
+	version, err := enterpriseVersion(httpClient, d.restURL)
+	if err != nil {
+		return nil, err
+	}
+
+	d.disableUnsupported(version)
+	return d, nil
 }
 
 // DownloadRepository downloads the metadata for the given repository and all
-// its resources (issues, PRs, comments, reviews)
+// its resources (issues, PRs, comments, reviews, projects, commit comments)
 func (d Downloader) DownloadRepository(ctx context.Context, owner string, name string, version int) error {
 	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"owner": owner, "repo": name})
 
+	var actors *actorsRecorder
+	if d.contributors != nil {
+		actors = newActorsRecorder(d.storer)
+		d.storer = actors
+	}
+
+	d.pages = d.pageSizer(owner + "/" + name)
+	d.skipped = &skippedItems{}
+	defer d.reportSkipped(ctx)
+
 	d.storer.Version(version)
 
 	var err error
@@ -88,7 +229,10 @@ func (d Downloader) DownloadRepository(ctx context.Context, owner string, name s
 			return
 		}
 
-		d.storer.Commit()
+		// the contributors are only marked as downloaded once they are saved
+		if d.storer.Commit() == nil && actors != nil {
+			d.contributors.add(version, actors.ids)
+		}
 	}()
 
 	var q struct {
@@ -108,11 +252,11 @@ func (d Downloader) DownloadRepository(ctx context.Context, owner string, name s
 		pullRequestReviewCommentsType, pullRequestReviewsType, pullRequestsType,
 	}
 	for _, c := range connections {
-		variables[c.Page()] = c.PageSize
+		variables[c.Page()] = d.pages.first(c)
 		variables[c.Cursor()] = (*githubv4.String)(nil)
 	}
 
-	err = d.client.Query(ctx, &q, variables)
+	err = d.queryFirst(ctx, &q, variables)
 	if err != nil {
 		return fmt.Errorf("first query failed: %v", err)
 	}
@@ -140,9 +284,130 @@ func (d Downloader) DownloadRepository(ctx context.Context, owner string, name s
 		return err
 	}
 
+	if d.projects {
+		err = d.downloadRepositoryProjects(ctx, owner, name, &q.Repository)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.commitComments {
+		err = d.downloadCommitComments(ctx, owner, name, &q.Repository)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.contributors != nil {
+		err = d.downloadContributors(ctx, version, actors.ids)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// DownloadIssue downloads the metadata for the given issue and all its
+// resources (assignees, labels, comments)
+func (d Downloader) DownloadIssue(ctx context.Context, owner string, name string, number int, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"owner": owner, "repo": name, "issue": number})
+	d.pages = d.pageSizer(owner + "/" + name)
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var q struct {
+		Repository struct {
+			Issue graphql.Issue `graphql:"issue(number: $issueNumber)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":       githubv4.String(owner),
+		"name":        githubv4.String(name),
+		"issueNumber": githubv4.Int(number),
+	}
+	connections := []connectionType{assigneesType, issueCommentsType, labelsType}
+	for _, c := range connections {
+		variables[c.Page()] = d.pages.first(c)
+		variables[c.Cursor()] = (*githubv4.String)(nil)
+	}
+
+	err = d.queryFirst(ctx, &q, variables)
+	if err != nil {
+		return fmt.Errorf("issue query failed: %v", err)
+	}
+
+	err = d.processIssue(ctx, owner, name, &q.Repository.Issue)
+	return err
+}
+
+// DownloadPullRequest downloads the metadata for the given PR and all its
+// resources (assignees, labels, comments, reviews, review comments)
+func (d Downloader) DownloadPullRequest(ctx context.Context, owner string, name string, number int, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"owner": owner, "repo": name, "pr": number})
+	d.pages = d.pageSizer(owner + "/" + name)
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var q struct {
+		Repository struct {
+			PullRequest graphql.PullRequest `graphql:"pullRequest(number: $prNumber)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":    githubv4.String(owner),
+		"name":     githubv4.String(name),
+		"prNumber": githubv4.Int(number),
+	}
+	connections := []connectionType{
+		assigneesType, issueCommentsType, labelsType,
+		pullRequestReviewCommentsType, pullRequestReviewsType}
+	for _, c := range connections {
+		variables[c.Page()] = d.pages.first(c)
+		variables[c.Cursor()] = (*githubv4.String)(nil)
+	}
+
+	err = d.queryFirst(ctx, &q, variables)
+	if err != nil {
+		return fmt.Errorf("pull request query failed: %v", err)
+	}
+
+	err = d.processPullRequest(ctx, owner, name, &q.Repository.PullRequest)
+	return err
+}
+
 func (d Downloader) ListRepositories(ctx context.Context, name string, noForks bool) ([]string, error) {
 	repos := []string{}
 
@@ -173,7 +438,7 @@ func (d Downloader) ListRepositories(ctx context.Context, name string, noForks b
 			} `graphql:"organization(login: $login)"`
 		}
 
-		err := d.client.Query(ctx, &q, variables)
+		err := d.query(ctx, &q, variables)
 		if err != nil {
 			return nil, fmt.Errorf("failed to query organization %v repositories: %v", name, err)
 		}
@@ -197,7 +462,7 @@ func (d Downloader) RateRemaining(ctx context.Context) (int, error) {
 		}
 	}
 
-	err := d.client.Query(ctx, &q, nil)
+	err := d.query(ctx, &q, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query remaining rate limit: %v", err)
 	}
@@ -231,6 +496,126 @@ func getPerPage(total, count int, fallback, limit githubv4.Int) githubv4.Int {
 	return perPage
 }
 
+// pageSizer returns the page sizes of the given repository or organization
+func (d Downloader) pageSizer(key string) pageSizer {
+	return pageSizer{sizes: d.pageSizes, key: key}
+}
+
+// query runs the GraphQL query, returning its errors typed. In
+// DownloadRepository and DownloadOrganization, the responses with errors only
+// about fields of nested items are partial but usable, those are skipped and
+// recorded
+func (d Downloader) query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	ctx, errs := withGraphQLErrors(ctx)
+	err := d.client.Query(ctx, q, variables)
+	if err == nil {
+		return nil
+	}
+
+	err = unwrapGraphQLError(err, *errs)
+	if d.skipped != nil && isSkippable(err) && errs.nested() {
+		d.skipped.recordFields(ctx, *errs)
+		return nil
+	}
+
+	return err
+}
+
+// queryFirst runs a query of the first pages of the connections in its
+// variables. WithPageSizes, when the query times out it is retried with half
+// the sizes
+func (d Downloader) queryFirst(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	connections := queryConnections(variables, connectionType{})
+	for {
+		queryCtx := ctx
+		if d.pages.canShrink(variables, connections) {
+			queryCtx = withoutTimeoutRetries(ctx)
+		}
+
+		err := d.query(queryCtx, q, variables)
+		if err != nil && isTimeout(err) && d.shrinkFirstPages(ctx, variables, connections) {
+			continue
+		}
+
+		return err
+	}
+}
+
+// queryPage queries the next page of the connection, adapting its size
+// WithPageSizes: when the query times out it is retried with half the size,
+// or with half the first pages of the connections nested in it once the page
+// is the smallest one, and the size grows after a fast query
+func (d Downloader) queryPage(ctx context.Context, t connectionType, q Query, variables map[string]interface{}, total, count int) error {
+	pages := d.pages
+	nested := queryConnections(variables, t)
+	for {
+		limit := pages.limit(t)
+		perPage := getPerPage(total, count, pages.first(t), limit)
+		variables[t.Page()] = perPage
+		for _, c := range nested {
+			variables[c.Page()] = pages.first(c)
+		}
+
+		queryCtx := ctx
+		if pages.sizes != nil && perPage > minPageSize || pages.canShrink(variables, nested) {
+			queryCtx = withoutTimeoutRetries(ctx)
+		}
+
+		start := time.Now()
+		err := d.query(queryCtx, q, variables)
+		if err != nil && isTimeout(err) && pages.shrink(t, perPage) {
+			ctxlog.Get(ctx).Warningf("query to %s timed out with pages of %d, retrying with %d", t.Name, perPage, pages.limit(t))
+			continue
+		}
+
+		if err != nil && isTimeout(err) && d.shrinkFirstPages(ctx, variables, nested) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if perPage >= limit && time.Since(start) < fastQuery {
+			pages.grow(t)
+		}
+
+		return nil
+	}
+}
+
+// queryConnections returns the connections with a page in the variables of a
+// query but t, the ones nested in the connection t
+func queryConnections(variables map[string]interface{}, t connectionType) []connectionType {
+	var connections []connectionType
+	for _, c := range connectionTypes {
+		if _, ok := variables[c.Page()]; ok && c != t {
+			connections = append(connections, c)
+		}
+	}
+
+	return connections
+}
+
+// shrinkFirstPages halves the sizes of the first pages of the connections
+// after the query timed out, it returns false if none of them can be smaller
+func (d Downloader) shrinkFirstPages(ctx context.Context, variables map[string]interface{}, connections []connectionType) bool {
+	var shrunk bool
+	for _, c := range connections {
+		size := variables[c.Page()].(githubv4.Int)
+		if d.pages.shrink(c, size) {
+			variables[c.Page()] = d.pages.first(c)
+			shrunk = true
+		}
+	}
+
+	if shrunk {
+		ctxlog.Get(ctx).Warningf("query timed out, retrying with smaller first pages")
+	}
+
+	return shrunk
+}
+
 func (d Downloader) downloadConnection(
 	ctx context.Context,
 	t connectionType,
@@ -251,21 +636,19 @@ func (d Downloader) downloadConnection(
 	}
 
 	var count int
-	limit := githubv4.Int(100)
-	// github timeouts and returns 502 too often with 100 limit
-	if t == pullRequestsType {
-		limit = t.PageSize
-	}
 	for res.GetPageInfo().HasNextPage {
 		count += res.Len()
-		variables[t.Page()] = getPerPage(res.GetTotalCount(), count, t.PageSize, limit)
 		variables[t.Cursor()] = githubv4.String(res.GetPageInfo().EndCursor)
 
 		if t.Log {
 			logger.Infof("%d/%d %s downloaded", count, res.GetTotalCount(), t.Name)
 		}
 
-		if err := d.client.Query(ctx, q, variables); err != nil {
+		if err := d.queryPage(ctx, t, q, variables, res.GetTotalCount(), count); err != nil {
+			if d.skipConnection(ctx, t.Name, variables["id"], err) {
+				return nil
+			}
+
 			return fmt.Errorf("query to %s failed: %s", t.Name, err)
 		}
 
@@ -332,28 +715,14 @@ func (d Downloader) downloadIssues(ctx context.Context, owner string, name strin
 	}
 	connections := []connectionType{assigneesType, issueCommentsType, labelsType}
 	for _, c := range connections {
-		variables[c.Page()] = c.PageSize
+		variables[c.Page()] = d.pages.first(c)
 		variables[c.Cursor()] = (*githubv4.String)(nil)
 	}
 
 	process := func(res Connection) error {
 		issues := res.(graphql.IssueConnection)
 		for _, issue := range issues.Nodes {
-			assignees, err := d.downloadIssueAssignees(ctx, &issue)
-			if err != nil {
-				return err
-			}
-
-			labels, err := d.downloadIssueLabels(ctx, &issue)
-			if err != nil {
-				return err
-			}
-
-			if err := d.storer.SaveIssue(ctx, owner, name, &issue, assignees, labels); err != nil {
-				return err
-			}
-
-			if err := d.downloadIssueComments(ctx, owner, name, &issue); err != nil {
+			if err := d.processIssue(ctx, owner, name, &issue); err != nil {
 				return err
 			}
 		}
@@ -364,6 +733,26 @@ func (d Downloader) downloadIssues(ctx context.Context, owner string, name strin
 	return d.downloadConnection(ctx, issuesType, repository.Issues, &q, variables, process)
 }
 
+// processIssue downloads the remaining pages of the issue nested connections
+// and saves the issue and its comments
+func (d Downloader) processIssue(ctx context.Context, owner string, name string, issue *graphql.Issue) error {
+	assignees, err := d.downloadIssueAssignees(ctx, issue)
+	if err != nil {
+		return err
+	}
+
+	labels, err := d.downloadIssueLabels(ctx, issue)
+	if err != nil {
+		return err
+	}
+
+	if err := d.storer.SaveIssue(ctx, owner, name, issue, assignees, labels); err != nil {
+		return err
+	}
+
+	return d.downloadIssueComments(ctx, owner, name, issue)
+}
+
 type issueAssigneesQ struct {
 	Node struct {
 		Issue struct {
@@ -487,31 +876,14 @@ func (d Downloader) downloadPullRequests(ctx context.Context, owner string, name
 		assigneesType, issueCommentsType, labelsType,
 		pullRequestReviewCommentsType, pullRequestReviewsType}
 	for _, c := range connections {
-		variables[c.Page()] = c.PageSize
+		variables[c.Page()] = d.pages.first(c)
 		variables[c.Cursor()] = (*githubv4.String)(nil)
 	}
 
 	process := func(res Connection) error {
 		prs := res.(graphql.PullRequestConnection)
 		for _, pr := range prs.Nodes {
-			assignees, err := d.downloadPullRequestAssignees(ctx, &pr)
-			if err != nil {
-				return err
-			}
-
-			labels, err := d.downloadPullRequestLabels(ctx, &pr)
-			if err != nil {
-				return err
-			}
-
-			if err := d.storer.SavePullRequest(ctx, owner, name, &pr, assignees, labels); err != nil {
-				return err
-			}
-
-			if err := d.downloadPullRequestComments(ctx, owner, name, &pr); err != nil {
-				return err
-			}
-			if err := d.downloadPullRequestReviews(ctx, owner, name, &pr); err != nil {
+			if err := d.processPullRequest(ctx, owner, name, &pr); err != nil {
 				return err
 			}
 		}
@@ -522,6 +894,30 @@ func (d Downloader) downloadPullRequests(ctx context.Context, owner string, name
 	return d.downloadConnection(ctx, pullRequestsType, repository.PullRequests, &q, variables, process)
 }
 
+// processPullRequest downloads the remaining pages of the PR nested
+// connections and saves the PR, its comments and its reviews
+func (d Downloader) processPullRequest(ctx context.Context, owner string, name string, pr *graphql.PullRequest) error {
+	assignees, err := d.downloadPullRequestAssignees(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	labels, err := d.downloadPullRequestLabels(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	if err := d.storer.SavePullRequest(ctx, owner, name, pr, assignees, labels); err != nil {
+		return err
+	}
+
+	if err := d.downloadPullRequestComments(ctx, owner, name, pr); err != nil {
+		return err
+	}
+
+	return d.downloadPullRequestReviews(ctx, owner, name, pr)
+}
+
 type pullRequestAssigneesQ struct {
 	Node struct {
 		PullRequest struct {
@@ -642,9 +1038,18 @@ func (d Downloader) downloadPullRequestReviews(ctx context.Context, owner string
 	variables := map[string]interface{}{
 		"id": githubv4.ID(pr.ID),
 	}
-	variables[pullRequestReviewCommentsType.Page()] = pullRequestReviewCommentsType.PageSize
+	variables[pullRequestReviewCommentsType.Page()] = d.pages.first(pullRequestReviewCommentsType)
 	variables[pullRequestReviewCommentsType.Cursor()] = (*githubv4.String)(nil)
 
+	var inReplyTo map[int]int
+	if d.rest != nil && pr.Reviews.TotalCount > 0 {
+		var err error
+		inReplyTo, err = d.reviewCommentsInReplyTo(ctx, owner, name, pr.Number)
+		if err != nil {
+			return err
+		}
+	}
+
 	process := func(res Connection) error {
 		reviews := res.(graphql.PullRequestReviewConnection)
 		for _, review := range reviews.Nodes {
@@ -652,7 +1057,7 @@ func (d Downloader) downloadPullRequestReviews(ctx context.Context, owner string
 			if err != nil {
 				return fmt.Errorf("failed to save PR review for PR #%v: %v", pr.Number, err)
 			}
-			if err := d.downloadReviewComments(ctx, owner, name, pr.Number, &review); err != nil {
+			if err := d.downloadReviewComments(ctx, owner, name, pr.Number, &review, inReplyTo); err != nil {
 				return err
 			}
 		}
@@ -675,7 +1080,9 @@ func (q *reviewCommentsQ) Connection() Connection {
 	return q.Node.PullRequestReview.Comments
 }
 
-func (d Downloader) downloadReviewComments(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) error {
+// downloadReviewComments downloads the comments of the given review,
+// inReplyTo contains the comment replied by each comment with WithRESTFallback
+func (d Downloader) downloadReviewComments(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview, inReplyTo map[int]int) error {
 	var q reviewCommentsQ
 	variables := map[string]interface{}{
 		"id": githubv4.ID(review.ID),
@@ -683,7 +1090,12 @@ func (d Downloader) downloadReviewComments(ctx context.Context, repositoryOwner,
 
 	process := func(res Connection) error {
 		comments := res.(graphql.PullRequestReviewCommentConnection)
-		for _, comment := range comments.Nodes {
+		for _, fields := range comments.Nodes {
+			comment := graphql.PullRequestReviewComment{
+				PullRequestReviewCommentFields: fields,
+				InReplyTo:                      inReplyTo[fields.DatabaseID],
+			}
+
 			err := d.storer.SavePullRequestReviewComment(ctx, repositoryOwner, repositoryName, pullRequestNumber, review.DatabaseID, &comment)
 			if err != nil {
 				return fmt.Errorf(
@@ -698,9 +1110,59 @@ func (d Downloader) downloadReviewComments(ctx context.Context, repositoryOwner,
 	return d.downloadConnection(ctx, pullRequestReviewCommentsType, review.Comments, &q, variables, process)
 }
 
+type commitCommentsQ struct {
+	Node struct {
+		Repository struct {
+			CommitComments graphql.CommitCommentConnection `graphql:"commitComments(first: $commitCommentsPage, after: $commitCommentsCursor)"`
+		} `graphql:"... on Repository"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *commitCommentsQ) Connection() Connection {
+	return q.Node.Repository.CommitComments
+}
+
+// downloadCommitComments downloads the comments made directly on the
+// repository commits, the ones made on PR diffs are review comments
+func (d Downloader) downloadCommitComments(ctx context.Context, owner string, name string, repository *graphql.Repository) error {
+	var q commitCommentsQ
+	variables := map[string]interface{}{
+		"id": githubv4.ID(repository.ID),
+	}
+	variables[commitCommentsType.Page()] = d.pages.first(commitCommentsType)
+	variables[commitCommentsType.Cursor()] = (*githubv4.String)(nil)
+
+	err := d.queryFirst(ctx, &q, variables)
+	if err != nil {
+		if d.skipConnection(ctx, commitCommentsType.Name, repository.ID, err) {
+			return nil
+		}
+
+		return fmt.Errorf("commit comments query failed: %v", err)
+	}
+
+	process := func(res Connection) error {
+		comments := res.(graphql.CommitCommentConnection)
+		for _, comment := range comments.Nodes {
+			err := d.storer.SaveCommitComment(ctx, owner, name, &comment)
+			if err != nil {
+				return fmt.Errorf("failed to save commit comment for commit %v: %v", comment.Commit.Oid, err)
+			}
+		}
+
+		return nil
+	}
+
+	return d.downloadConnection(ctx, commitCommentsType, q.Connection(), &q, variables, process)
+}
+
 // DownloadOrganization downloads the metadata for the given organization and
 // its member users
 func (d Downloader) DownloadOrganization(ctx context.Context, name string, version int) error {
+	d.pages = d.pageSizer(name)
+	d.skipped = &skippedItems{}
+	defer d.reportSkipped(ctx)
+
 	d.storer.Version(version)
 
 	var err error
@@ -729,10 +1191,10 @@ func (d Downloader) DownloadOrganization(ctx context.Context, name string, versi
 	variables := map[string]interface{}{
 		"organizationLogin": githubv4.String(name),
 	}
-	variables[membersWithRole.Page()] = membersWithRole.PageSize
+	variables[membersWithRole.Page()] = d.pages.first(membersWithRole)
 	variables[membersWithRole.Cursor()] = (*githubv4.String)(nil)
 
-	err = d.client.Query(ctx, &q, variables)
+	err = d.queryFirst(ctx, &q, variables)
 	if err != nil {
 		return fmt.Errorf("organization query failed: %v", err)
 	}
@@ -747,6 +1209,13 @@ func (d Downloader) DownloadOrganization(ctx context.Context, name string, versi
 		return err
 	}
 
+	if d.projects {
+		err = d.downloadOrganizationProjects(ctx, &q.Organization)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -768,8 +1237,13 @@ func (d Downloader) downloadUsers(ctx context.Context, name string, organization
 
 	process := func(res Connection) error {
 		users := res.(graphql.OrganizationMemberConnection)
-		for _, user := range users.Nodes {
-			err := d.storer.SaveUser(ctx, organization.DatabaseID, organization.Login, &user)
+		for _, fields := range users.Nodes {
+			user, err := d.userExtended(ctx, fields)
+			if err != nil {
+				return err
+			}
+
+			err = d.storer.SaveUser(ctx, organization.DatabaseID, organization.Login, user)
 			if err != nil {
 				return fmt.Errorf("failed to save UserExtended: %v", err)
 			}
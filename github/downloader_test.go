@@ -6,10 +6,8 @@ package github
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"database/sql"
-	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -21,6 +19,8 @@ import (
 	"time"
 
 	"github.com/src-d/metadata-retrieval/database"
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/github/recorder"
 	"github.com/src-d/metadata-retrieval/github/store"
 	"github.com/src-d/metadata-retrieval/testutils"
 
@@ -33,10 +33,10 @@ import (
 )
 
 const (
-	orgPrefix            = "../testdata/organization_src-d_2019-10-31"
-	repoPrefix           = "../testdata/repository_src-d_gitbase_2019-10-31"
-	orgRecFile           = orgPrefix + ".gob.gz"
-	repoRecFile          = repoPrefix + ".gob.gz"
+	orgPrefix            = "../testdata/organization_src-d"
+	repoPrefix           = "../testdata/repository_src-d_gitbase"
+	orgRecFile           = orgPrefix + ".cassette.json"
+	repoRecFile          = repoPrefix + ".cassette.json.gz"
 	offlineRepoTests     = repoPrefix + ".json"
 	offlineOrgTests      = orgPrefix + ".json"
 	onlineRepoTests      = "../testdata/online-repository-tests.json"
@@ -44,22 +44,6 @@ const (
 	onlineReposListTests = "../testdata/online-repositories-list-tests.json"
 )
 
-// loads requests-response data from a gob file
-func loadReqResp(filepath string, reqResp map[string]string) error {
-	// Open a file
-	decodeFile, err := os.Open(filepath)
-	if err != nil {
-		return err
-	}
-	defer decodeFile.Close()
-	reader, err := gzip.NewReader(decodeFile)
-	if err != nil {
-		return err
-	}
-	// Create a decoder and decode
-	return gob.NewDecoder(reader).Decode(&reqResp)
-}
-
 // loads tests from a json file
 func loadTests(filepath string) (testutils.TestOracles, error) {
 	var (
@@ -262,38 +246,140 @@ func testOrg(t *testing.T, oracle testutils.OrganizationTestOracle, d *Downloade
 	require.Len(storer.Users, oracle.NumOfUsers)
 }
 
-func getRoundTripDownloader(reqResp map[string]string, storer Storer) *Downloader {
+// getReplayDownloader returns a Downloader that replays the given cassette
+func getReplayDownloader(cassette string, storer Storer) (*Downloader, error) {
+	r, err := recorder.New(cassette, recorder.Replay, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the recorded responses predate the projects and commit comments downloads,
+	// so they are left disabled
 	return &Downloader{
 		storer: storer,
+		client: githubv4.NewClient(r.Client()),
+	}, nil
+}
+
+// TestDownloadCommitComments checks that the commit comments are paginated,
+// the ones that are not on a line have no path and position
+func TestDownloadCommitComments(t *testing.T) {
+	require := require.New(t)
+
+	pages := map[string]string{
+		"null": `{"data":{"node":{"commitComments":{
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"totalCount":2,
+			"nodes":[{"body":"typo","commit":{"oid":"1234abcd"},"path":"README.md","position":3,"author":{"login":"alice"}}]}}}}`,
+		`"c1"`: `{"data":{"node":{"commitComments":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},"totalCount":2,
+			"nodes":[{"body":"lgtm","commit":{"oid":"5678efab"},"path":null,"position":null,"author":{"login":"bob"}}]}}}}`,
+	}
+
+	d := &Downloader{
 		client: githubv4.NewClient(&http.Client{
 			Transport: RoundTripFunc(func(req *http.Request) *http.Response {
-				// consume request body
-				savecl := req.ContentLength
-				bodyBytes, _ := ioutil.ReadAll(req.Body)
-				defer req.Body.Close()
-				// recreate request body
-				req.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-				req.ContentLength = savecl
-				data := reqResp[string(bodyBytes)]
+				var body struct {
+					Variables map[string]json.RawMessage
+				}
+				require.NoError(json.NewDecoder(req.Body).Decode(&body))
+
 				return &http.Response{
-					StatusCode: 200,
-					Body:       ioutil.NopCloser(bytes.NewBufferString(data)),
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(pages[string(body.Variables["commitCommentsCursor"])])),
 					Header:     make(http.Header),
 				}
-			})}),
+			}),
+		}),
 	}
+
+	storer := &testutils.Memory{}
+	d.storer = storer
+	require.NoError(storer.SaveRepository(context.TODO(), &graphql.RepositoryFields{}, nil))
+
+	repository := &graphql.Repository{}
+	repository.ID = "R1"
+	require.NoError(d.downloadCommitComments(context.TODO(), "src-d", "gitbase", repository))
+	require.Len(storer.CommitComments, 2)
+	require.Equal("README.md", *storer.CommitComments[0].Path)
+	require.Equal(3, *storer.CommitComments[0].Position)
+	require.Nil(storer.CommitComments[1].Path)
+	require.Nil(storer.CommitComments[1].Position)
+}
+
+// getStaticDownloader returns a Downloader that answers every query with the
+// given response
+func getStaticDownloader(response string, storer Storer) *Downloader {
+	return &Downloader{
+		storer: storer,
+		client: githubv4.NewClient(&http.Client{
+			Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(response)),
+					Header:     make(http.Header),
+				}
+			}),
+		}),
+	}
+}
+
+func TestDownloadIssue(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := getStaticDownloader(`{"data":{"repository":{"issue":{
+		"number":7,"id":"I7","title":"crash on start",
+		"assignees":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"login":"alice"}]},
+		"labels":{"pageInfo":{"hasNextPage":false},"totalCount":2,"nodes":[{"name":"bug"},{"name":"p1"}]},
+		"comments":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"body":"same here","author":{"login":"bob"}}]}
+	}}}}`, storer)
+
+	require.NoError(d.DownloadIssue(context.TODO(), "src-d", "gitbase", 7, 0))
+	require.Len(storer.Issues, 1)
+	require.Equal(7, storer.Issues[0].Number)
+	require.Len(storer.IssueComments, 1)
+}
+
+func TestDownloadPullRequest(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := getStaticDownloader(`{"data":{"repository":{"pullRequest":{
+		"number":8,"id":"PR8","title":"fix crash on start",
+		"assignees":{"pageInfo":{"hasNextPage":false},"totalCount":0,"nodes":[]},
+		"labels":{"pageInfo":{"hasNextPage":false},"totalCount":0,"nodes":[]},
+		"comments":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"body":"thanks","author":{"login":"bob"}}]},
+		"reviews":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"id":"R1","state":"APPROVED","author":{"login":"carol"},
+			"comments":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"body":"nit","path":"main.go","author":{"login":"carol"}}]}}]}
+	}}}}`, storer)
+
+	require.NoError(d.DownloadPullRequest(context.TODO(), "src-d", "gitbase", 8, 0))
+	require.Len(storer.PRs, 1)
+	require.Equal(8, storer.PRs[0].Number)
+	require.Len(storer.PRComments, 1)
+	require.Len(storer.PRReviews, 1)
+	require.Len(storer.PRReviewComments, 1)
+}
+
+func TestDownloadIssueNotFound(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := getStaticDownloader(`{"data":{"repository":{"issue":null}},
+		"errors":[{"type":"NOT_FOUND","path":["repository","issue"],"message":"Could not resolve to an Issue with the number of 9."}]}`, storer)
+
+	require.Error(d.DownloadIssue(context.TODO(), "src-d", "gitbase", 9, 0))
+	require.Empty(storer.Issues)
 }
 
 // TestOfflineOrganizationDownload Tests a large organization by replaying recorded responses
 func (suite *DownloaderTestSuite) TestOfflineOrganizationDownload() {
 	t := suite.T()
-	reqResp := make(map[string]string)
-	// Load the recording
-	suite.NoError(loadReqResp(orgRecFile, reqResp), "Failed to read the offline recordings")
-	// Setup the downloader with RoundTrip functionality.
+	// Setup the downloader replaying the recording.
 	// Not using the NewStdoutDownloader initialization because it overides the transport
 	storer := &testutils.Memory{}
-	downloader := getRoundTripDownloader(reqResp, storer)
+	downloader, err := getReplayDownloader(orgRecFile, storer)
+	suite.NoError(err, "Failed to read the offline recordings")
 	testOracles, err := loadTests(offlineOrgTests)
 	suite.NoError(err, "Failed to read the offline tests")
 	suite.Greater(len(testOracles.OrganizationTestOracles), 0, "Must contain at least one test")
@@ -309,10 +395,9 @@ func (suite *DownloaderTestSuite) TestOfflineOrganizationDownload() {
 // TestOfflineRepositoryDownload Tests a large repository by replaying recorded responses
 func (suite *DownloaderTestSuite) TestOfflineRepositoryDownload() {
 	t := suite.T()
-	reqResp := make(map[string]string)
-	suite.NoError(loadReqResp(repoRecFile, reqResp), "Failed to read the offline recordings")
 	storer := &testutils.Memory{}
-	downloader := getRoundTripDownloader(reqResp, storer)
+	downloader, err := getReplayDownloader(repoRecFile, storer)
+	suite.NoError(err, "Failed to read the offline recordings")
 	testOracles, err := loadTests(offlineRepoTests)
 	suite.NoError(err, "Failed to read the offline tests")
 	suite.Greater(len(testOracles.RepositoryTestOracles), 0, "Must contain at least one test")
@@ -474,13 +559,11 @@ func (suite *DownloaderTestSuite) TestOnlineRepositoryDownloadWithDB() {
 // TestOfflineOrganizationDownloadWithDB Tests a large organization by replaying recorded responses and storing the results in Postgresql
 func (suite *DownloaderTestSuite) TestOfflineOrganizationDownloadWithDB() {
 	t := suite.T()
-	reqResp := make(map[string]string)
-	// Load the recording
-	suite.NoError(loadReqResp(orgRecFile, reqResp), "Failed to read the recordings")
-	// Setup the downloader with RoundTrip functionality.
+	// Setup the downloader replaying the recording.
 	// Not using the NewStdoutDownloader initialization because it overides the transport
 	storer := &store.DB{DB: suite.db}
-	downloader := getRoundTripDownloader(reqResp, storer)
+	downloader, err := getReplayDownloader(orgRecFile, storer)
+	suite.NoError(err, "Failed to read the recordings")
 	downloader.SetCurrent(context.TODO(), 0) // Will create the views
 	suite.downloader = downloader
 	testOracles, err := loadTests(offlineOrgTests)
@@ -497,11 +580,9 @@ func (suite *DownloaderTestSuite) TestOfflineOrganizationDownloadWithDB() {
 // TestOfflineRepositoryDownload Tests a large repository by replaying recorded responses and stores the results in postgresql
 func (suite *DownloaderTestSuite) TestOfflineRepositoryDownloadWithDB() {
 	t := suite.T()
-	reqResp := make(map[string]string)
-	// Load the recording
-	suite.NoError(loadReqResp(repoRecFile, reqResp), "Failed to read the recordings")
 	storer := &store.DB{DB: suite.db}
-	downloader := getRoundTripDownloader(reqResp, storer)
+	downloader, err := getReplayDownloader(repoRecFile, storer)
+	suite.NoError(err, "Failed to read the recordings")
 	downloader.SetCurrent(context.TODO(), 0)
 	suite.downloader = downloader
 	testOracles, err := loadTests(offlineRepoTests)
@@ -0,0 +1,128 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// WithEndpoints makes the Downloader use the API of a GitHub Enterprise
+// Server, given its GraphQL endpoint and its REST API base URL, e.g.
+// https://github.example.com/api/graphql and https://github.example.com/api/v3/
+// An empty REST API URL is derived from the GraphQL endpoint when it ends with
+// /api/graphql, NewDownloader fails otherwise. The server version is detected
+// when the Downloader is created, and the resources missing in its schema are
+// not downloaded
+func WithEndpoints(graphqlURL string, restURL string) Option {
+	return func(d *Downloader) {
+		if restURL == "" {
+			restURL = enterpriseRESTURL(graphqlURL)
+		}
+
+		d.graphqlURL = graphqlURL
+		d.restURL = ""
+		if restURL != "" {
+			d.restURL = strings.TrimSuffix(restURL, "/") + "/"
+		}
+	}
+}
+
+// enterpriseRESTURL returns the REST API base URL of a GitHub Enterprise
+// Server from its GraphQL endpoint, or "" if it does not end with /api/graphql
+func enterpriseRESTURL(graphqlURL string) string {
+	graphqlURL = strings.TrimSuffix(graphqlURL, "/")
+	if !strings.HasSuffix(graphqlURL, "/api/graphql") {
+		return ""
+	}
+
+	return strings.TrimSuffix(graphqlURL, "graphql") + "v3/"
+}
+
+// serverVersion is a GitHub Enterprise Server version, like 3.6.2
+type serverVersion struct {
+	Major int
+	Minor int
+}
+
+// latestVersion is used when the server does not report its version
+var latestVersion = serverVersion{Major: 1<<31 - 1}
+
+func (v serverVersion) String() string {
+	if v == latestVersion {
+		return "latest"
+	}
+
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// atLeast returns true if v is the same or a newer version than the given one
+func (v serverVersion) atLeast(major int, minor int) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+// parseServerVersion parses the major and minor parts of a version string
+// like 3.6.2; an empty string is the latest version
+func parseServerVersion(s string) (serverVersion, error) {
+	if s == "" {
+		return latestVersion, nil
+	}
+
+	parts := strings.SplitN(s, ".", 3)
+	if len(parts) < 2 {
+		return serverVersion{}, fmt.Errorf("malformed server version %q", s)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return serverVersion{}, fmt.Errorf("malformed server version %q: %v", s, err)
+	}
+
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return serverVersion{}, fmt.Errorf("malformed server version %q: %v", s, err)
+	}
+
+	return serverVersion{Major: major, Minor: minor}, nil
+}
+
+// enterpriseVersion requests the version of the GitHub Enterprise Server
+// using the REST API meta endpoint
+// https://docs.github.com/en/enterprise-server@3.0/rest/reference/meta
+func enterpriseVersion(httpClient *http.Client, restURL string) (serverVersion, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Get(restURL + "meta")
+	if err != nil {
+		return serverVersion{}, fmt.Errorf("failed to request the server version: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return serverVersion{}, fmt.Errorf("failed to request the server version: %s", resp.Status)
+	}
+
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return serverVersion{}, fmt.Errorf("failed to decode the server version: %v", err)
+	}
+
+	return parseServerVersion(meta.InstalledVersion)
+}
+
+// disableUnsupported disables the optional resources that are not part of
+// the GraphQL schema of the given server version
+func (d *Downloader) disableUnsupported(version serverVersion) {
+	// https://docs.github.com/en/enterprise-server@3.7/admin/release-notes
+	if d.projectsV2 && !version.atLeast(3, 7) {
+		log.Infof("projects (v2) are not available in GitHub Enterprise Server %s", version)
+		d.projectsV2 = false
+	}
+}
@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newEnterpriseServer returns a stand-in GitHub Enterprise Server that reports
+// the given version and answers the rate limit GraphQL query
+func newEnterpriseServer(version string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/meta", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"verifiable_password_authentication":true,"installed_version":"` + version + `"}`))
+	})
+	mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"rateLimit":{"remaining":4321}}}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestEnterpriseEndpoints(t *testing.T) {
+	require := require.New(t)
+
+	server := newEnterpriseServer("3.9.0")
+	defer server.Close()
+
+	d, err := NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/api/graphql", server.URL+"/api/v3"), WithProjects())
+	require.NoError(err)
+	require.True(d.projects)
+	require.True(d.projectsV2)
+
+	remaining, err := d.RateRemaining(context.TODO())
+	require.NoError(err)
+	require.Equal(4321, remaining)
+}
+
+func TestEnterpriseUnsupportedResources(t *testing.T) {
+	require := require.New(t)
+
+	server := newEnterpriseServer("3.6.2")
+	defer server.Close()
+
+	d, err := NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/api/graphql", server.URL+"/api/v3/"), WithProjects())
+	require.NoError(err)
+	require.True(d.projects)
+	require.False(d.projectsV2)
+}
+
+func TestEnterpriseDerivedRESTURL(t *testing.T) {
+	require := require.New(t)
+
+	server := newEnterpriseServer("3.9.0")
+	defer server.Close()
+
+	// the REST API URL is derived from the GraphQL endpoint
+	d, err := NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/api/graphql", ""))
+	require.NoError(err)
+	require.Equal(server.URL+"/api/v3/", d.restURL)
+
+	// unless it does not have the usual path
+	_, err = NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/graphql", ""))
+	require.Error(err)
+	require.Contains(err.Error(), "REST API URL")
+}
+
+func TestEnterpriseVersionError(t *testing.T) {
+	require := require.New(t)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/api/graphql", server.URL+"/api/v3/"))
+	require.Error(err)
+}
+
+func TestParseServerVersion(t *testing.T) {
+	require := require.New(t)
+
+	v, err := parseServerVersion("3.6.2")
+	require.NoError(err)
+	require.Equal(serverVersion{Major: 3, Minor: 6}, v)
+	require.True(v.atLeast(3, 6))
+	require.True(v.atLeast(2, 22))
+	require.False(v.atLeast(3, 7))
+
+	v, err = parseServerVersion("")
+	require.NoError(err)
+	require.True(v.atLeast(3, 7))
+
+	_, err = parseServerVersion("three")
+	require.Error(err)
+}
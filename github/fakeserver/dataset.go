@@ -0,0 +1,318 @@
+package fakeserver
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Dataset is the data served by a Server. The IDs, database IDs and URLs of
+// the items are generated when the Server is created
+type Dataset struct {
+	Organizations []*Organization
+}
+
+// Organization is an organization with its members and repositories
+type Organization struct {
+	Login       string
+	Name        string
+	Description string
+	Email       string
+	CreatedAt   time.Time
+
+	Members      []*User
+	Repositories []*Repository
+}
+
+// User is the author of the issues, PRs, comments and reviews, and an
+// organization member. The same User can be used in several organizations
+type User struct {
+	Login     string
+	Name      string
+	Bio       string
+	Company   string
+	Location  string
+	CreatedAt time.Time
+}
+
+// Repository is a repository with its issues and PRs
+type Repository struct {
+	Name        string
+	Description string
+	Language    string
+	Topics      []string
+	IsPrivate   bool
+	IsFork      bool
+	IsArchived  bool
+	CreatedAt   time.Time
+
+	Issues       []*Issue
+	PullRequests []*PullRequest
+}
+
+// Issue is an issue with its comments. It is closed when ClosedAt is set
+type Issue struct {
+	Number    int
+	Title     string
+	Body      string
+	Author    *User
+	Assignees []*User
+	Labels    []string
+	CreatedAt time.Time
+	ClosedAt  *time.Time
+	ClosedBy  *User
+
+	Comments []*Comment
+}
+
+// Comment is a comment of an issue or a PR
+type Comment struct {
+	Author    *User
+	Body      string
+	CreatedAt time.Time
+}
+
+// PullRequest is a PR with its comments and reviews. It is merged when
+// MergedAt is set
+type PullRequest struct {
+	Number       int
+	Title        string
+	Body         string
+	Author       *User
+	Assignees    []*User
+	Labels       []string
+	BaseRef      string
+	HeadRef      string
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	CreatedAt    time.Time
+	ClosedAt     *time.Time
+	MergedAt     *time.Time
+	MergedBy     *User
+
+	Comments []*Comment
+	Reviews  []*Review
+}
+
+// Review is a PR review with its comments
+type Review struct {
+	Author      *User
+	Body        string
+	State       string
+	SubmittedAt time.Time
+
+	Comments []*ReviewComment
+}
+
+// ReviewComment is a comment of a PR review
+type ReviewComment struct {
+	Author    *User
+	Body      string
+	Path      string
+	Position  int
+	DiffHunk  string
+	CreatedAt time.Time
+}
+
+// Size is the number of items of a seeded Dataset. The nested ones, like the
+// Comments of each issue, are the maximum number of them, each item has a
+// random number of them up to it
+type Size struct {
+	Members        int
+	Repositories   int
+	Issues         int
+	PullRequests   int
+	Comments       int
+	Assignees      int
+	Labels         int
+	Reviews        int
+	ReviewComments int
+}
+
+// DefaultSize is enough to paginate all the connections downloaded for a
+// repository
+var DefaultSize = Size{
+	Members:        5,
+	Repositories:   2,
+	Issues:         40,
+	PullRequests:   40,
+	Comments:       12,
+	Assignees:      3,
+	Labels:         3,
+	Reviews:        6,
+	ReviewComments: 6,
+}
+
+var (
+	labels = []string{"bug", "enhancement", "documentation", "question", "good first issue"}
+	topics = []string{"go", "git", "sql", "github", "metadata"}
+	states = []string{"APPROVED", "CHANGES_REQUESTED", "COMMENTED"}
+)
+
+// Seed returns a Dataset with an organization with the given login, and the
+// given size. The contents are random, but the same seed always returns the
+// same Dataset
+func Seed(seed int64, login string, size Size) *Dataset {
+	r := rand.New(rand.NewSource(seed))
+	date := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := func() time.Time {
+		date = date.Add(time.Duration(1+r.Intn(72)) * time.Hour)
+		return date
+	}
+
+	org := &Organization{
+		Login:       login,
+		Name:        fmt.Sprintf("The %s organization", login),
+		Description: fmt.Sprintf("Repositories of %s", login),
+		Email:       fmt.Sprintf("hello@%s.example.com", login),
+		CreatedAt:   next(),
+	}
+
+	for i := 0; i < size.Members; i++ {
+		org.Members = append(org.Members, &User{
+			Login:     fmt.Sprintf("%s-member-%d", login, i+1),
+			Name:      fmt.Sprintf("Member %d", i+1),
+			Bio:       "Software engineer",
+			Company:   login,
+			Location:  "Madrid",
+			CreatedAt: next(),
+		})
+	}
+
+	user := func() *User {
+		if len(org.Members) == 0 {
+			return nil
+		}
+
+		return org.Members[r.Intn(len(org.Members))]
+	}
+
+	users := func(max int) []*User {
+		var list []*User
+		for _, i := range r.Perm(len(org.Members))[:r.Intn(min(max, len(org.Members))+1)] {
+			list = append(list, org.Members[i])
+		}
+
+		return list
+	}
+
+	pick := func(from []string, max int) []string {
+		var list []string
+		for _, i := range r.Perm(len(from))[:r.Intn(min(max, len(from))+1)] {
+			list = append(list, from[i])
+		}
+
+		return list
+	}
+
+	comments := func() []*Comment {
+		var list []*Comment
+		for i := r.Intn(size.Comments + 1); i > 0; i-- {
+			list = append(list, &Comment{Author: user(), Body: fmt.Sprintf("comment %d", r.Int()), CreatedAt: next()})
+		}
+
+		return list
+	}
+
+	for i := 0; i < size.Repositories; i++ {
+		repo := &Repository{
+			Name:        fmt.Sprintf("repository-%d", i+1),
+			Description: fmt.Sprintf("Repository %d of %s", i+1, login),
+			Language:    "Go",
+			Topics:      pick(topics, len(topics)),
+			IsFork:      i%3 == 2,
+			CreatedAt:   next(),
+		}
+
+		// issues and PRs share the numbers
+		number := 0
+		for j := 0; j < size.Issues; j++ {
+			number++
+			issue := &Issue{
+				Number:    number,
+				Title:     fmt.Sprintf("Issue %d", number),
+				Body:      fmt.Sprintf("Body of the issue %d", number),
+				Author:    user(),
+				Assignees: users(size.Assignees),
+				Labels:    pick(labels, size.Labels),
+				CreatedAt: next(),
+				Comments:  comments(),
+			}
+
+			if r.Intn(2) == 0 {
+				closedAt := next()
+				issue.ClosedAt = &closedAt
+				issue.ClosedBy = user()
+			}
+
+			repo.Issues = append(repo.Issues, issue)
+		}
+
+		for j := 0; j < size.PullRequests; j++ {
+			number++
+			pr := &PullRequest{
+				Number:       number,
+				Title:        fmt.Sprintf("PR %d", number),
+				Body:         fmt.Sprintf("Body of the PR %d", number),
+				Author:       user(),
+				Assignees:    users(size.Assignees),
+				Labels:       pick(labels, size.Labels),
+				BaseRef:      "master",
+				HeadRef:      fmt.Sprintf("feature-%d", number),
+				Additions:    r.Intn(500),
+				Deletions:    r.Intn(500),
+				ChangedFiles: 1 + r.Intn(20),
+				CreatedAt:    next(),
+				Comments:     comments(),
+			}
+
+			for k := r.Intn(size.Reviews + 1); k > 0; k-- {
+				review := &Review{
+					Author:      user(),
+					Body:        fmt.Sprintf("review %d", r.Int()),
+					State:       states[r.Intn(len(states))],
+					SubmittedAt: next(),
+				}
+
+				for l := r.Intn(size.ReviewComments + 1); l > 0; l-- {
+					review.Comments = append(review.Comments, &ReviewComment{
+						Author:    user(),
+						Body:      fmt.Sprintf("review comment %d", r.Int()),
+						Path:      fmt.Sprintf("file%d.go", r.Intn(10)),
+						Position:  1 + r.Intn(100),
+						DiffHunk:  "@@ -1,3 +1,4 @@",
+						CreatedAt: next(),
+					})
+				}
+
+				pr.Reviews = append(pr.Reviews, review)
+			}
+
+			switch r.Intn(3) {
+			case 0:
+				closedAt := next()
+				pr.ClosedAt = &closedAt
+			case 1:
+				mergedAt := next()
+				pr.ClosedAt = &mergedAt
+				pr.MergedAt = &mergedAt
+				pr.MergedBy = user()
+			}
+
+			repo.PullRequests = append(repo.PullRequests, pr)
+		}
+
+		org.Repositories = append(org.Repositories, repo)
+	}
+
+	return &Dataset{Organizations: []*Organization{org}}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
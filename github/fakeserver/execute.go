@@ -0,0 +1,387 @@
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxPageSize is the maximum number of nodes of a connection page
+	maxPageSize  = 100
+	cursorPrefix = "cursor:v2:"
+)
+
+// object is a node of the graph served by the fake server, with its fields
+// by GraphQL name. The values of the fields are scalars, *object, []*object,
+// *connection or resolver. The fields that are not in the graph are null
+type object struct {
+	typename string
+	// interfaces are the interfaces and unions the object belongs to, used by
+	// the inline fragments
+	interfaces []string
+	fields     map[string]interface{}
+}
+
+func newObject(typename string, fields map[string]interface{}, interfaces ...string) *object {
+	return &object{typename: typename, interfaces: interfaces, fields: fields}
+}
+
+// is returns true if the inline fragments on the given type apply to the
+// object
+func (o *object) is(typename string) bool {
+	if o.typename == typename {
+		return true
+	}
+
+	for _, i := range o.interfaces {
+		if i == typename {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolver returns the value of a field with arguments
+type resolver func(args map[string]interface{}) (interface{}, error)
+
+// connection is a paginated list of nodes. filter, if set, selects the nodes
+// for the arguments of the field, like the states of the issues
+type connection struct {
+	typename string
+	nodes    []*object
+	filter   func(args map[string]interface{}, node *object) bool
+}
+
+// fieldError is an error of a field, returned in the errors of the response
+// and with a null value
+type fieldError struct {
+	Type    string
+	Message string
+}
+
+func (e *fieldError) Error() string {
+	return e.Message
+}
+
+// responseError is an error of a GraphQL response
+type responseError struct {
+	Type    string        `json:"type,omitempty"`
+	Path    []interface{} `json:"path,omitempty"`
+	Message string        `json:"message"`
+}
+
+// result is a response object, with the fields in the order of the query
+type result []resultField
+
+type resultField struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (r result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// executor runs a query on the graph
+type executor struct {
+	variables map[string]interface{}
+	errors    []responseError
+}
+
+// execute runs the query with the given variables from the root object
+func execute(root *object, query string, variables map[string]interface{}) (interface{}, []responseError) {
+	set, err := parseQuery(query)
+	if err != nil {
+		return nil, []responseError{{Message: err.Error()}}
+	}
+
+	e := &executor{variables: variables}
+	data := e.object(root, set, nil)
+	return data, e.errors
+}
+
+func (e *executor) fail(path []interface{}, err error) {
+	re := responseError{Path: path, Message: err.Error()}
+	if fe, ok := err.(*fieldError); ok {
+		re.Type = fe.Type
+	}
+
+	e.errors = append(e.errors, re)
+}
+
+// collect returns the fields selected for the object: the ones of the
+// selection set and the ones of the inline fragments that apply to it, with
+// the selections of the repeated fields merged
+func collect(o *object, set []selection) []selection {
+	var fields []selection
+	index := make(map[string]int)
+	var walk func([]selection)
+	walk = func(set []selection) {
+		for _, s := range set {
+			if s.on != "" {
+				if o.is(s.on) {
+					walk(s.selection)
+				}
+
+				continue
+			}
+
+			if i, ok := index[s.key()]; ok {
+				fields[i].selection = append(fields[i].selection, s.selection...)
+				continue
+			}
+
+			index[s.key()] = len(fields)
+			fields = append(fields, s)
+		}
+	}
+
+	walk(set)
+	return fields
+}
+
+func (e *executor) object(o *object, set []selection, path []interface{}) interface{} {
+	if o == nil {
+		return nil
+	}
+
+	var r result
+	for _, s := range collect(o, set) {
+		r = append(r, resultField{s.key(), e.field(o, s, appendPath(path, s.key()))})
+	}
+
+	return r
+}
+
+// appendPath returns a copy of the path with the given element
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, element)
+}
+
+func (e *executor) field(o *object, s selection, path []interface{}) interface{} {
+	if s.name == "__typename" {
+		return o.typename
+	}
+
+	v, ok := o.fields[s.name]
+	if !ok {
+		return nil
+	}
+
+	args := make(map[string]interface{}, len(s.arguments))
+	for name, arg := range s.arguments {
+		args[name] = arg.resolve(e.variables)
+	}
+
+	if r, ok := v.(resolver); ok {
+		var err error
+		v, err = r(args)
+		if err != nil {
+			e.fail(path, err)
+			return nil
+		}
+	}
+
+	return e.value(v, s, args, path)
+}
+
+func (e *executor) value(v interface{}, s selection, args map[string]interface{}, path []interface{}) interface{} {
+	switch v := v.(type) {
+	case *object:
+		return e.object(v, s.selection, path)
+	case []*object:
+		list := make([]interface{}, len(v))
+		for i, o := range v {
+			list[i] = e.object(o, s.selection, appendPath(path, i))
+		}
+
+		return list
+	case *connection:
+		page, err := v.page(s, args)
+		if err != nil {
+			e.fail(path, err)
+			return nil
+		}
+
+		return e.object(page, s.selection, path)
+	default:
+		return v
+	}
+}
+
+func encodeCursor(i int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(i)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	invalid := &fieldError{Message: fmt.Sprintf("`%s` does not appear to be a valid cursor.", cursor)}
+	content, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(content), cursorPrefix) {
+		return 0, invalid
+	}
+
+	i, err := strconv.Atoi(strings.TrimPrefix(string(content), cursorPrefix))
+	if err != nil {
+		return 0, invalid
+	}
+
+	return i, nil
+}
+
+// toInt returns the value of an Int argument, the variables are decoded as
+// float64
+func toInt(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// page returns the page of the connection for the given arguments, like
+// GitHub it requires first or last to request the nodes, up to 100
+func (c *connection) page(s selection, args map[string]interface{}) (*object, error) {
+	var nodes []*object
+	for _, node := range c.nodes {
+		if c.filter == nil || c.filter(args, node) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	start, end := 0, len(nodes)
+	if after, ok := args["after"].(string); ok {
+		i, err := decodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+
+		start = i + 1
+	}
+
+	if before, ok := args["before"].(string); ok {
+		i, err := decodeCursor(before)
+		if err != nil {
+			return nil, err
+		}
+
+		end = i
+	}
+
+	if start > len(nodes) {
+		start = len(nodes)
+	}
+
+	if end > len(nodes) {
+		end = len(nodes)
+	}
+
+	if end < start {
+		end = start
+	}
+
+	first, hasFirst := toInt(args["first"])
+	last, hasLast := toInt(args["last"])
+	for _, limit := range []struct {
+		name  string
+		value int
+		ok    bool
+	}{{"first", first, hasFirst}, {"last", last, hasLast}} {
+		if limit.ok && (limit.value < 0 || limit.value > maxPageSize) {
+			return nil, &fieldError{
+				Type:    "EXCESSIVE_PAGINATION",
+				Message: fmt.Sprintf("Requesting %d records on the `%s` connection exceeds the `%s` limit of %d records.", limit.value, s.name, limit.name, maxPageSize),
+			}
+		}
+	}
+
+	if !hasFirst && !hasLast && selectsNodes(s.selection) {
+		return nil, &fieldError{
+			Type:    "MISSING_PAGINATION_BOUNDARIES",
+			Message: fmt.Sprintf("You must provide a `first` or `last` value to properly paginate the `%s` connection.", s.name),
+		}
+	}
+
+	hasNext, hasPrevious := end < len(nodes), start > 0
+	if hasFirst && end-start > first {
+		end = start + first
+		hasNext = true
+	}
+
+	if hasLast && end-start > last {
+		start = end - last
+		hasPrevious = true
+	}
+
+	pageInfo := map[string]interface{}{
+		"hasNextPage":     hasNext,
+		"hasPreviousPage": hasPrevious,
+		"startCursor":     nil,
+		"endCursor":       nil,
+	}
+
+	var edges []*object
+	for i := start; i < end; i++ {
+		edges = append(edges, newObject(strings.TrimSuffix(c.typename, "Connection")+"Edge", map[string]interface{}{
+			"cursor": encodeCursor(i),
+			"node":   nodes[i],
+		}))
+	}
+
+	if start < end {
+		pageInfo["startCursor"] = encodeCursor(start)
+		pageInfo["endCursor"] = encodeCursor(end - 1)
+	}
+
+	return newObject(c.typename, map[string]interface{}{
+		"totalCount": len(nodes),
+		"pageInfo":   newObject("PageInfo", pageInfo),
+		"nodes":      nodes[start:end],
+		"edges":      edges,
+	}), nil
+}
+
+// selectsNodes returns true if the selection set of a connection requests
+// its nodes or edges
+func selectsNodes(set []selection) bool {
+	for _, s := range set {
+		if s.name == "nodes" || s.name == "edges" || s.on != "" && selectsNodes(s.selection) {
+			return true
+		}
+	}
+
+	return false
+}
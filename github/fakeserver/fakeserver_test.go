@@ -0,0 +1,247 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// post sends a GraphQL query to the server and decodes its response
+func post(require *require.Assertions, s *Server, query string, variables map[string]interface{}) (*http.Response, map[string]interface{}) {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(err)
+
+	resp, err := s.Client().Post("https://api.github.com/graphql", "application/json", strings.NewReader(string(body)))
+	require.NoError(err)
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	}
+
+	return resp, response
+}
+
+func TestParseQuery(t *testing.T) {
+	require := require.New(t)
+
+	set, err := parseQuery(`query($id:ID!$page:Int!){node(id: $id){... on Issue{
+		open: issues(states:[OPEN], first: 10, title: "a \"quoted\" title"),id,
+		__typename}}}`)
+	require.NoError(err)
+	require.Len(set, 1)
+
+	node := set[0]
+	require.Equal("node", node.name)
+	require.Equal("id", node.arguments["id"].variable)
+	require.Len(node.selection, 1)
+	require.Equal("Issue", node.selection[0].on)
+
+	fields := node.selection[0].selection
+	require.Len(fields, 3)
+	require.Equal("open", fields[0].key())
+	require.Equal("issues", fields[0].name)
+	require.Equal([]interface{}{"OPEN"}, fields[0].arguments["states"].resolve(nil))
+	require.Equal(float64(10), fields[0].arguments["first"].resolve(nil))
+	require.Equal(`a "quoted" title`, fields[0].arguments["title"].resolve(nil))
+	require.Equal("id", fields[1].key())
+	require.Equal("__typename", fields[2].key())
+
+	_, err = parseQuery(`{repository(owner: "src-d") {name}`)
+	require.Error(err)
+
+	_, err = parseQuery(`{...IssueFields}`)
+	require.Error(err)
+}
+
+func TestSeed(t *testing.T) {
+	require := require.New(t)
+
+	d := Seed(42, "acme", DefaultSize)
+	require.Equal(d, Seed(42, "acme", DefaultSize))
+	require.NotEqual(d, Seed(43, "acme", DefaultSize))
+
+	org := d.Organizations[0]
+	require.Equal("acme", org.Login)
+	require.Len(org.Members, DefaultSize.Members)
+	require.Len(org.Repositories, DefaultSize.Repositories)
+	require.Len(org.Repositories[0].Issues, DefaultSize.Issues)
+	require.Len(org.Repositories[0].PullRequests, DefaultSize.PullRequests)
+	require.Equal(DefaultSize.Issues+1, org.Repositories[0].PullRequests[0].Number)
+}
+
+func TestQuery(t *testing.T) {
+	require := require.New(t)
+
+	s := New(Seed(1, "acme", DefaultSize))
+	defer s.Close()
+
+	_, response := post(require, s, `query($owner:String!$name:String!){
+		repository(owner: $owner, name: $name){
+			nameWithOwner,
+			openIssues: issues(states:[OPEN]){totalCount},
+			owner{login,__typename,... on Organization{databaseId},... on User{bio}},
+			newField
+		}}`, map[string]interface{}{"owner": "acme", "name": "repository-1"})
+
+	require.Nil(response["errors"])
+
+	var open int
+	for _, issue := range s.graph.repositories["acme/repository-1"].fields["issues"].(*connection).nodes {
+		if issue.fields["state"] == "OPEN" {
+			open++
+		}
+	}
+
+	repository := response["data"].(map[string]interface{})["repository"].(map[string]interface{})
+	require.Equal("acme/repository-1", repository["nameWithOwner"])
+	require.Equal(float64(open), repository["openIssues"].(map[string]interface{})["totalCount"])
+	require.Equal(map[string]interface{}{"login": "acme", "__typename": "Organization", "databaseId": float64(1)}, repository["owner"])
+	// the fields that are not in the dataset are null
+	require.Contains(repository, "newField")
+	require.Nil(repository["newField"])
+}
+
+func TestPagination(t *testing.T) {
+	require := require.New(t)
+
+	s := New(Seed(1, "acme", DefaultSize))
+	defer s.Close()
+
+	query := `query($cursor:String){repository(owner: "acme", name: "repository-1"){
+		issues(first: 15, after: $cursor){totalCount,pageInfo{hasNextPage,endCursor},nodes{number}}}}`
+
+	var numbers []float64
+	variables := map[string]interface{}{"cursor": nil}
+	for {
+		_, response := post(require, s, query, variables)
+		require.Nil(response["errors"])
+
+		issues := response["data"].(map[string]interface{})["repository"].(map[string]interface{})["issues"].(map[string]interface{})
+		require.Equal(float64(DefaultSize.Issues), issues["totalCount"])
+		for _, node := range issues["nodes"].([]interface{}) {
+			numbers = append(numbers, node.(map[string]interface{})["number"].(float64))
+		}
+
+		pageInfo := issues["pageInfo"].(map[string]interface{})
+		if !pageInfo["hasNextPage"].(bool) {
+			break
+		}
+
+		variables["cursor"] = pageInfo["endCursor"]
+	}
+
+	require.Len(numbers, DefaultSize.Issues)
+	for i, n := range numbers {
+		require.Equal(float64(i+1), n)
+	}
+
+	// last
+	_, response := post(require, s, `{repository(owner: "acme", name: "repository-1"){issues(last: 2){nodes{number}}}}`, nil)
+	issues := response["data"].(map[string]interface{})["repository"].(map[string]interface{})["issues"].(map[string]interface{})
+	require.Equal([]interface{}{
+		map[string]interface{}{"number": float64(DefaultSize.Issues - 1)},
+		map[string]interface{}{"number": float64(DefaultSize.Issues)},
+	}, issues["nodes"])
+}
+
+func TestQueryErrors(t *testing.T) {
+	require := require.New(t)
+
+	s := New(Seed(1, "acme", DefaultSize))
+	defer s.Close()
+
+	for _, c := range []struct {
+		query string
+		error map[string]interface{}
+	}{{
+		query: `{repository(owner: "acme", name: "missing"){name}}`,
+		error: map[string]interface{}{
+			"type":    "NOT_FOUND",
+			"path":    []interface{}{"repository"},
+			"message": "Could not resolve to a Repository with the name 'acme/missing'.",
+		},
+	}, {
+		query: `{node(id: "MDU6SXNzdWUxMDAwMA=="){id}}`,
+		error: map[string]interface{}{
+			"type":    "NOT_FOUND",
+			"path":    []interface{}{"node"},
+			"message": "Could not resolve to a node with the global id of 'MDU6SXNzdWUxMDAwMA=='",
+		},
+	}, {
+		query: `{repository(owner: "acme", name: "repository-1"){issues{nodes{number}}}}`,
+		error: map[string]interface{}{
+			"type":    "MISSING_PAGINATION_BOUNDARIES",
+			"path":    []interface{}{"repository", "issues"},
+			"message": "You must provide a `first` or `last` value to properly paginate the `issues` connection.",
+		},
+	}, {
+		query: `{repository(owner: "acme", name: "repository-1"){issues(first: 101){nodes{number}}}}`,
+		error: map[string]interface{}{
+			"type":    "EXCESSIVE_PAGINATION",
+			"path":    []interface{}{"repository", "issues"},
+			"message": "Requesting 101 records on the `issues` connection exceeds the `first` limit of 100 records.",
+		},
+	}} {
+		_, response := post(require, s, c.query, nil)
+		require.Equal([]interface{}{c.error}, response["errors"], c.query)
+	}
+
+	_, response := post(require, s, `{repository`, nil)
+	require.Nil(response["data"])
+	require.Len(response["errors"], 1)
+}
+
+func TestFaults(t *testing.T) {
+	require := require.New(t)
+
+	s := New(Seed(1, "acme", DefaultSize))
+	defer s.Close()
+
+	reset := time.Now().Add(time.Minute)
+	s.Inject(BadGatewayFault(), AbuseFault(30*time.Second), AbuseFault(0), RateLimitFault(reset))
+
+	query := `{rateLimit{remaining}}`
+	resp, _ := post(require, s, query, nil)
+	require.Equal(http.StatusBadGateway, resp.StatusCode)
+
+	resp, _ = post(require, s, query, nil)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	require.Equal("30", resp.Header.Get("Retry-After"))
+
+	resp, _ = post(require, s, query, nil)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	require.Empty(resp.Header.Get("Retry-After"))
+
+	resp, _ = post(require, s, query, nil)
+	require.Equal(http.StatusForbidden, resp.StatusCode)
+	require.Equal("0", resp.Header.Get("X-RateLimit-Remaining"))
+	require.Equal(strconv.FormatInt(reset.Unix(), 10), resp.Header.Get("X-RateLimit-Reset"))
+
+	// the faults are not counted in the rate limit
+	resp, response := post(require, s, query, nil)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.Equal("4999", resp.Header.Get("X-RateLimit-Remaining"))
+	require.Equal(map[string]interface{}{"rateLimit": map[string]interface{}{"remaining": float64(4999)}}, response["data"])
+	require.Equal(5, s.Requests())
+
+	s.InjectFunc(func(request int) *Fault {
+		if request%2 == 0 {
+			return nil
+		}
+
+		fault := BadGatewayFault()
+		return &fault
+	})
+
+	resp, _ = post(require, s, query, nil)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	resp, _ = post(require, s, query, nil)
+	require.Equal(http.StatusBadGateway, resp.StatusCode)
+}
@@ -0,0 +1,525 @@
+package fakeserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// graph is the Dataset as the objects of the GraphQL schema
+type graph struct {
+	root *object
+	// nodes are the objects by their global ID, for the node queries
+	nodes map[string]*object
+	// owners are the organizations and users by their lowercase login, and
+	// repositories by their lowercase owner/name
+	owners       map[string]*object
+	repositories map[string]*object
+	userObjects  map[*User]*object
+	databaseIDs  map[string]int
+}
+
+// newGraph returns the graph of the Dataset. The rateLimit field of the
+// root object is given by the Server
+func newGraph(d *Dataset, rateLimit resolver) *graph {
+	g := &graph{
+		nodes:        make(map[string]*object),
+		owners:       make(map[string]*object),
+		repositories: make(map[string]*object),
+		userObjects:  make(map[*User]*object),
+		databaseIDs:  make(map[string]int),
+	}
+
+	for _, org := range d.Organizations {
+		g.organization(org)
+	}
+
+	g.root = newObject("Query", map[string]interface{}{
+		"organization": resolver(g.resolveOrganization),
+		"user":         resolver(g.resolveUser),
+		"repository":   resolver(g.resolveRepository),
+		"node":         resolver(g.resolveNode),
+		"nodes":        resolver(g.resolveNodes),
+		"rateLimit":    rateLimit,
+	})
+
+	return g
+}
+
+// register assigns the database ID, the global ID and the URL of a new
+// object of the given type, like GitHub they look like MDU6SXNzdWUx
+func (g *graph) register(o *object, url string) *object {
+	g.databaseIDs[o.typename]++
+	id := g.databaseIDs[o.typename]
+	nodeID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%02d:%s%d", len(o.typename), o.typename, id)))
+
+	o.fields["databaseId"] = id
+	o.fields["id"] = nodeID
+	o.fields["url"] = url
+	g.nodes[nodeID] = o
+	return o
+}
+
+func formatTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return formatTime(*t)
+}
+
+// count is an object with only the totalCount of a connection
+func count(typename string, n int) *object {
+	return newObject(typename, map[string]interface{}{"totalCount": n})
+}
+
+// emptyConnection is a connection of the resources that are not part of the
+// Dataset, like the projects
+func emptyConnection(typename string) *connection {
+	return &connection{typename: typename}
+}
+
+// has returns true if the argument is not set, or if it is a value or a list
+// of values that contains the given one
+func has(arg interface{}, v string) bool {
+	switch arg := arg.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		for _, a := range arg {
+			if a == v {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return arg == v
+	}
+}
+
+func (g *graph) user(u *User) *object {
+	if u == nil {
+		return nil
+	}
+
+	if o, ok := g.userObjects[u]; ok {
+		return o
+	}
+
+	o := newObject("User", map[string]interface{}{
+		"avatarUrl":    fmt.Sprintf("https://avatars.githubusercontent.com/%s", u.Login),
+		"bio":          u.Bio,
+		"company":      u.Company,
+		"createdAt":    formatTime(u.CreatedAt),
+		"updatedAt":    formatTime(u.CreatedAt),
+		"followers":    count("FollowerConnection", 0),
+		"following":    count("FollowingConnection", 0),
+		"isHireable":   false,
+		"location":     u.Location,
+		"login":        u.Login,
+		"name":         u.Name,
+		"repositories": emptyConnection("RepositoryConnection"),
+	}, "Actor", "Node", "RepositoryOwner", "ProjectOwner")
+
+	g.register(o, "https://github.com/"+u.Login)
+	g.userObjects[u] = o
+	g.owners[strings.ToLower(u.Login)] = o
+	return o
+}
+
+func (g *graph) users(users []*User) []*object {
+	var list []*object
+	for _, u := range users {
+		list = append(list, g.user(u))
+	}
+
+	return list
+}
+
+func (g *graph) organization(org *Organization) {
+	o := newObject("Organization", map[string]interface{}{
+		"avatarUrl":       fmt.Sprintf("https://avatars.githubusercontent.com/%s", org.Login),
+		"createdAt":       formatTime(org.CreatedAt),
+		"updatedAt":       formatTime(org.CreatedAt),
+		"description":     org.Description,
+		"email":           org.Email,
+		"login":           org.Login,
+		"name":            org.Name,
+		"membersWithRole": &connection{typename: "OrganizationMemberConnection", nodes: g.users(org.Members)},
+		"projects":        emptyConnection("ProjectConnection"),
+		"projectsV2":      emptyConnection("ProjectV2Connection"),
+	}, "Actor", "Node", "RepositoryOwner", "ProjectOwner")
+
+	g.register(o, "https://github.com/"+org.Login)
+	g.owners[strings.ToLower(org.Login)] = o
+
+	var repositories []*object
+	for _, repo := range org.Repositories {
+		repositories = append(repositories, g.repository(o, org.Login, repo))
+	}
+
+	o.fields["repositories"] = &connection{
+		typename: "RepositoryConnection",
+		nodes:    repositories,
+		filter: func(args map[string]interface{}, node *object) bool {
+			if privacy, ok := args["privacy"].(string); ok && node.fields["isPrivate"] != (privacy == "PRIVATE") {
+				return false
+			}
+
+			if isFork, ok := args["isFork"].(bool); ok && node.fields["isFork"] != isFork {
+				return false
+			}
+
+			return true
+		},
+	}
+}
+
+func (g *graph) repository(owner *object, login string, repo *Repository) *object {
+	nameWithOwner := login + "/" + repo.Name
+	o := newObject("Repository", map[string]interface{}{
+		"mergeCommitAllowed": true,
+		"rebaseMergeAllowed": true,
+		"squashMergeAllowed": true,
+		"isArchived":         repo.IsArchived,
+		"isDisabled":         false,
+		"isFork":             repo.IsFork,
+		"isPrivate":          repo.IsPrivate,
+		"createdAt":          formatTime(repo.CreatedAt),
+		"pushedAt":           formatTime(repo.CreatedAt),
+		"updatedAt":          formatTime(repo.CreatedAt),
+		"defaultBranchRef":   newObject("Ref", map[string]interface{}{"name": "master"}),
+		"description":        repo.Description,
+		"forkCount":          0,
+		"nameWithOwner":      nameWithOwner,
+		"hasIssuesEnabled":   true,
+		"hasWikiEnabled":     true,
+		"homepageUrl":        "",
+		"primaryLanguage":    newObject("Language", map[string]interface{}{"name": repo.Language}),
+		"name":               repo.Name,
+		"owner":              owner,
+		"sshUrl":             fmt.Sprintf("git@github.com:%s.git", nameWithOwner),
+		"stargazers":         count("StargazerConnection", 0),
+		"watchers":           count("UserConnection", 0),
+		"projects":           emptyConnection("ProjectConnection"),
+		"projectsV2":         emptyConnection("ProjectV2Connection"),
+		"commitComments":     emptyConnection("CommitCommentConnection"),
+	}, "Node", "ProjectOwner")
+
+	g.register(o, "https://github.com/"+nameWithOwner)
+	g.repositories[strings.ToLower(nameWithOwner)] = o
+
+	var topics []*object
+	for _, t := range repo.Topics {
+		topics = append(topics, newObject("RepositoryTopic", map[string]interface{}{
+			"topic": newObject("Topic", map[string]interface{}{"name": t}),
+		}))
+	}
+
+	o.fields["repositoryTopics"] = &connection{typename: "RepositoryTopicConnection", nodes: topics}
+
+	issues := make(map[int]*object)
+	var issueNodes []*object
+	for _, issue := range repo.Issues {
+		i := g.issue(o, nameWithOwner, issue)
+		issues[issue.Number] = i
+		issueNodes = append(issueNodes, i)
+	}
+
+	prs := make(map[int]*object)
+	var prNodes []*object
+	for _, pr := range repo.PullRequests {
+		p := g.pullRequest(o, nameWithOwner, pr)
+		prs[pr.Number] = p
+		prNodes = append(prNodes, p)
+	}
+
+	byState := func(args map[string]interface{}, node *object) bool {
+		return has(args["states"], node.fields["state"].(string))
+	}
+
+	o.fields["issues"] = &connection{typename: "IssueConnection", nodes: issueNodes, filter: byState}
+	o.fields["pullRequests"] = &connection{typename: "PullRequestConnection", nodes: prNodes, filter: byState}
+	o.fields["issue"] = resolver(func(args map[string]interface{}) (interface{}, error) {
+		return byNumber(issues, "Issue", args)
+	})
+	o.fields["pullRequest"] = resolver(func(args map[string]interface{}) (interface{}, error) {
+		return byNumber(prs, "PullRequest", args)
+	})
+
+	return o
+}
+
+func byNumber(items map[int]*object, typename string, args map[string]interface{}) (interface{}, error) {
+	number, _ := toInt(args["number"])
+	if item, ok := items[number]; ok {
+		return item, nil
+	}
+
+	return nil, &fieldError{
+		Type:    "NOT_FOUND",
+		Message: fmt.Sprintf("Could not resolve to an %s with the number of %d.", typename, number),
+	}
+}
+
+// labels returns the connection of the labels with the given names
+func labelsConnection(names []string) *connection {
+	var nodes []*object
+	for _, name := range names {
+		nodes = append(nodes, newObject("Label", map[string]interface{}{"name": name}))
+	}
+
+	return &connection{typename: "LabelConnection", nodes: nodes}
+}
+
+func (g *graph) comments(url string, comments []*Comment) *connection {
+	var nodes []*object
+	for _, c := range comments {
+		o := newObject("IssueComment", map[string]interface{}{
+			"authorAssociation": "MEMBER",
+			"body":              c.Body,
+			"createdAt":         formatTime(c.CreatedAt),
+			"updatedAt":         formatTime(c.CreatedAt),
+			"author":            g.user(c.Author),
+		}, "Node", "Comment")
+
+		nodes = append(nodes, g.register(o, ""))
+		o.fields["url"] = fmt.Sprintf("%s#issuecomment-%d", url, o.fields["databaseId"])
+	}
+
+	return &connection{typename: "IssueCommentConnection", nodes: nodes}
+}
+
+func issueState(closedAt *time.Time) string {
+	if closedAt != nil {
+		return "CLOSED"
+	}
+
+	return "OPEN"
+}
+
+func (g *graph) issue(repository *object, nameWithOwner string, issue *Issue) *object {
+	url := fmt.Sprintf("https://github.com/%s/issues/%d", nameWithOwner, issue.Number)
+	o := newObject("Issue", map[string]interface{}{
+		"body":       issue.Body,
+		"closed":     issue.ClosedAt != nil,
+		"closedAt":   formatTimePtr(issue.ClosedAt),
+		"createdAt":  formatTime(issue.CreatedAt),
+		"updatedAt":  formatTime(issue.CreatedAt),
+		"locked":     false,
+		"number":     issue.Number,
+		"state":      issueState(issue.ClosedAt),
+		"title":      issue.Title,
+		"author":     g.user(issue.Author),
+		"assignees":  &connection{typename: "UserConnection", nodes: g.users(issue.Assignees)},
+		"labels":     labelsConnection(issue.Labels),
+		"comments":   g.comments(url, issue.Comments),
+		"repository": repository,
+	}, "Node", "Comment", "Assignable", "Labelable", "ProjectItemContent")
+
+	var timeline []*object
+	if issue.ClosedAt != nil {
+		timeline = append(timeline, newObject("ClosedEvent", map[string]interface{}{
+			"actor":     g.user(issue.ClosedBy),
+			"createdAt": formatTimePtr(issue.ClosedAt),
+		}, "Node", "IssueTimelineItems"))
+	}
+
+	o.fields["timelineItems"] = &connection{
+		typename: "IssueTimelineItemsConnection",
+		nodes:    timeline,
+		filter: func(args map[string]interface{}, node *object) bool {
+			return has(args["itemTypes"], "CLOSED_EVENT")
+		},
+	}
+
+	return g.register(o, url)
+}
+
+func (g *graph) ref(repository *object, name string, author *User) *object {
+	return newObject("Ref", map[string]interface{}{
+		"name":       name,
+		"repository": repository,
+		"target": newObject("Commit", map[string]interface{}{
+			"oid": oid(repository.fields["nameWithOwner"].(string) + name),
+			"author": newObject("GitActor", map[string]interface{}{
+				"user": g.user(author),
+			}),
+		}, "GitObject", "Node"),
+	}, "Node")
+}
+
+// oid returns a fake commit hash, always the same for the same content
+func oid(content string) string {
+	var hash [20]byte
+	for i := 0; i < len(content); i++ {
+		hash[i%len(hash)] = hash[i%len(hash)]*31 + content[i]
+	}
+
+	return fmt.Sprintf("%x", hash)
+}
+
+func (g *graph) pullRequest(repository *object, nameWithOwner string, pr *PullRequest) *object {
+	url := fmt.Sprintf("https://github.com/%s/pull/%d", nameWithOwner, pr.Number)
+	state := issueState(pr.ClosedAt)
+	var mergeCommit *object
+	if pr.MergedAt != nil {
+		state = "MERGED"
+		mergeCommit = newObject("Commit", map[string]interface{}{"oid": oid(url)}, "GitObject", "Node")
+	}
+
+	var reviewThreads int
+	var reviews []*object
+	for _, review := range pr.Reviews {
+		reviewThreads += len(review.Comments)
+		reviews = append(reviews, g.review(url, review))
+	}
+
+	o := newObject("PullRequest", map[string]interface{}{
+		"additions":           pr.Additions,
+		"authorAssociation":   "MEMBER",
+		"baseRef":             g.ref(repository, pr.BaseRef, pr.Author),
+		"baseRefName":         pr.BaseRef,
+		"body":                pr.Body,
+		"changedFiles":        pr.ChangedFiles,
+		"closed":              pr.ClosedAt != nil,
+		"closedAt":            formatTimePtr(pr.ClosedAt),
+		"commits":             count("PullRequestCommitConnection", 1),
+		"createdAt":           formatTime(pr.CreatedAt),
+		"updatedAt":           formatTime(pr.CreatedAt),
+		"deletions":           pr.Deletions,
+		"headRef":             g.ref(repository, pr.HeadRef, pr.Author),
+		"headRefName":         pr.HeadRef,
+		"locked":              false,
+		"maintainerCanModify": false,
+		"mergeCommit":         mergeCommit,
+		"mergeable":           "MERGEABLE",
+		"merged":              pr.MergedAt != nil,
+		"mergedAt":            formatTimePtr(pr.MergedAt),
+		"mergedBy":            g.user(pr.MergedBy),
+		"number":              pr.Number,
+		"reviewThreads":       count("PullRequestReviewThreadConnection", reviewThreads),
+		"state":               state,
+		"title":               pr.Title,
+		"author":              g.user(pr.Author),
+		"assignees":           &connection{typename: "UserConnection", nodes: g.users(pr.Assignees)},
+		"labels":              labelsConnection(pr.Labels),
+		"comments":            g.comments(url, pr.Comments),
+		"reviews":             &connection{typename: "PullRequestReviewConnection", nodes: reviews},
+		"repository":          repository,
+	}, "Node", "Comment", "Assignable", "Labelable", "ProjectItemContent")
+
+	return g.register(o, url)
+}
+
+func (g *graph) review(url string, review *Review) *object {
+	commit := newObject("Commit", map[string]interface{}{"oid": oid(url + review.Body)}, "GitObject", "Node")
+	o := newObject("PullRequestReview", map[string]interface{}{
+		"body":        review.Body,
+		"commit":      commit,
+		"state":       review.State,
+		"submittedAt": formatTime(review.SubmittedAt),
+		"createdAt":   formatTime(review.SubmittedAt),
+		"author":      g.user(review.Author),
+	}, "Node", "Comment")
+
+	g.register(o, "")
+	o.fields["url"] = fmt.Sprintf("%s#pullrequestreview-%d", url, o.fields["databaseId"])
+
+	var comments []*object
+	for _, c := range review.Comments {
+		co := newObject("PullRequestReviewComment", map[string]interface{}{
+			"authorAssociation": "MEMBER",
+			"body":              c.Body,
+			"commit":            commit,
+			"createdAt":         formatTime(c.CreatedAt),
+			"updatedAt":         formatTime(c.CreatedAt),
+			"diffHunk":          c.DiffHunk,
+			"originalCommit":    commit,
+			"originalPosition":  c.Position,
+			"path":              c.Path,
+			"position":          c.Position,
+			"author":            g.user(c.Author),
+			"pullRequestReview": o,
+		}, "Node", "Comment")
+
+		comments = append(comments, g.register(co, ""))
+		co.fields["url"] = fmt.Sprintf("%s#discussion_r%d", url, co.fields["databaseId"])
+	}
+
+	o.fields["comments"] = &connection{typename: "PullRequestReviewCommentConnection", nodes: comments}
+	return o
+}
+
+func (g *graph) resolveOrganization(args map[string]interface{}) (interface{}, error) {
+	login, _ := args["login"].(string)
+	if o, ok := g.owners[strings.ToLower(login)]; ok && o.typename == "Organization" {
+		return o, nil
+	}
+
+	return nil, &fieldError{
+		Type:    "NOT_FOUND",
+		Message: fmt.Sprintf("Could not resolve to an Organization with the login of '%s'.", login),
+	}
+}
+
+func (g *graph) resolveUser(args map[string]interface{}) (interface{}, error) {
+	login, _ := args["login"].(string)
+	if o, ok := g.owners[strings.ToLower(login)]; ok && o.typename == "User" {
+		return o, nil
+	}
+
+	return nil, &fieldError{
+		Type:    "NOT_FOUND",
+		Message: fmt.Sprintf("Could not resolve to a User with the login of '%s'.", login),
+	}
+}
+
+func (g *graph) resolveRepository(args map[string]interface{}) (interface{}, error) {
+	owner, _ := args["owner"].(string)
+	name, _ := args["name"].(string)
+	if o, ok := g.repositories[strings.ToLower(owner+"/"+name)]; ok {
+		return o, nil
+	}
+
+	return nil, &fieldError{
+		Type:    "NOT_FOUND",
+		Message: fmt.Sprintf("Could not resolve to a Repository with the name '%s/%s'.", owner, name),
+	}
+}
+
+func (g *graph) resolveNode(args map[string]interface{}) (interface{}, error) {
+	id, _ := args["id"].(string)
+	if o, ok := g.nodes[id]; ok {
+		return o, nil
+	}
+
+	return nil, &fieldError{
+		Type:    "NOT_FOUND",
+		Message: fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id),
+	}
+}
+
+// resolveNodes returns the nodes with the given IDs, null for the ones that
+// are not found. Unlike GitHub, it does not return their errors
+func (g *graph) resolveNodes(args map[string]interface{}) (interface{}, error) {
+	ids, _ := args["ids"].([]interface{})
+	nodes := make([]*object, len(ids))
+	for i, id := range ids {
+		if s, ok := id.(string); ok {
+			nodes[i] = g.nodes[s]
+		}
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,338 @@
+package fakeserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selection is a field, or an inline fragment when on is set, of a GraphQL
+// selection set
+type selection struct {
+	alias     string
+	name      string
+	arguments map[string]value
+	on        string
+	selection []selection
+}
+
+// key returns the name of the field in the response
+func (s selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+
+	return s.name
+}
+
+// value is an argument value: a variable, a literal or a list of them
+type value struct {
+	variable string
+	literal  interface{}
+	list     []value
+	isList   bool
+}
+
+// resolve returns the argument value with the variables replaced
+func (v value) resolve(variables map[string]interface{}) interface{} {
+	if v.variable != "" {
+		return variables[v.variable]
+	}
+
+	if v.isList {
+		list := make([]interface{}, len(v.list))
+		for i, item := range v.list {
+			list[i] = item.resolve(variables)
+		}
+
+		return list
+	}
+
+	return v.literal
+}
+
+// parser parses the subset of GraphQL used by the queries of the
+// downloaders: an anonymous query or an operation with variables, fields
+// with aliases and arguments, and inline fragments
+type parser struct {
+	input string
+	pos   int
+}
+
+// parseQuery returns the selection set of the given query
+func parseQuery(query string) ([]selection, error) {
+	p := &parser{input: query}
+	p.skip()
+	if p.consumeWord("query") {
+		p.consumeName()
+		if p.peek() == '(' {
+			if err := p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	set, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+
+	return set, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Parse error at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// skip skips the whitespace, the commas and the comments
+func (p *parser) skip() {
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.peek() != c {
+		return false
+	}
+
+	p.pos++
+	p.skip()
+	return true
+}
+
+func (p *parser) expect(c byte) error {
+	if !p.consume(c) {
+		return p.errorf("expected %q", c)
+	}
+
+	return nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (p *parser) consumeName() string {
+	start := p.pos
+	for p.pos < len(p.input) && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+
+	name := p.input[start:p.pos]
+	p.skip()
+	return name
+}
+
+func (p *parser) consumeWord(word string) bool {
+	end := p.pos + len(word)
+	if !strings.HasPrefix(p.input[p.pos:], word) || end < len(p.input) && isNameChar(p.input[end]) {
+		return false
+	}
+
+	p.pos = end
+	p.skip()
+	return true
+}
+
+// skipVariableDefinitions skips the variable definitions of the operation,
+// their values are given in the request
+func (p *parser) skipVariableDefinitions() error {
+	depth := 0
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				p.skip()
+				return nil
+			}
+		}
+
+		p.pos++
+	}
+
+	return p.errorf("unterminated variable definitions")
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	var set []selection
+	for !p.consume('}') {
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("unterminated selection set")
+		}
+
+		s, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+
+		set = append(set, s)
+	}
+
+	return set, nil
+}
+
+func (p *parser) selection() (selection, error) {
+	var s selection
+	if strings.HasPrefix(p.input[p.pos:], "...") {
+		p.pos += 3
+		p.skip()
+		if !p.consumeWord("on") {
+			return s, p.errorf("only inline fragments are supported")
+		}
+
+		s.on = p.consumeName()
+		set, err := p.selectionSet()
+		s.selection = set
+		return s, err
+	}
+
+	s.name = p.consumeName()
+	if s.name == "" {
+		return s, p.errorf("expected a field")
+	}
+
+	if p.consume(':') {
+		s.alias = s.name
+		s.name = p.consumeName()
+	}
+
+	if p.consume('(') {
+		s.arguments = make(map[string]value)
+		for !p.consume(')') {
+			name := p.consumeName()
+			if name == "" {
+				return s, p.errorf("expected an argument")
+			}
+
+			if err := p.expect(':'); err != nil {
+				return s, err
+			}
+
+			v, err := p.value()
+			if err != nil {
+				return s, err
+			}
+
+			s.arguments[name] = v
+		}
+	}
+
+	if p.peek() == '{' {
+		set, err := p.selectionSet()
+		if err != nil {
+			return s, err
+		}
+
+		s.selection = set
+	}
+
+	return s, nil
+}
+
+func (p *parser) value() (value, error) {
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		return value{variable: p.consumeName()}, nil
+	case c == '[':
+		p.consume('[')
+		v := value{isList: true}
+		for !p.consume(']') {
+			if p.pos >= len(p.input) {
+				return v, p.errorf("unterminated list")
+			}
+
+			item, err := p.value()
+			if err != nil {
+				return v, err
+			}
+
+			v.list = append(v.list, item)
+		}
+
+		return v, nil
+	case c == '"':
+		return p.stringValue()
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.input) && strings.IndexByte("0123456789.eE+-", p.input[p.pos]) >= 0 {
+			p.pos++
+		}
+
+		n, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return value{}, p.errorf("invalid number %q", p.input[start:p.pos])
+		}
+
+		p.skip()
+		return value{literal: n}, nil
+	}
+
+	switch name := p.consumeName(); name {
+	case "":
+		return value{}, p.errorf("expected a value")
+	case "true", "false":
+		return value{literal: name == "true"}, nil
+	case "null":
+		return value{}, nil
+	default:
+		// enum values are kept as strings
+		return value{literal: name}, nil
+	}
+}
+
+func (p *parser) stringValue() (value, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.input) && p.input[p.pos] != '"' {
+		if p.input[p.pos] == '\\' {
+			p.pos++
+		}
+
+		p.pos++
+	}
+
+	if p.pos >= len(p.input) {
+		return value{}, p.errorf("unterminated string")
+	}
+
+	p.pos++
+	s, err := strconv.Unquote(p.input[start:p.pos])
+	if err != nil {
+		return value{}, p.errorf("invalid string %s", p.input[start:p.pos])
+	}
+
+	p.skip()
+	return value{literal: s}, nil
+}
@@ -0,0 +1,253 @@
+// Package fakeserver provides an in-process fake of the GitHub GraphQL API,
+// serving a seeded Dataset, to test the downloaders offline.
+//
+// Unlike the recorded cassettes, it runs the queries it receives: the fields
+// of the schema are resolved from the Dataset, the connections are paginated
+// with cursors, and the fields that are not part of it are null, so the tests
+// keep working when new fields are requested. Faults like rate limits, abuse
+// detection and 502 responses can be injected in the responses.
+package fakeserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimit is the number of requests allowed per hour
+	rateLimit = 5000
+	// abuseDocumentationURL is the documentation_url of the abuse responses,
+	// it is used to detect them when they have no Retry-After header
+	abuseDocumentationURL = "https://developer.github.com/v3/#abuse-rate-limits"
+)
+
+// Server is a fake GitHub GraphQL API serving a Dataset at the /graphql
+// path. It is closed with Close
+type Server struct {
+	// URL is the base URL of the server, like http://127.0.0.1:1234
+	URL string
+
+	server *httptest.Server
+	graph  *graph
+
+	mu       sync.Mutex
+	faults   []Fault
+	schedule func(request int) *Fault
+	requests int
+	// remaining and reset are the rate limit status, returned in the headers
+	// of the responses and by the rateLimit query. The requests are counted,
+	// but the limit is only hit with a RateLimitFault
+	remaining int
+	reset     time.Time
+}
+
+// New starts a Server serving the given Dataset
+func New(d *Dataset) *Server {
+	s := &Server{
+		remaining: rateLimit,
+		reset:     time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+
+	s.graph = newGraph(d, s.rateLimit)
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an HTTP client that sends the requests for
+// https://api.github.com to the server, it can be given to
+// github.NewDownloader
+func (s *Server) Client() *http.Client {
+	return &http.Client{Transport: &redirectTransport{url: s.server.URL, T: s.server.Client().Transport}}
+}
+
+// Requests returns the number of requests received, including the ones
+// answered with faults
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// Inject makes the server answer the next requests with the given faults,
+// one request each, before answering the queries again
+func (s *Server) Inject(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, faults...)
+}
+
+// InjectFunc makes the server call the given function for each request, with
+// its number starting at 1, and answer it with the returned fault, if any.
+// The faults given to Inject are answered first
+func (s *Server) InjectFunc(schedule func(request int) *Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedule = schedule
+}
+
+// ServeHTTP implements the http.Handler interface
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	var fault *Fault
+	if len(s.faults) > 0 {
+		fault = &s.faults[0]
+		s.faults = s.faults[1:]
+	} else if s.schedule != nil {
+		fault = s.schedule(s.requests)
+	}
+
+	if fault == nil && s.remaining > 0 {
+		s.remaining--
+	}
+
+	remaining, reset := s.remaining, s.reset
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+	if fault != nil {
+		fault.write(w)
+		return
+	}
+
+	if r.URL.Path != "/graphql" && r.URL.Path != "/api/graphql" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+
+	var body struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON"})
+		return
+	}
+
+	data, errs := execute(s.graph.root, body.Query, body.Variables)
+	response := map[string]interface{}{"data": data}
+	if len(errs) > 0 {
+		response["errors"] = errs
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) rateLimit(args map[string]interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return newObject("RateLimit", map[string]interface{}{
+		"cost":      1,
+		"limit":     rateLimit,
+		"remaining": s.remaining,
+		"resetAt":   formatTime(s.reset),
+		"used":      rateLimit - s.remaining,
+	}), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// redirectTransport sends the requests to the server, keeping their path
+type redirectTransport struct {
+	url string
+	T   http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, err := req.URL.Parse(t.url)
+	if err != nil {
+		return nil, err
+	}
+
+	// the RoundTrippers must not modify the request
+	r := new(http.Request)
+	*r = *req
+	url := *req.URL
+	url.Scheme = target.Scheme
+	url.Host = target.Host
+	r.URL = &url
+	r.Host = target.Host
+	return t.T.RoundTrip(r)
+}
+
+// Fault is a response injected by the server instead of answering a query
+type Fault struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+func (f *Fault) write(w http.ResponseWriter) {
+	for name, values := range f.Header {
+		w.Header()[name] = values
+	}
+
+	w.WriteHeader(f.StatusCode)
+	w.Write([]byte(f.Body))
+}
+
+// RateLimitFault is a 403 response with no remaining requests until the
+// given reset time
+func RateLimitFault(reset time.Time) Fault {
+	return Fault{
+		StatusCode: http.StatusForbidden,
+		Header: http.Header{
+			"X-Ratelimit-Remaining": {"0"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+		},
+		Body: `{"message":"API rate limit exceeded for user ID 1.","documentation_url":"https://developer.github.com/v3/#rate-limiting"}`,
+	}
+}
+
+// AbuseFault is a 403 response of the abuse detection mechanism, with the
+// given Retry-After header, or without it if retryAfter is 0
+func AbuseFault(retryAfter time.Duration) Fault {
+	f := Fault{
+		StatusCode: http.StatusForbidden,
+		Header:     make(http.Header),
+		Body:       `{"message":"You have triggered an abuse detection mechanism. Please wait a few minutes before you try again.","documentation_url":"` + abuseDocumentationURL + `"}`,
+	}
+
+	if retryAfter > 0 {
+		f.Header.Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+	}
+
+	return f
+}
+
+// BadGatewayFault is a 502 response, like the ones GitHub returns when a
+// query takes too long
+func BadGatewayFault() Fault {
+	return Fault{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       "<html><body><h1>502 Bad Gateway</h1></body></html>",
+	}
+}
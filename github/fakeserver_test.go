@@ -0,0 +1,198 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github/fakeserver"
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// assignedMemory is a Memory that keeps a copy of the issues, and the
+// assignees and labels of the issues and PRs, by number. The saved issues are
+// overwritten by the next pages
+type assignedMemory struct {
+	*testutils.Memory
+	issues    map[int]graphql.Issue
+	assignees map[int][]string
+	labels    map[int][]string
+}
+
+func newAssignedMemory() *assignedMemory {
+	return &assignedMemory{
+		Memory:    &testutils.Memory{},
+		issues:    make(map[int]graphql.Issue),
+		assignees: make(map[int][]string),
+		labels:    make(map[int][]string),
+	}
+}
+
+func (s *assignedMemory) SaveIssue(ctx context.Context, owner, name string, issue *graphql.Issue, assignees []string, labels []string) error {
+	s.issues[issue.Number] = *issue
+	s.assignees[issue.Number], s.labels[issue.Number] = assignees, labels
+	return s.Memory.SaveIssue(ctx, owner, name, issue, assignees, labels)
+}
+
+func (s *assignedMemory) SavePullRequest(ctx context.Context, owner, name string, pr *graphql.PullRequest, assignees []string, labels []string) error {
+	s.assignees[pr.Number], s.labels[pr.Number] = assignees, labels
+	return s.Memory.SavePullRequest(ctx, owner, name, pr, assignees, labels)
+}
+
+func logins(users []*fakeserver.User) []string {
+	var list []string
+	for _, u := range users {
+		list = append(list, u.Login)
+	}
+
+	return list
+}
+
+func sorted(list []string) []string {
+	list = append([]string{}, list...)
+	sort.Strings(list)
+	return list
+}
+
+// requireRepository checks that the downloaded repository is the one of the
+// Dataset, with all the pages of its connections
+func requireRepository(t *testing.T, repo *fakeserver.Repository, storer *assignedMemory) {
+	require := require.New(t)
+
+	require.Equal(repo.Name, storer.Repository.Name)
+	require.Equal(sorted(repo.Topics), sorted(storer.Topics))
+	require.Len(storer.Issues, len(repo.Issues))
+	require.Len(storer.PRs, len(repo.PullRequests))
+
+	var comments int
+	for _, issue := range repo.Issues {
+		downloaded, ok := storer.issues[issue.Number]
+		require.True(ok, "issue #%d", issue.Number)
+		require.Equal(issue.Title, downloaded.Title)
+		require.Equal(issue.Author.Login, downloaded.Author.Login)
+		require.Equal(issue.ClosedAt != nil, downloaded.ClosedAt != nil)
+		require.Equal(sorted(logins(issue.Assignees)), sorted(storer.assignees[issue.Number]), "issue #%d", issue.Number)
+		require.Equal(sorted(issue.Labels), sorted(storer.labels[issue.Number]), "issue #%d", issue.Number)
+		if issue.ClosedBy != nil {
+			require.Equal(issue.ClosedBy.Login, downloaded.ClosedBy.Nodes[0].ClosedEvent.Actor.Login)
+		}
+
+		comments += len(issue.Comments)
+	}
+
+	require.Len(storer.IssueComments, comments)
+
+	var prComments, reviews, reviewComments int
+	for _, pr := range repo.PullRequests {
+		require.Equal(sorted(logins(pr.Assignees)), sorted(storer.assignees[pr.Number]), "PR #%d", pr.Number)
+		require.Equal(sorted(pr.Labels), sorted(storer.labels[pr.Number]), "PR #%d", pr.Number)
+		prComments += len(pr.Comments)
+		reviews += len(pr.Reviews)
+		for _, review := range pr.Reviews {
+			reviewComments += len(review.Comments)
+		}
+	}
+
+	require.Len(storer.PRComments, prComments)
+	require.Len(storer.PRReviews, reviews)
+	require.Len(storer.PRReviewComments, reviewComments)
+}
+
+func TestDownloadRepositoryFakeServer(t *testing.T) {
+	require := require.New(t)
+
+	dataset := fakeserver.Seed(1, "acme", fakeserver.DefaultSize)
+	server := fakeserver.New(dataset)
+	defer server.Close()
+
+	storer := newAssignedMemory()
+	d, err := NewDownloader(server.Client(), storer)
+	require.NoError(err)
+
+	require.NoError(d.DownloadRepository(context.TODO(), "acme", "repository-1", 0))
+	requireRepository(t, dataset.Organizations[0].Repositories[0], storer)
+
+	// the not found repositories fail
+	err = d.DownloadRepository(context.TODO(), "acme", "missing", 0)
+	require.Error(err)
+	require.Contains(err.Error(), "Could not resolve to a Repository with the name 'acme/missing'.")
+}
+
+func TestDownloadRepositoryFakeServerFaults(t *testing.T) {
+	require := require.New(t)
+
+	dataset := fakeserver.Seed(2, "acme", fakeserver.DefaultSize)
+	server := fakeserver.New(dataset)
+	defer server.Close()
+
+	client := server.Client()
+	client.Transport = NewRetryTransport(client.Transport, fastRetryPolicy())
+
+	storer := newAssignedMemory()
+	d, err := NewDownloader(client, storer)
+	require.NoError(err)
+
+	// the first query and the ones of the connections are retried
+	server.Inject(fakeserver.BadGatewayFault(), fakeserver.BadGatewayFault())
+	require.NoError(d.DownloadRepository(context.TODO(), "acme", "repository-2", 0))
+	requireRepository(t, dataset.Organizations[0].Repositories[1], storer)
+
+	// and all the requests of the next pages
+	storer = newAssignedMemory()
+	d, err = NewDownloader(client, storer)
+	require.NoError(err)
+
+	server.InjectFunc(func(request int) *fakeserver.Fault {
+		if request%10 != 0 {
+			return nil
+		}
+
+		fault := fakeserver.BadGatewayFault()
+		return &fault
+	})
+
+	require.NoError(d.DownloadRepository(context.TODO(), "acme", "repository-2", 0))
+	requireRepository(t, dataset.Organizations[0].Repositories[1], storer)
+}
+
+func TestDownloadOrganizationFakeServer(t *testing.T) {
+	require := require.New(t)
+
+	dataset := fakeserver.Seed(3, "acme", fakeserver.DefaultSize)
+	server := fakeserver.New(dataset)
+	defer server.Close()
+
+	client := server.Client()
+	storer := &testutils.Memory{}
+	d, err := NewDownloader(client, storer)
+	require.NoError(err)
+
+	require.NoError(d.DownloadOrganization(context.TODO(), "acme", 0))
+	require.Equal("acme", storer.Organization.Login)
+	require.Equal(len(dataset.Organizations[0].Repositories), storer.Organization.PublicRepos.TotalCount)
+
+	var users []string
+	for _, u := range storer.Users {
+		users = append(users, u.Login)
+	}
+
+	require.Equal(logins(dataset.Organizations[0].Members), users)
+
+	repos, err := d.ListRepositories(context.TODO(), "acme", true)
+	require.NoError(err)
+	require.Equal([]string{"repository-1", "repository-2"}, repos)
+
+	// the request is retried after the abuse limit
+	client.Transport = NewRetryTransport(NewRateLimitTransport(client.Transport, &testutils.LoggerMock{}), fastRetryPolicy())
+	d, err = NewDownloader(client, storer)
+	require.NoError(err)
+
+	server.Inject(fakeserver.AbuseFault(time.Second))
+	remaining, err := d.RateRemaining(context.TODO())
+	require.NoError(err)
+	require.True(remaining > 0 && remaining < 5000)
+}
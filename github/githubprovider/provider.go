@@ -0,0 +1,36 @@
+// Package githubprovider registers the GitHub Downloader in the provider
+// registry. It is kept apart from the github package so that the library
+// users of the downloader do not depend on the github/store package:
+//
+//	import _ "github.com/src-d/metadata-retrieval/github/githubprovider"
+package githubprovider
+
+import (
+	"strings"
+
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/github/store"
+	"github.com/src-d/metadata-retrieval/provider"
+)
+
+func init() {
+	provider.Register("github", newProvider)
+}
+
+// newProvider creates a Downloader for the provider registry, the base URL is
+// the one of a GitHub Enterprise Server instance, e.g.
+// https://github.example.com, or empty for github.com
+func newProvider(c provider.Config) (provider.Downloader, error) {
+	var storer github.Storer = &store.Stdout{}
+	if c.DB != nil {
+		storer = store.NewDB(c.DB)
+	}
+
+	var opts []github.Option
+	if c.BaseURL != "" {
+		url := strings.TrimSuffix(c.BaseURL, "/")
+		opts = append(opts, github.WithEndpoints(url+"/api/graphql", url+"/api/v3/"))
+	}
+
+	return github.NewDownloader(c.HTTPClient, storer, opts...)
+}
@@ -1,6 +1,9 @@
 package graphql
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // Connection represents common fields for paginated the connections
 type Connection struct {
@@ -55,20 +58,29 @@ type OrganizationFields struct {
 // OrganizationMemberConnection represents https://developer.github.com/v4/object/organizationmemberconnection/
 type OrganizationMemberConnection struct {
 	Connection
-	Nodes []UserExtended
+	Nodes []UserExtendedFields
 } // `graphql:"membersWithRole(first: $membersWithRolePage, after: $membersWithRoleCursor)"`
 
 func (c OrganizationMemberConnection) Len() int { return len(c.Nodes) }
 
-// UserExtended is the same type as User, but requesting more fields.
+// UserExtended is the same type as User, but with more fields. Email and the
+// gists are not available with GraphQL, they are filled from the REST API
+// when the Downloader uses WithRESTFallback.
 // Represents https://developer.github.com/v4/object/user/
 type UserExtended struct {
+	UserExtendedFields
+	Email        string // email text,
+	PrivateGists int    // private_gists bigint,
+	PublicGists  int    // public_gists bigint,
+}
+
+// UserExtendedFields are the fields of UserExtended requested with GraphQL
+type UserExtendedFields struct {
 	AvatarURL string    // avatar_url text,
 	Bio       string    // bio text,
 	Company   string    // company text,
 	CreatedAt time.Time // created_at timestamptz,
-	// TODO requires ['user:email', 'read:user'] scopes
-	//Email     string // email text,
+	// email requires ['user:email', 'read:user'] scopes, see UserExtended
 	Followers struct {
 		TotalCount int // followers bigint,
 	}
@@ -85,15 +97,8 @@ type UserExtended struct {
 	OwnedPrivateRepos struct {
 		TotalCount int // owned_private_repos bigint,
 	} `graphql:"owned_private_repos: repositories(privacy:PRIVATE, ownerAffiliations:OWNER)"`
-	/*
-		TODO: call returns: You don't have permission to see gists.
-			PrivateGists struct {
-				TotalCount int // private_gists bigint,
-			} `graphql:"private_gists: gists(privacy:SECRET)"`
-			PublicGists struct {
-				TotalCount int // public_gists bigint,
-			} `graphql:"public_gists: gists(privacy:PUBLIC)"`
-	*/
+	// gists(privacy:SECRET) returns: You don't have permission to see gists,
+	// see UserExtended
 	PublicRepos struct {
 		TotalCount int // public_repos bigint,
 	} `graphql:"public_repos: repositories(privacy:PUBLIC)"`
@@ -372,23 +377,32 @@ type PullRequestReviewFields struct {
 
 type PullRequestReviewCommentConnection struct {
 	Connection
-	Nodes []PullRequestReviewComment
+	Nodes []PullRequestReviewCommentFields
 }
 
 func (c PullRequestReviewCommentConnection) Len() int { return len(c.Nodes) }
 
+// PullRequestReviewComment represents
+// https://developer.github.com/v4/object/pullrequestreviewcomment/. InReplyTo
+// is filled from the REST API when the Downloader uses WithRESTFallback
 type PullRequestReviewComment struct {
+	PullRequestReviewCommentFields
+	InReplyTo int // in_reply_to bigint,
+}
+
+// PullRequestReviewCommentFields are the fields of PullRequestReviewComment
+// requested with GraphQL
+type PullRequestReviewCommentFields struct {
 	AuthorAssociation string // author_association text,
 	Body              string // body text,
 	Commit            struct {
 		Oid string // commit_id text,
 	}
-	CreatedAt  time.Time // created_at timestamptz,
-	DiffHunk   string    // diff_hunk text,
-	URL        string    // htmlurl text,
-	DatabaseID int       // id bigint,
-	//in_reply_to            string    // in_reply_to bigint,
-	ID             string // node_id text,
+	CreatedAt      time.Time // created_at timestamptz,
+	DiffHunk       string    // diff_hunk text,
+	URL            string    // htmlurl text,
+	DatabaseID     int       // id bigint,
+	ID             string    // node_id text,
 	OriginalCommit struct {
 		Oid string // original_commit_id text,
 	}
@@ -398,3 +412,266 @@ type PullRequestReviewComment struct {
 	UpdatedAt        time.Time // updated_at timestamptz,
 	Author           Actor     // user_id bigint NOT NULL, user_login text NOT NULL,
 }
+
+type CommitCommentConnection struct {
+	Connection
+	Nodes []CommitComment
+} // `graphql:"commitComments(first: $commitCommentsPage, after: $commitCommentsCursor)"`
+
+func (c CommitCommentConnection) Len() int { return len(c.Nodes) }
+
+type CommitComment struct {
+	AuthorAssociation string // author_association text,
+	Body              string // body text,
+	Commit            struct {
+		Oid string // commit_id text,
+	}
+	CreatedAt  time.Time // created_at timestamptz,
+	URL        string    // htmlurl text,
+	DatabaseID int       // id bigint,
+	ID         string    // node_id text,
+	Path       *string   // path text,
+	Position   *int      // position bigint,
+	UpdatedAt  time.Time // updated_at timestamptz,
+	Author     Actor     // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// ProjectConnection represents https://developer.github.com/v4/object/projectconnection/
+type ProjectConnection struct {
+	Connection
+	Nodes []Project
+} // `graphql:"projects(first: $projectsPage, after: $projectsCursor)"`
+
+func (c ProjectConnection) Len() int { return len(c.Nodes) }
+
+// Project represents https://developer.github.com/v4/object/project/
+type Project struct {
+	ProjectFields
+	Columns ProjectColumnConnection `graphql:"columns(first: $projectColumnsPage, after: $projectColumnsCursor)"`
+}
+
+// ProjectFields defines the fields for Project
+type ProjectFields struct {
+	Body      string     // body text,
+	Closed    bool       // closed boolean,
+	ClosedAt  *time.Time // closed_at timestamptz,
+	CreatedAt time.Time  // created_at timestamptz,
+	Creator   struct {
+		Login string // creator_login text NOT NULL,
+	}
+	URL        string    // htmlurl text,
+	DatabaseID int       // id bigint,
+	Name       string    // name text,
+	ID         string    // node_id text,
+	Number     int       // number bigint,
+	State      string    // state text,
+	UpdatedAt  time.Time // updated_at timestamptz,
+}
+
+// ProjectColumnConnection represents https://developer.github.com/v4/object/projectcolumnconnection/
+type ProjectColumnConnection struct {
+	Connection
+	Nodes []ProjectColumn
+} // `graphql:"columns(first: $projectColumnsPage, after: $projectColumnsCursor)"`
+
+func (c ProjectColumnConnection) Len() int { return len(c.Nodes) }
+
+// ProjectColumn represents https://developer.github.com/v4/object/projectcolumn/
+type ProjectColumn struct {
+	ProjectColumnFields
+	Cards ProjectCardConnection `graphql:"cards(first: $projectCardsPage, after: $projectCardsCursor)"`
+}
+
+// ProjectColumnFields defines the fields for ProjectColumn
+type ProjectColumnFields struct {
+	CreatedAt  time.Time // created_at timestamptz,
+	URL        string    // htmlurl text,
+	DatabaseID int       // id bigint,
+	Name       string    // name text,
+	ID         string    // node_id text,
+	Purpose    string    // purpose text,
+	UpdatedAt  time.Time // updated_at timestamptz,
+}
+
+// ProjectCardConnection represents https://developer.github.com/v4/object/projectcardconnection/
+type ProjectCardConnection struct {
+	Connection
+	Nodes []ProjectCard
+} // `graphql:"cards(first: $projectCardsPage, after: $projectCardsCursor)"`
+
+func (c ProjectCardConnection) Len() int { return len(c.Nodes) }
+
+// ProjectCard represents https://developer.github.com/v4/object/projectcard/
+type ProjectCard struct {
+	IsArchived bool               // archived boolean,
+	Content    ProjectItemContent // content_*
+	CreatedAt  time.Time          // created_at timestamptz,
+	Creator    struct {
+		Login string // creator_login text NOT NULL,
+	}
+	URL        string    // htmlurl text,
+	DatabaseID int       // id bigint,
+	ID         string    // node_id text,
+	Note       string    // note text,
+	State      string    // state text,
+	UpdatedAt  time.Time // updated_at timestamptz,
+}
+
+// ProjectItemContent is the Issue or PullRequest referenced by a ProjectCard
+// or a ProjectV2Item; DraftIssue is only used by ProjectV2Item
+type ProjectItemContent struct {
+	Typename    string           `graphql:"__typename"` // content_type text NOT NULL,
+	Issue       ProjectItemIssue `graphql:"... on Issue"`
+	PullRequest ProjectItemIssue `graphql:"... on PullRequest"`
+}
+
+// ProjectItemIssue contains the fields used to link a project item with an
+// Issue or a PullRequest
+type ProjectItemIssue struct {
+	ID         string // content_node_id text NOT NULL,
+	Number     int    // content_number bigint NOT NULL,
+	Title      string // content_title text NOT NULL,
+	Repository struct {
+		Name  string // content_repository_name text NOT NULL,
+		Owner struct {
+			Login string // content_repository_owner text NOT NULL,
+		}
+	}
+}
+
+// Linked returns the Issue or PullRequest referenced by the content, if any
+func (c ProjectItemContent) Linked() *ProjectItemIssue {
+	switch c.Typename {
+	case "Issue":
+		return &c.Issue
+	case "PullRequest":
+		return &c.PullRequest
+	default:
+		return nil
+	}
+}
+
+// ProjectV2Connection represents https://docs.github.com/en/graphql/reference/objects#projectv2connection
+type ProjectV2Connection struct {
+	Connection
+	Nodes []ProjectV2
+} // `graphql:"projectsV2(first: $projectsV2Page, after: $projectsV2Cursor)"`
+
+func (c ProjectV2Connection) Len() int { return len(c.Nodes) }
+
+// ProjectV2 represents https://docs.github.com/en/graphql/reference/objects#projectv2
+type ProjectV2 struct {
+	ProjectV2Fields
+	Items ProjectV2ItemConnection `graphql:"items(first: $projectV2ItemsPage, after: $projectV2ItemsCursor)"`
+}
+
+// ProjectV2Fields defines the fields for ProjectV2
+type ProjectV2Fields struct {
+	Closed    bool       // closed boolean,
+	ClosedAt  *time.Time // closed_at timestamptz,
+	CreatedAt time.Time  // created_at timestamptz,
+	Creator   struct {
+		Login string // creator_login text NOT NULL,
+	}
+	URL              string    // htmlurl text,
+	ID               string    // node_id text,
+	Number           int       // number bigint,
+	Public           bool      // public boolean,
+	Readme           string    // readme text,
+	ShortDescription string    // short_description text,
+	Title            string    // title text,
+	UpdatedAt        time.Time // updated_at timestamptz,
+}
+
+// ProjectV2ItemConnection represents https://docs.github.com/en/graphql/reference/objects#projectv2itemconnection
+type ProjectV2ItemConnection struct {
+	Connection
+	Nodes []ProjectV2Item
+} // `graphql:"items(first: $projectV2ItemsPage, after: $projectV2ItemsCursor)"`
+
+func (c ProjectV2ItemConnection) Len() int { return len(c.Nodes) }
+
+// ProjectV2Item represents https://docs.github.com/en/graphql/reference/objects#projectv2item
+type ProjectV2Item struct {
+	ProjectV2ItemFields
+	FieldValues ProjectV2ItemFieldValueConnection `graphql:"fieldValues(first: $projectV2FieldValuesPage, after: $projectV2FieldValuesCursor)"`
+}
+
+// ProjectV2ItemFields defines the fields for ProjectV2Item
+type ProjectV2ItemFields struct {
+	IsArchived bool // archived boolean,
+	Content    struct {
+		ProjectItemContent
+		DraftIssue struct {
+			Title string // content_title text NOT NULL,
+		} `graphql:"... on DraftIssue"`
+	} // content_*
+	CreatedAt time.Time // created_at timestamptz,
+	Creator   struct {
+		Login string // creator_login text NOT NULL,
+	}
+	ID        string    // node_id text,
+	Type      string    // type text,
+	UpdatedAt time.Time // updated_at timestamptz,
+}
+
+// ProjectV2ItemFieldValueConnection represents https://docs.github.com/en/graphql/reference/objects#projectv2itemfieldvalueconnection
+type ProjectV2ItemFieldValueConnection struct {
+	Connection
+	Nodes []ProjectV2ItemFieldValue
+} // `graphql:"fieldValues(first: $projectV2FieldValuesPage, after: $projectV2FieldValuesCursor)"`
+
+func (c ProjectV2ItemFieldValueConnection) Len() int { return len(c.Nodes) }
+
+// ProjectV2ItemFieldValue represents the union
+// https://docs.github.com/en/graphql/reference/unions#projectv2itemfieldvalue
+// Only the field types with a scalar representation are requested
+type ProjectV2ItemFieldValue struct {
+	Typename string `graphql:"__typename"`
+	Date     struct {
+		Date  string
+		Field ProjectV2FieldName
+	} `graphql:"... on ProjectV2ItemFieldDateValue"`
+	Iteration struct {
+		Title string
+		Field ProjectV2FieldName
+	} `graphql:"... on ProjectV2ItemFieldIterationValue"`
+	Number struct {
+		Number float64
+		Field  ProjectV2FieldName
+	} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+	SingleSelect struct {
+		Name  string
+		Field ProjectV2FieldName
+	} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+	Text struct {
+		Text  string
+		Field ProjectV2FieldName
+	} `graphql:"... on ProjectV2ItemFieldTextValue"`
+}
+
+// ProjectV2FieldName is the name of the field a ProjectV2ItemFieldValue belongs to
+type ProjectV2FieldName struct {
+	Common struct {
+		Name string
+	} `graphql:"... on ProjectV2FieldCommon"`
+}
+
+// NameValue returns the field name and the value as a string. The returned
+// name is empty for the field value types that are not requested
+func (v ProjectV2ItemFieldValue) NameValue() (string, string) {
+	switch v.Typename {
+	case "ProjectV2ItemFieldDateValue":
+		return v.Date.Field.Common.Name, v.Date.Date
+	case "ProjectV2ItemFieldIterationValue":
+		return v.Iteration.Field.Common.Name, v.Iteration.Title
+	case "ProjectV2ItemFieldNumberValue":
+		return v.Number.Field.Common.Name, strconv.FormatFloat(v.Number.Number, 'f', -1, 64)
+	case "ProjectV2ItemFieldSingleSelectValue":
+		return v.SingleSelect.Field.Common.Name, v.SingleSelect.Name
+	case "ProjectV2ItemFieldTextValue":
+		return v.Text.Field.Common.Name, v.Text.Text
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,287 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GraphQL error types returned by GitHub in the errors array of a response
+const (
+	graphqlNotFound               = "NOT_FOUND"
+	graphqlForbidden              = "FORBIDDEN"
+	graphqlResourceLimitsExceeded = "RESOURCE_LIMITS_EXCEEDED"
+	graphqlTimeout                = "TIMEOUT"
+)
+
+// GraphQLError is one of the errors returned in the body of a GraphQL
+// response, usually with a 200 OK status and partial data
+// https://graphql.github.io/graphql-spec/June2018/#sec-Errors
+type GraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	// Path is the field of the response that failed, its elements are the
+	// field names and the list indexes
+	Path []interface{} `json:"path"`
+}
+
+// PathString returns the path of the error separated by dots,
+// e.g. repository.issues.nodes.3.author
+func (e GraphQLError) PathString() string {
+	elems := make([]string, len(e.Path))
+	for i, elem := range e.Path {
+		elems[i] = fmt.Sprint(elem)
+	}
+
+	return strings.Join(elems, ".")
+}
+
+// isTimeout returns whether the query timed out. GitHub reports the timeouts
+// without type, only with a message mentioning them
+func (e GraphQLError) isTimeout() bool {
+	return e.Type == graphqlTimeout ||
+		strings.Contains(strings.ToLower(e.Message), "timeout")
+}
+
+// isNested returns whether the error is about a field of an item of a
+// connection, so the item and the rest of the response are still there
+func (e GraphQLError) isNested() bool {
+	for i := 0; i+2 < len(e.Path); i++ {
+		if e.Path[i] != "nodes" {
+			continue
+		}
+
+		if _, ok := e.Path[i+1].(float64); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GraphQLErrors are the errors of a GraphQL response
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+		if len(err.Path) > 0 {
+			messages[i] = fmt.Sprintf("%s (%s)", err.Message, err.PathString())
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e GraphQLErrors) graphqlErrors() GraphQLErrors {
+	return e
+}
+
+// nested returns whether all the errors are about fields of items of a
+// connection, so the data of the response is partial but usable
+func (e GraphQLErrors) nested() bool {
+	for _, err := range e {
+		if !err.isNested() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// graphqlErrorer is implemented by the typed GraphQL errors
+type graphqlErrorer interface {
+	error
+	graphqlErrors() GraphQLErrors
+}
+
+// ErrNotFound is returned when a GraphQL response has a NOT_FOUND error, like
+// the ones about resources deleted while downloading
+type ErrNotFound struct {
+	GraphQLErrors
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("not found: %s", e.GraphQLErrors.Error())
+}
+
+// ErrForbidden is returned when a GraphQL response has a FORBIDDEN error, like
+// the ones about resources the token can not access
+type ErrForbidden struct {
+	GraphQLErrors
+}
+
+func (e *ErrForbidden) Error() string {
+	return fmt.Sprintf("forbidden: %s", e.GraphQLErrors.Error())
+}
+
+// ErrResourceLimitsExceeded is returned when a GraphQL query exceeds the
+// resource limits of the API. It is transient, and retried by SetRetryTransport
+// https://developer.github.com/v4/guides/resource-limitations/
+type ErrResourceLimitsExceeded struct {
+	GraphQLErrors
+}
+
+func (e *ErrResourceLimitsExceeded) Error() string {
+	return fmt.Sprintf("resource limits exceeded: %s", e.GraphQLErrors.Error())
+}
+
+// ErrTimeout is returned when a GraphQL query times out. It is transient, and
+// retried by SetRetryTransport
+type ErrTimeout struct {
+	GraphQLErrors
+}
+
+func (e *ErrTimeout) Error() string {
+	return fmt.Sprintf("timeout: %s", e.GraphQLErrors.Error())
+}
+
+// ErrGraphQL is returned when a GraphQL response has errors of any other type
+type ErrGraphQL struct {
+	GraphQLErrors
+}
+
+func (e *ErrGraphQL) Error() string {
+	return fmt.Sprintf("graphql: %s", e.GraphQLErrors.Error())
+}
+
+// asGraphQLError returns the typed error for the errors of a GraphQL response,
+// or nil if there are none. The transient errors take precedence, otherwise the
+// type of the first error decides
+func asGraphQLError(errs GraphQLErrors) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, err := range errs {
+		if err.Type == graphqlResourceLimitsExceeded {
+			return &ErrResourceLimitsExceeded{errs}
+		}
+
+		if err.isTimeout() {
+			return &ErrTimeout{errs}
+		}
+	}
+
+	switch errs[0].Type {
+	case graphqlNotFound:
+		return &ErrNotFound{errs}
+	case graphqlForbidden:
+		return &ErrForbidden{errs}
+	default:
+		return &ErrGraphQL{errs}
+	}
+}
+
+// isTransient returns whether the error is a GraphQL error worth retrying
+func isTransient(err error) bool {
+	switch err.(type) {
+	case *ErrResourceLimitsExceeded, *ErrTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSkippable returns whether the error is a GraphQL error about an item that
+// can not be downloaded, but does not prevent downloading the rest
+func isSkippable(err error) bool {
+	switch err.(type) {
+	case *ErrNotFound, *ErrForbidden:
+		return true
+	default:
+		return false
+	}
+}
+
+// readGraphQLErrors reads the errors of a GraphQL response, restoring its
+// body. The bodies that are not GraphQL responses have no errors
+func readGraphQLErrors(resp *http.Response) (GraphQLErrors, error) {
+	bodyContent, err := readResponseAndRestore(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Errors GraphQLErrors `json:"errors"`
+	}
+
+	if err := json.Unmarshal(bodyContent, &body); err != nil {
+		return nil, nil
+	}
+
+	return body.Errors, nil
+}
+
+type graphqlErrorsKey struct{}
+
+// withGraphQLErrors returns a context where graphqlErrorsTransport keeps the
+// errors of the GraphQL response to the request made with it
+func withGraphQLErrors(ctx context.Context) (context.Context, *GraphQLErrors) {
+	errs := &GraphQLErrors{}
+	return context.WithValue(ctx, graphqlErrorsKey{}, errs), errs
+}
+
+// graphqlErrorsTransport keeps the errors of the 200 OK GraphQL responses in
+// the context of their request, since the GraphQL client only returns their
+// messages
+type graphqlErrorsTransport struct {
+	T http.RoundTripper
+}
+
+func (t *graphqlErrorsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.T.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	errs, ok := req.Context().Value(graphqlErrorsKey{}).(*GraphQLErrors)
+	if !ok {
+		return resp, nil
+	}
+
+	*errs, err = readGraphQLErrors(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// newGraphQLHTTPClient returns a copy of the HTTP client that keeps the
+// errors of the GraphQL responses with graphqlErrorsTransport
+func newGraphQLHTTPClient(httpClient *http.Client) *http.Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	client := *httpClient
+	client.Transport = &graphqlErrorsTransport{transport}
+	return &client
+}
+
+// unwrapGraphQLError returns the typed GraphQL error of a failed query, either
+// the one of its response errors, or the one returned by the transport, like
+// the transient errors after the retries of SetRetryTransport
+func unwrapGraphQLError(err error, errs GraphQLErrors) error {
+	if typed := asGraphQLError(errs); typed != nil {
+		return typed
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		if typed, ok := urlErr.Err.(graphqlErrorer); ok {
+			return typed
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,225 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func newGraphQLResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+// getGraphQLDownloader returns a Downloader that keeps the errors of the
+// GraphQL responses, answered by the given function
+func getGraphQLDownloader(storer Storer, f RoundTripFunc) *Downloader {
+	return &Downloader{
+		storer:     storer,
+		client:     githubv4.NewClient(newGraphQLHTTPClient(&http.Client{Transport: f})),
+		skippedLog: &skippedLog{},
+	}
+}
+
+func TestAsGraphQLError(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(asGraphQLError(nil))
+
+	notFound := GraphQLError{Type: "NOT_FOUND", Message: "Could not resolve to a node", Path: []interface{}{"node"}}
+	forbidden := GraphQLError{Type: "FORBIDDEN", Message: "Resource not accessible by integration"}
+	limits := GraphQLError{Type: "RESOURCE_LIMITS_EXCEEDED", Message: "Resource limits for this query exceeded"}
+	timeout := GraphQLError{Message: "Something went wrong while executing your query. This may be the result of a timeout, or it could be a GitHub bug."}
+	other := GraphQLError{Type: "MAX_NODE_LIMIT_EXCEEDED", Message: "This query requests too many nodes"}
+
+	require.IsType(&ErrNotFound{}, asGraphQLError(GraphQLErrors{notFound, forbidden}))
+	require.IsType(&ErrForbidden{}, asGraphQLError(GraphQLErrors{forbidden, notFound}))
+	require.IsType(&ErrResourceLimitsExceeded{}, asGraphQLError(GraphQLErrors{notFound, limits}))
+	require.IsType(&ErrTimeout{}, asGraphQLError(GraphQLErrors{timeout}))
+	require.IsType(&ErrGraphQL{}, asGraphQLError(GraphQLErrors{other}))
+
+	require.EqualError(asGraphQLError(GraphQLErrors{notFound}), "not found: Could not resolve to a node (node)")
+	require.True(isTransient(asGraphQLError(GraphQLErrors{timeout})))
+	require.False(isTransient(asGraphQLError(GraphQLErrors{notFound})))
+}
+
+func TestGraphQLErrorsNested(t *testing.T) {
+	require := require.New(t)
+
+	author := GraphQLError{Path: []interface{}{"repository", "issues", "nodes", float64(3), "author"}}
+	issue := GraphQLError{Path: []interface{}{"repository", "issues", "nodes", float64(3)}}
+	repository := GraphQLError{Path: []interface{}{"repository"}}
+
+	require.Equal("repository.issues.nodes.3.author", author.PathString())
+	require.True(GraphQLErrors{author}.nested())
+	require.False(GraphQLErrors{author, issue}.nested())
+	require.False(GraphQLErrors{repository}.nested())
+}
+
+func TestRetryTransientGraphQLErrors(t *testing.T) {
+	require := require.New(t)
+
+	var requests int
+	client := &http.Client{Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+		requests++
+		if requests == 1 {
+			return newGraphQLResponse(`{"data":null,"errors":[{"type":"RESOURCE_LIMITS_EXCEEDED","message":"Resource limits for this query exceeded"}]}`)
+		}
+
+		return newGraphQLResponse(`{"data":{"viewer":{"login":"octocat"}}}`)
+	})}
+	SetRetryTransport(client)
+
+	resp, err := client.Post("https://api.github.com/graphql", "application/json", strings.NewReader(`{"query":"{viewer{login}}"}`))
+	require.NoError(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(err)
+	require.Equal(`{"data":{"viewer":{"login":"octocat"}}}`, string(body))
+	require.Equal(2, requests)
+
+	// the other errors are not transient
+	requests = 0
+	client.Transport = NewRetryTransport(RoundTripFunc(func(req *http.Request) *http.Response {
+		requests++
+		return newGraphQLResponse(`{"data":{"node":null},"errors":[{"type":"NOT_FOUND","path":["node"],"message":"Could not resolve to a node"}]}`)
+	}), DefaultRetryPolicy())
+
+	_, err = client.Post("https://api.github.com/graphql", "application/json", strings.NewReader(`{"query":"{node{id}}"}`))
+	require.NoError(err)
+	require.Equal(1, requests)
+}
+
+func TestQueryTypedErrors(t *testing.T) {
+	require := require.New(t)
+
+	d := getGraphQLDownloader(&testutils.Memory{}, func(req *http.Request) *http.Response {
+		return newGraphQLResponse(`{"data":{"repository":{"issue":null}},
+			"errors":[{"type":"NOT_FOUND","path":["repository","issue"],"message":"Could not resolve to an Issue with the number of 9."}]}`)
+	})
+
+	var q struct {
+		Repository struct {
+			Issue struct {
+				Number int
+			} `graphql:"issue(number: 9)"`
+		} `graphql:"repository(owner: \"src-d\", name: \"gitbase\")"`
+	}
+
+	err := d.query(context.TODO(), &q, nil)
+	require.IsType(&ErrNotFound{}, err)
+	require.Equal("repository.issue", err.(*ErrNotFound).GraphQLErrors[0].PathString())
+
+	// it is not skipped, it is the queried item
+	d.skipped = &skippedItems{}
+	require.IsType(&ErrNotFound{}, d.query(context.TODO(), &q, nil))
+	require.Empty(d.skipped.items)
+}
+
+func TestSkipConnection(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := getGraphQLDownloader(storer, func(req *http.Request) *http.Response {
+		return newGraphQLResponse(`{"data":{"node":null},
+			"errors":[{"type":"NOT_FOUND","path":["node"],"message":"Could not resolve to a node with the global id of 'I1'"}]}`)
+	})
+
+	issue := &graphql.Issue{}
+	issue.ID = "I1"
+	issue.Comments.PageInfo.HasNextPage = true
+	issue.Comments.TotalCount = 20
+
+	// only skipped in DownloadRepository
+	err := d.downloadIssueComments(context.TODO(), "src-d", "gitbase", issue)
+	require.Error(err)
+
+	d.skipped = &skippedItems{}
+	require.NoError(d.downloadIssueComments(context.TODO(), "src-d", "gitbase", issue))
+	require.Equal([]SkippedItem{{
+		Type:    "NOT_FOUND",
+		Path:    "node(I1).issueComments",
+		Message: "Could not resolve to a node with the global id of 'I1' (node)",
+	}}, d.skipped.items)
+	require.Equal("1 NOT_FOUND", d.skipped.summary())
+}
+
+func TestDownloadRepositorySkipped(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := getGraphQLDownloader(storer, func(req *http.Request) *http.Response {
+		body, _ := ioutil.ReadAll(req.Body)
+		if !strings.Contains(string(body), "repository(owner:") {
+			return newGraphQLResponse(`{"data":{"node":null},
+				"errors":[{"type":"FORBIDDEN","path":["node"],"message":"Resource not accessible by integration"}]}`)
+		}
+
+		return newGraphQLResponse(`{"data":{"repository":{"id":"R1","nameWithOwner":"src-d/gitbase",
+			"issues":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"id":"I1","number":1,"title":"crash on start","author":null,
+				"assignees":{"pageInfo":{"hasNextPage":false},"totalCount":0,"nodes":[]},
+				"labels":{"pageInfo":{"hasNextPage":false},"totalCount":0,"nodes":[]},
+				"comments":{"pageInfo":{"hasNextPage":false},"totalCount":0,"nodes":[]}}]}}},
+			"errors":[{"type":"FORBIDDEN","path":["repository","issues","nodes",0,"author"],"message":"Resource not accessible by integration"}]}`)
+	})
+	d.projects = true
+	d.commitComments = true
+
+	// the author of the issue, the projects and the commit comments are skipped
+	require.NoError(d.DownloadRepository(context.TODO(), "src-d", "gitbase", 0))
+	require.Equal("src-d/gitbase", storer.Repository.NameWithOwner)
+	require.Len(storer.Issues, 1)
+	require.Equal(1, storer.Issues[0].Number)
+	require.Empty(storer.CommitComments)
+
+	forbidden := "Resource not accessible by integration (node)"
+	require.Equal([]SkippedItem{
+		{Type: "FORBIDDEN", Path: "repository.issues.nodes.0.author", Message: "Resource not accessible by integration"},
+		{Type: "FORBIDDEN", Path: "node(R1).projects", Message: forbidden},
+		{Type: "FORBIDDEN", Path: "node(R1).commitComments", Message: forbidden},
+	}, d.Skipped())
+
+	// each download reports its own skipped items
+	d.projects, d.commitComments = false, false
+	require.NoError(d.DownloadRepository(context.TODO(), "src-d", "gitbase", 0))
+	require.Len(d.Skipped(), 1)
+}
+
+func TestDownloadOrganizationSkipped(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := getGraphQLDownloader(storer, func(req *http.Request) *http.Response {
+		body, _ := ioutil.ReadAll(req.Body)
+		if !strings.Contains(string(body), "organization(login:") {
+			return newGraphQLResponse(`{"data":{"node":null},
+				"errors":[{"type":"FORBIDDEN","path":["node"],"message":"Resource not accessible by integration"}]}`)
+		}
+
+		return newGraphQLResponse(`{"data":{"organization":{"id":"O1","login":"acme",
+			"membersWithRole":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"id":"U1","login":"alice","email":null}]}}},
+			"errors":[{"type":"FORBIDDEN","path":["organization","membersWithRole","nodes",0,"email"],"message":"Resource not accessible by integration"}]}`)
+	})
+	d.projects = true
+
+	// the email of the member and the projects are skipped
+	require.NoError(d.DownloadOrganization(context.TODO(), "acme", 0))
+	require.Equal("acme", storer.Organization.Login)
+	require.Len(storer.Users, 1)
+	require.Equal("alice", storer.Users[0].Login)
+	require.Equal([]SkippedItem{
+		{Type: "FORBIDDEN", Path: "organization.membersWithRole.nodes.0.email", Message: "Resource not accessible by integration"},
+		{Type: "FORBIDDEN", Path: "node(O1).projects", Message: "Resource not accessible by integration (node)"},
+	}, d.Skipped())
+}
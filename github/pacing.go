@@ -0,0 +1,71 @@
+package github
+
+import (
+	"time"
+)
+
+// costWeight is the weight of the last observed cost in the average cost of
+// the requests
+const costWeight = 0.2
+
+// RateLimitOption configures optional behaviour of a RateLimitTransport
+type RateLimitOption func(*RateLimitTransport)
+
+// WithPacing makes the RateLimitTransport spread the remaining requests of the
+// token evenly until the rate limit reset, instead of waiting only once they
+// are exhausted, to avoid the secondary rate limits in long downloads. The
+// reserve requests are never used, they are left for interactive use. The
+// interval between requests adapts to their cost, observed from the decrease
+// of the remaining requests. Each rate limit resource is paced on its own
+func WithPacing(reserve int) RateLimitOption {
+	return func(rt *RateLimitTransport) {
+		rt.pacers = make(map[string]*pacer)
+		rt.pacingReserve = reserve
+	}
+}
+
+// pacer computes the wait before each request to spread the remaining
+// requests until the reset
+type pacer struct {
+	reserve int
+	// cost is the average cost of a request, in remaining requests
+	cost float64
+	// last is when the last request was sent
+	last time.Time
+	// prev is the rate limit status of the previous response
+	prev RateStatus
+}
+
+// wait returns how long to wait before the next request, given the status of
+// the last response
+func (p *pacer) wait(status RateStatus, now time.Time) time.Duration {
+	remaining, ok := status.Headroom(now)
+	if !ok {
+		return 0
+	}
+
+	untilReset := status.Reset.Sub(now)
+	available := remaining - p.reserve
+	if available <= 0 {
+		return untilReset
+	}
+
+	interval := time.Duration(float64(untilReset) * p.cost / float64(available))
+	if next := p.last.Add(interval); next.After(now) {
+		return next.Sub(now)
+	}
+
+	return 0
+}
+
+// observe updates the average cost of the requests with the status of a
+// response, the cost is only known within the same rate limit window
+func (p *pacer) observe(status RateStatus) {
+	if p.prev.Known && p.prev.Reset.Equal(status.Reset) {
+		if spent := p.prev.Remaining - status.Remaining; spent >= 0 {
+			p.cost = (1-costWeight)*p.cost + costWeight*float64(spent)
+		}
+	}
+
+	p.prev = status
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacerWait(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	status := RateStatus{Known: true, Remaining: 1100, Reset: now.Add(time.Hour)}
+	p := &pacer{reserve: 100, cost: 1, last: now}
+
+	// 1000 requests available for an hour
+	require.Equal(3600*time.Millisecond, p.wait(status, now))
+	require.Equal(597*time.Millisecond, p.wait(status, now.Add(3*time.Second)))
+
+	// the requests cost twice as much
+	p.cost = 2
+	require.Equal(7200*time.Millisecond, p.wait(status, now))
+
+	// only the reserve is left, it waits until the reset
+	status.Remaining = 100
+	require.Equal(time.Hour, p.wait(status, now))
+
+	// unknown status, after the reset
+	require.Equal(time.Duration(0), p.wait(status, now.Add(2*time.Hour)))
+	require.Equal(time.Duration(0), p.wait(RateStatus{}, now))
+}
+
+func TestPacerObserve(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(time.Hour)
+	p := &pacer{cost: 1}
+
+	p.observe(RateStatus{Known: true, Remaining: 1000, Reset: reset})
+	require.Equal(1.0, p.cost)
+
+	p.observe(RateStatus{Known: true, Remaining: 994, Reset: reset})
+	require.InDelta(2.0, p.cost, 0.0001)
+
+	// a new window does not tell the cost
+	p.observe(RateStatus{Known: true, Remaining: 5000, Reset: reset.Add(time.Hour)})
+	require.InDelta(2.0, p.cost, 0.0001)
+}
+
+func TestRateLimitTransportPacing(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(time.Hour)
+	remaining := 1010
+	rt := NewRateLimitTransport(RoundTripFunc(func(req *http.Request) *http.Response {
+		remaining--
+		resp, _ := newResponse(apiResponse{Data: "success"}, nil, http.StatusOK)
+		resp.Header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		return resp
+	}), &testutils.LoggerMock{}, WithPacing(10))
+
+	var sleeps []time.Duration
+	rt.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	for i := 0; i < 3; i++ {
+		_, err := rt.RoundTrip(newRequest("/normal"))
+		require.NoError(err)
+	}
+
+	// the first request is not paced, the rate limit status is unknown. The
+	// sleeps are not real, so each request waits for the turn reserved by the
+	// previous one
+	require.Len(sleeps, 2)
+	require.InDelta(3600*time.Millisecond, sleeps[0], float64(100*time.Millisecond))
+	require.InDelta(7200*time.Millisecond, sleeps[1], float64(100*time.Millisecond))
+
+	require.Equal(1007, rt.RateStatus().Remaining)
+}
+
+func TestRateLimitTransportResources(t *testing.T) {
+	require := require.New(t)
+
+	reset := time.Now().Add(time.Hour)
+	remaining := map[string]int{"graphql": 5000, "core": 30}
+	cost := map[string]int{"graphql": 1, "core": 3}
+	rt := NewRateLimitTransport(RoundTripFunc(func(req *http.Request) *http.Response {
+		resource := "core"
+		if req.URL.Path == "/graphql" {
+			resource = "graphql"
+		}
+
+		remaining[resource] -= cost[resource]
+		resp, _ := newResponse(apiResponse{Data: "success"}, nil, http.StatusOK)
+		resp.Header.Set("X-RateLimit-Resource", resource)
+		resp.Header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining[resource]))
+		resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		return resp
+	}), &testutils.LoggerMock{}, WithPacing(0))
+
+	var sleeps []time.Duration
+	rt.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	graphqlReq := newRequest("https://api.github.com/graphql")
+	coreReq := newRequest("https://api.github.com/users/smola")
+	require.Equal("graphql", requestRateResource(graphqlReq))
+	require.Equal("core", requestRateResource(coreReq))
+
+	// the REST requests use up the core rate limit, the last one hits it
+	for i := 0; i < 10; i++ {
+		_, err := rt.RoundTrip(graphqlReq)
+		require.NoError(err)
+
+		_, err = rt.RoundTrip(coreReq)
+		if i < 9 {
+			require.NoError(err)
+		} else {
+			require.IsType(&ErrRateLimit{}, err)
+		}
+	}
+
+	// each resource keeps its own status and cost
+	require.Equal(4990, rt.RateStatus().Remaining)
+	require.Equal(rt.RateStatus(), rt.ResourceRateStatus("graphql"))
+	require.Equal(0, rt.ResourceRateStatus("core").Remaining)
+	require.InDelta(1.0, rt.pacers["graphql"].cost, 0.0001)
+	require.InDelta(3.0, rt.pacers["core"].cost, 0.5)
+
+	// the GraphQL requests are only paced, not locked out by the core limit
+	sleeps = nil
+	_, err := rt.RoundTrip(graphqlReq)
+	require.NoError(err)
+	for _, d := range sleeps {
+		require.True(d < time.Minute, "unexpected sleep of %s", d)
+	}
+
+	require.Equal(4989, rt.RateStatus().Remaining)
+}
@@ -0,0 +1,184 @@
+package github
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	minPageSize = 1
+	maxPageSize = 100
+	// fastQuery is the duration of the queries fast enough to request bigger
+	// pages after them
+	fastQuery = 2 * time.Second
+)
+
+// PageSizes are the page sizes learned for the connections of each repository
+// or organization: they shrink after the queries time out, and grow again
+// after fast ones, see WithPageSizes. They can be saved to a file to be
+// loaded in later runs, so the huge repositories start with the sizes that
+// work for them. The same PageSizes can be given to several Downloaders
+type PageSizes struct {
+	mu sync.Mutex
+	// sizes are the page sizes of each connection, by repository or
+	// organization
+	sizes map[string]map[string]int
+}
+
+// NewPageSizes returns an empty PageSizes
+func NewPageSizes() *PageSizes {
+	return &PageSizes{sizes: make(map[string]map[string]int)}
+}
+
+// LoadPageSizes reads the PageSizes saved in the given file, it returns an
+// empty PageSizes if the file does not exist yet
+func LoadPageSizes(path string) (*PageSizes, error) {
+	p := NewPageSizes()
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &p.sizes); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Save writes the PageSizes to the given file
+func (p *PageSizes) Save(path string) error {
+	p.mu.Lock()
+	content, err := json.MarshalIndent(p.sizes, "", "  ")
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// defaultPageSize returns the page size of the connections without a learned
+// one, the maximum except for the pull requests
+func defaultPageSize(t connectionType) githubv4.Int {
+	// github timeouts and returns 502 too often with 100 limit
+	if t == pullRequestsType {
+		return t.PageSize
+	}
+
+	return maxPageSize
+}
+
+// get returns the page size of the connection of the given repository or
+// organization, by default the maximum one except for the pull requests
+func (p *PageSizes) get(key string, t connectionType) githubv4.Int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if size, ok := p.sizes[key][t.Name]; ok {
+		return githubv4.Int(size)
+	}
+
+	return defaultPageSize(t)
+}
+
+func (p *PageSizes) set(key string, t connectionType, size githubv4.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sizes[key] == nil {
+		p.sizes[key] = make(map[string]int)
+	}
+
+	p.sizes[key][t.Name] = int(size)
+}
+
+// shrink halves the page size of the connection after a query of the given
+// size timed out, it returns false if it is already the minimum one
+func (p *PageSizes) shrink(key string, t connectionType, size githubv4.Int) bool {
+	if size <= minPageSize {
+		return false
+	}
+
+	p.set(key, t, size/2)
+	return true
+}
+
+// grow increases the page size of the connection by a quarter after a fast
+// query
+func (p *PageSizes) grow(key string, t connectionType) {
+	size := p.get(key, t)
+	if size >= maxPageSize {
+		return
+	}
+
+	size += (size + 3) / 4
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	p.set(key, t, size)
+}
+
+// pageSizer adapts the page sizes of the connections of a repository or an
+// organization. Without PageSizes it keeps the default ones
+type pageSizer struct {
+	sizes *PageSizes
+	key   string
+}
+
+func (s pageSizer) limit(t connectionType) githubv4.Int {
+	if s.sizes == nil {
+		return defaultPageSize(t)
+	}
+
+	return s.sizes.get(s.key, t)
+}
+
+// first returns the size of the first page of a connection, requested along
+// with the items that contain it: the default size of the connection, or the
+// learned one when it is smaller
+func (s pageSizer) first(t connectionType) githubv4.Int {
+	if limit := s.limit(t); limit < t.PageSize {
+		return limit
+	}
+
+	return t.PageSize
+}
+
+// shrink halves the page size after a timeout, it returns false if it can
+// not be smaller
+func (s pageSizer) shrink(t connectionType, size githubv4.Int) bool {
+	return s.sizes != nil && s.sizes.shrink(s.key, t, size)
+}
+
+// canShrink returns whether any of the pages of the connections in the
+// variables of a query can be smaller after a timeout
+func (s pageSizer) canShrink(variables map[string]interface{}, connections []connectionType) bool {
+	if s.sizes == nil {
+		return false
+	}
+
+	for _, c := range connections {
+		if size, ok := variables[c.Page()].(githubv4.Int); ok && size > minPageSize {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s pageSizer) grow(t connectionType) {
+	if s.sizes != nil {
+		s.sizes.grow(s.key, t)
+	}
+}
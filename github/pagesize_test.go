@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageSizes(t *testing.T) {
+	require := require.New(t)
+
+	p := NewPageSizes()
+	require.Equal(githubv4.Int(100), p.get("src-d/gitbase", issuesType))
+	require.Equal(githubv4.Int(35), p.get("src-d/gitbase", pullRequestsType))
+
+	require.True(p.shrink("src-d/gitbase", issuesType, 100))
+	require.Equal(githubv4.Int(50), p.get("src-d/gitbase", issuesType))
+	p.grow("src-d/gitbase", issuesType)
+	require.Equal(githubv4.Int(63), p.get("src-d/gitbase", issuesType))
+
+	// each repository learns its own sizes
+	require.Equal(githubv4.Int(100), p.get("src-d/go-git", issuesType))
+
+	require.True(p.shrink("src-d/gitbase", pullRequestsType, 2))
+	require.False(p.shrink("src-d/gitbase", pullRequestsType, 1))
+	require.Equal(githubv4.Int(1), p.get("src-d/gitbase", pullRequestsType))
+
+	dir, err := ioutil.TempDir("", "pagesizes")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "page-sizes.json")
+	loaded, err := LoadPageSizes(path)
+	require.NoError(err)
+	require.Equal(githubv4.Int(100), loaded.get("src-d/gitbase", issuesType))
+
+	require.NoError(p.Save(path))
+	loaded, err = LoadPageSizes(path)
+	require.NoError(err)
+	require.Equal(githubv4.Int(63), loaded.get("src-d/gitbase", issuesType))
+	require.Equal(githubv4.Int(1), loaded.get("src-d/gitbase", pullRequestsType))
+}
+
+func TestAdaptivePageSize(t *testing.T) {
+	require := require.New(t)
+
+	var sizes []int
+	client := &http.Client{Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+		var body struct {
+			Variables struct {
+				IssueCommentsPage int
+			}
+		}
+		require.NoError(json.NewDecoder(req.Body).Decode(&body))
+
+		size := body.Variables.IssueCommentsPage
+		sizes = append(sizes, size)
+		if size > 25 {
+			resp := newGraphQLResponse("<html>502 Bad Gateway</html>")
+			resp.StatusCode = http.StatusBadGateway
+			resp.Status = "502 Bad Gateway"
+			return resp
+		}
+
+		return newGraphQLResponse(`{"data":{"node":{"comments":{"pageInfo":{"hasNextPage":false},"totalCount":200,"nodes":[]}}}}`)
+	})}
+	SetRetryTransport(client)
+
+	storer := &testutils.Memory{}
+	d := &Downloader{
+		storer:    storer,
+		client:    githubv4.NewClient(newGraphQLHTTPClient(client)),
+		pageSizes: NewPageSizes(),
+	}
+	d.pages = d.pageSizer("src-d/gitbase")
+
+	issue := &graphql.Issue{}
+	issue.ID = "I1"
+	issue.Comments.PageInfo.HasNextPage = true
+	issue.Comments.TotalCount = 200
+
+	// the timed out queries are retried with smaller pages, not as they are
+	require.NoError(d.downloadIssueComments(context.TODO(), "src-d", "gitbase", issue))
+	require.Equal([]int{100, 50, 25}, sizes)
+
+	// it grows after the fast query
+	require.Equal(githubv4.Int(32), d.pageSizes.get("src-d/gitbase", issueCommentsType))
+}
+
+func TestAdaptiveFirstPageSize(t *testing.T) {
+	require := require.New(t)
+
+	var sizes [][]int
+	client := &http.Client{Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+		var body struct {
+			Variables struct {
+				AssigneesPage     int
+				IssueCommentsPage int
+				LabelsPage        int
+			}
+		}
+		require.NoError(json.NewDecoder(req.Body).Decode(&body))
+
+		v := body.Variables
+		sizes = append(sizes, []int{v.IssueCommentsPage, v.AssigneesPage, v.LabelsPage})
+		if v.IssueCommentsPage > 2 {
+			resp := newGraphQLResponse("<html>502 Bad Gateway</html>")
+			resp.StatusCode = http.StatusBadGateway
+			resp.Status = "502 Bad Gateway"
+			return resp
+		}
+
+		return newGraphQLResponse(`{"data":{"repository":{"issue":{"number":1}}}}`)
+	})}
+	SetRetryTransport(client)
+
+	storer := &testutils.Memory{}
+	d := &Downloader{
+		storer:    storer,
+		client:    githubv4.NewClient(newGraphQLHTTPClient(client)),
+		pageSizes: NewPageSizes(),
+	}
+	d.pageSizes.set("src-d/gitbase", issueCommentsType, 4)
+
+	// the first pages start with the learned sizes, and the timed out query
+	// is retried with half of them
+	require.NoError(d.DownloadIssue(context.TODO(), "src-d", "gitbase", 1, 0))
+	require.Equal([][]int{{4, 2, 2}, {2, 1, 1}}, sizes)
+	require.Len(storer.Issues, 1)
+	require.Equal(githubv4.Int(2), d.pageSizes.get("src-d/gitbase", issueCommentsType))
+}
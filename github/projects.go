@@ -0,0 +1,310 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+
+	"github.com/shurcooL/githubv4"
+)
+
+var (
+	projectsConnections   = []connectionType{projectsType, projectColumnsType, projectCardsType}
+	projectsV2Connections = []connectionType{projectsV2Type, projectV2ItemsType, projectV2FieldValuesType}
+)
+
+// connectionVariables returns the variables for the node with the given id
+// and the first pages of the given connections, with the cursors set to nil
+func (d Downloader) connectionVariables(id string, connections []connectionType) map[string]interface{} {
+	variables := map[string]interface{}{
+		"id": githubv4.ID(id),
+	}
+	for _, c := range connections {
+		variables[c.Page()] = d.pages.first(c)
+		variables[c.Cursor()] = (*githubv4.String)(nil)
+	}
+
+	return variables
+}
+
+type repositoryProjectsQ struct {
+	Node struct {
+		Repository struct {
+			Projects graphql.ProjectConnection `graphql:"projects(first: $projectsPage, after: $projectsCursor)"`
+		} `graphql:"... on Repository"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *repositoryProjectsQ) Connection() Connection {
+	return q.Node.Repository.Projects
+}
+
+type repositoryProjectsV2Q struct {
+	Node struct {
+		Repository struct {
+			ProjectsV2 graphql.ProjectV2Connection `graphql:"projectsV2(first: $projectsV2Page, after: $projectsV2Cursor)"`
+		} `graphql:"... on Repository"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *repositoryProjectsV2Q) Connection() Connection {
+	return q.Node.Repository.ProjectsV2
+}
+
+// downloadRepositoryProjects downloads the classic projects and the projects
+// (v2) linked to the given repository
+func (d Downloader) downloadRepositoryProjects(ctx context.Context, owner string, name string, repository *graphql.Repository) error {
+	var q repositoryProjectsQ
+	err := d.downloadProjects(ctx, owner, name, &q, d.connectionVariables(repository.ID, projectsConnections))
+	if err != nil || !d.projectsV2 {
+		return err
+	}
+
+	var qV2 repositoryProjectsV2Q
+	return d.downloadProjectsV2(ctx, owner, name, &qV2, d.connectionVariables(repository.ID, projectsV2Connections))
+}
+
+type organizationProjectsQ struct {
+	Node struct {
+		Organization struct {
+			Projects graphql.ProjectConnection `graphql:"projects(first: $projectsPage, after: $projectsCursor)"`
+		} `graphql:"... on Organization"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *organizationProjectsQ) Connection() Connection {
+	return q.Node.Organization.Projects
+}
+
+type organizationProjectsV2Q struct {
+	Node struct {
+		Organization struct {
+			ProjectsV2 graphql.ProjectV2Connection `graphql:"projectsV2(first: $projectsV2Page, after: $projectsV2Cursor)"`
+		} `graphql:"... on Organization"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *organizationProjectsV2Q) Connection() Connection {
+	return q.Node.Organization.ProjectsV2
+}
+
+// downloadOrganizationProjects downloads the classic projects and the
+// projects (v2) owned by the given organization
+func (d Downloader) downloadOrganizationProjects(ctx context.Context, organization *graphql.Organization) error {
+	var q organizationProjectsQ
+	err := d.downloadProjects(ctx, organization.Login, "", &q, d.connectionVariables(organization.ID, projectsConnections))
+	if err != nil || !d.projectsV2 {
+		return err
+	}
+
+	var qV2 organizationProjectsV2Q
+	return d.downloadProjectsV2(ctx, organization.Login, "", &qV2, d.connectionVariables(organization.ID, projectsV2Connections))
+}
+
+// downloadProjects downloads the classic projects returned by q; repositoryName
+// is empty for the projects owned by an organization
+func (d Downloader) downloadProjects(ctx context.Context, owner string, repositoryName string, q Query, variables map[string]interface{}) error {
+	if err := d.queryFirst(ctx, q, variables); err != nil {
+		if d.skipConnection(ctx, projectsType.Name, variables["id"], err) {
+			return nil
+		}
+
+		return fmt.Errorf("projects query failed: %v", err)
+	}
+
+	process := func(res Connection) error {
+		projects := res.(graphql.ProjectConnection)
+		for _, project := range projects.Nodes {
+			if err := d.storer.SaveProject(ctx, owner, repositoryName, &project); err != nil {
+				return fmt.Errorf("failed to save project #%v: %v", project.Number, err)
+			}
+
+			if err := d.downloadProjectColumns(ctx, &project); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return d.downloadConnection(ctx, projectsType, q.Connection(), q, variables, process)
+}
+
+type projectColumnsQ struct {
+	Node struct {
+		Project struct {
+			Columns graphql.ProjectColumnConnection `graphql:"columns(first: $projectColumnsPage, after: $projectColumnsCursor)"`
+		} `graphql:"... on Project"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *projectColumnsQ) Connection() Connection {
+	return q.Node.Project.Columns
+}
+
+func (d Downloader) downloadProjectColumns(ctx context.Context, project *graphql.Project) error {
+	var q projectColumnsQ
+	variables := map[string]interface{}{
+		"id": githubv4.ID(project.ID),
+	}
+	variables[projectCardsType.Page()] = d.pages.first(projectCardsType)
+	variables[projectCardsType.Cursor()] = (*githubv4.String)(nil)
+
+	process := func(res Connection) error {
+		columns := res.(graphql.ProjectColumnConnection)
+		for _, column := range columns.Nodes {
+			if err := d.storer.SaveProjectColumn(ctx, project.ID, &column); err != nil {
+				return fmt.Errorf("failed to save column %q of project #%v: %v", column.Name, project.Number, err)
+			}
+
+			if err := d.downloadProjectCards(ctx, project, &column); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return d.downloadConnection(ctx, projectColumnsType, project.Columns, &q, variables, process)
+}
+
+type projectCardsQ struct {
+	Node struct {
+		ProjectColumn struct {
+			Cards graphql.ProjectCardConnection `graphql:"cards(first: $projectCardsPage, after: $projectCardsCursor)"`
+		} `graphql:"... on ProjectColumn"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *projectCardsQ) Connection() Connection {
+	return q.Node.ProjectColumn.Cards
+}
+
+func (d Downloader) downloadProjectCards(ctx context.Context, project *graphql.Project, column *graphql.ProjectColumn) error {
+	var q projectCardsQ
+	variables := map[string]interface{}{
+		"id": githubv4.ID(column.ID),
+	}
+
+	process := func(res Connection) error {
+		cards := res.(graphql.ProjectCardConnection)
+		for _, card := range cards.Nodes {
+			if err := d.storer.SaveProjectCard(ctx, project.ID, column.ID, &card); err != nil {
+				return fmt.Errorf("failed to save card of project #%v: %v", project.Number, err)
+			}
+		}
+
+		return nil
+	}
+
+	return d.downloadConnection(ctx, projectCardsType, column.Cards, &q, variables, process)
+}
+
+// downloadProjectsV2 downloads the projects (v2) returned by q; repositoryName
+// is empty for the projects owned by an organization
+func (d Downloader) downloadProjectsV2(ctx context.Context, owner string, repositoryName string, q Query, variables map[string]interface{}) error {
+	if err := d.queryFirst(ctx, q, variables); err != nil {
+		if d.skipConnection(ctx, projectsV2Type.Name, variables["id"], err) {
+			return nil
+		}
+
+		return fmt.Errorf("projects v2 query failed: %v", err)
+	}
+
+	process := func(res Connection) error {
+		projects := res.(graphql.ProjectV2Connection)
+		for _, project := range projects.Nodes {
+			if err := d.storer.SaveProjectV2(ctx, owner, repositoryName, &project); err != nil {
+				return fmt.Errorf("failed to save project v2 #%v: %v", project.Number, err)
+			}
+
+			if err := d.downloadProjectV2Items(ctx, &project); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return d.downloadConnection(ctx, projectsV2Type, q.Connection(), q, variables, process)
+}
+
+type projectV2ItemsQ struct {
+	Node struct {
+		ProjectV2 struct {
+			Items graphql.ProjectV2ItemConnection `graphql:"items(first: $projectV2ItemsPage, after: $projectV2ItemsCursor)"`
+		} `graphql:"... on ProjectV2"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *projectV2ItemsQ) Connection() Connection {
+	return q.Node.ProjectV2.Items
+}
+
+func (d Downloader) downloadProjectV2Items(ctx context.Context, project *graphql.ProjectV2) error {
+	var q projectV2ItemsQ
+	variables := map[string]interface{}{
+		"id": githubv4.ID(project.ID),
+	}
+	variables[projectV2FieldValuesType.Page()] = d.pages.first(projectV2FieldValuesType)
+	variables[projectV2FieldValuesType.Cursor()] = (*githubv4.String)(nil)
+
+	process := func(res Connection) error {
+		items := res.(graphql.ProjectV2ItemConnection)
+		for _, item := range items.Nodes {
+			fieldValues, err := d.downloadProjectV2ItemFieldValues(ctx, &item)
+			if err != nil {
+				return err
+			}
+
+			if err := d.storer.SaveProjectV2Item(ctx, project.ID, &item, fieldValues); err != nil {
+				return fmt.Errorf("failed to save item of project v2 #%v: %v", project.Number, err)
+			}
+		}
+
+		return nil
+	}
+
+	return d.downloadConnection(ctx, projectV2ItemsType, project.Items, &q, variables, process)
+}
+
+type projectV2ItemFieldValuesQ struct {
+	Node struct {
+		ProjectV2Item struct {
+			FieldValues graphql.ProjectV2ItemFieldValueConnection `graphql:"fieldValues(first: $projectV2FieldValuesPage, after: $projectV2FieldValuesCursor)"`
+		} `graphql:"... on ProjectV2Item"`
+	} `graphql:"node(id:$id)"`
+}
+
+func (q *projectV2ItemFieldValuesQ) Connection() Connection {
+	return q.Node.ProjectV2Item.FieldValues
+}
+
+// downloadProjectV2ItemFieldValues returns the item field values indexed by
+// the field name
+func (d Downloader) downloadProjectV2ItemFieldValues(ctx context.Context, item *graphql.ProjectV2Item) (map[string]string, error) {
+	var q projectV2ItemFieldValuesQ
+	variables := map[string]interface{}{
+		"id": githubv4.ID(item.ID),
+	}
+
+	values := map[string]string{}
+	process := func(res Connection) error {
+		fieldValues := res.(graphql.ProjectV2ItemFieldValueConnection)
+		for _, node := range fieldValues.Nodes {
+			name, value := node.NameValue()
+			if name != "" {
+				values[name] = value
+			}
+		}
+		return nil
+	}
+
+	err := d.downloadConnection(ctx, projectV2FieldValuesType, item.FieldValues, &q, variables, process)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,162 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github/fakeserver"
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	projectsResponse = `{"data":{"node":{"projects":{
+		"pageInfo":{"hasNextPage":false,"endCursor":"a"},"totalCount":1,
+		"nodes":[{"name":"Sprint 1","number":1,"id":"P1","state":"OPEN","creator":{"login":"alice"},
+			"columns":{"pageInfo":{"hasNextPage":false},"totalCount":1,"nodes":[{"name":"To do","id":"C1",
+				"cards":{"pageInfo":{"hasNextPage":false},"totalCount":2,"nodes":[
+					{"id":"CARD1","note":"","content":{"__typename":"Issue","id":"I1","number":10,"repository":{"name":"gitbase","owner":{"login":"src-d"}}}},
+					{"id":"CARD2","note":"remember the milk","content":null}
+				]}}]}}]}}}}`
+	projectsV2Response = `{"data":{"node":{"projectsV2":{
+		"pageInfo":{"hasNextPage":false},"totalCount":1,
+		"nodes":[{"title":"Roadmap","number":2,"id":"PV2",
+			"items":{"pageInfo":{"hasNextPage":false},"totalCount":2,"nodes":[
+				{"id":"ITEM1","type":"PULL_REQUEST","content":{"__typename":"PullRequest","id":"PR1","number":20,"repository":{"name":"gitbase","owner":{"login":"src-d"}}},
+					"fieldValues":{"pageInfo":{"hasNextPage":true,"endCursor":"fv1"},"totalCount":2,"nodes":[
+						{"__typename":"ProjectV2ItemFieldSingleSelectValue","name":"Done","field":{"name":"Status"}}]}},
+				{"id":"ITEM2","type":"DRAFT_ISSUE","content":{"__typename":"DraftIssue","title":"an idea"},
+					"fieldValues":{"pageInfo":{"hasNextPage":false},"totalCount":0,"nodes":[]}}
+			]}}]}}}}`
+	fieldValuesResponse = `{"data":{"node":{"fieldValues":{
+		"pageInfo":{"hasNextPage":false},"totalCount":2,"nodes":[
+			{"__typename":"ProjectV2ItemFieldNumberValue","number":3,"field":{"name":"Points"}}]}}}}`
+)
+
+func projectsTransport(t *testing.T) RoundTripFunc {
+	return RoundTripFunc(func(req *http.Request) *http.Response {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		var data string
+		switch query := string(body); {
+		case strings.Contains(query, "fieldValues(first: $projectV2FieldValuesPage") &&
+			!strings.Contains(query, "projectsV2("):
+			data = fieldValuesResponse
+		case strings.Contains(query, "projectsV2("):
+			data = projectsV2Response
+		case strings.Contains(query, "projects("):
+			data = projectsResponse
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(data)),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func TestDownloadOrganizationProjects(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.Memory{}
+	d := &Downloader{
+		storer:     storer,
+		client:     githubv4.NewClient(&http.Client{Transport: projectsTransport(t)}),
+		projects:   true,
+		projectsV2: true,
+	}
+
+	org := &graphql.Organization{}
+	org.Login = "src-d"
+	org.ID = "O1"
+	require.NoError(storer.SaveOrganization(context.TODO(), org))
+	require.NoError(d.downloadOrganizationProjects(context.TODO(), org))
+
+	require.Len(storer.Projects, 1)
+	require.Equal("Sprint 1", storer.Projects[0].Name)
+	require.Len(storer.ProjectColumns, 1)
+	require.Len(storer.ProjectCards, 2)
+
+	require.Len(storer.ProjectsV2, 1)
+	require.Len(storer.ProjectV2Items, 2)
+	require.Equal("Roadmap", storer.ProjectsV2[0].Title)
+}
+
+func TestProjectV2ItemFieldValues(t *testing.T) {
+	require := require.New(t)
+
+	d := &Downloader{
+		client: githubv4.NewClient(&http.Client{Transport: projectsTransport(t)}),
+	}
+
+	item := &graphql.ProjectV2Item{}
+	item.ID = "ITEM1"
+	item.FieldValues.PageInfo.HasNextPage = true
+	item.FieldValues.TotalCount = 2
+	item.FieldValues.Nodes = []graphql.ProjectV2ItemFieldValue{{Typename: "ProjectV2ItemFieldTextValue"}}
+	item.FieldValues.Nodes[0].Text.Text = "needs review"
+	item.FieldValues.Nodes[0].Text.Field.Common.Name = "Notes"
+
+	values, err := d.downloadProjectV2ItemFieldValues(context.TODO(), item)
+	require.NoError(err)
+	require.Equal(map[string]string{"Notes": "needs review", "Points": "3"}, values)
+}
+
+// TestProjectsOptIn checks that the projects are only downloaded
+// WithProjects, the tokens without the read:project scope can download the
+// rest of the organizations and repositories
+func TestProjectsOptIn(t *testing.T) {
+	require := require.New(t)
+
+	dataset := fakeserver.Seed(4, "acme", fakeserver.DefaultSize)
+	server := fakeserver.New(dataset)
+	defer server.Close()
+
+	// the queries of the projects fail like with a token without the scope
+	client := server.Client()
+	transport := client.Transport
+	client.Transport = RoundTripFunc(func(req *http.Request) *http.Response {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(err)
+
+		if !strings.Contains(string(body), "projects(") {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			resp, err := transport.RoundTrip(req)
+			require.NoError(err)
+			return resp
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{"data":null,"errors":[{"type":"INSUFFICIENT_SCOPES",
+				"message":"Your token has not been granted the required scopes to execute this query. The 'projects' field requires one of the following scopes: ['read:project']"}]}`)),
+			Header: make(http.Header),
+		}
+	})
+
+	storer := &testutils.Memory{}
+	d, err := NewDownloader(client, storer)
+	require.NoError(err)
+
+	require.NoError(d.DownloadOrganization(context.TODO(), "acme", 0))
+	require.NoError(d.DownloadRepository(context.TODO(), "acme", "repository-1", 0))
+	require.Equal("acme", storer.Organization.Login)
+	require.Equal("repository-1", storer.Repository.Name)
+	require.Empty(storer.Projects)
+
+	d, err = NewDownloader(client, &testutils.Memory{}, WithProjects())
+	require.NoError(err)
+
+	err = d.DownloadRepository(context.TODO(), "acme", "repository-1", 0)
+	require.Error(err)
+	require.Contains(err.Error(), "read:project")
+}
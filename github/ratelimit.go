@@ -26,28 +26,102 @@ var (
 // and it no longer process any further Requests until the Limit has been expired.
 // RateLimitTransport does not retry; that behaviour must be implemented by another Transport
 // Each client (with its own token) should use its own RateLimitTransport
+// The requests are sent serially, unless WithConcurrency is used
+// GitHub keeps a rate limit for each resource, the REST API v3 requests count
+// against the "core" one and the GraphQL ones against "graphql". The status,
+// the lockout and the pacing are kept for each of them, as reported by the
+// 'X-RateLimit-Resource' header
 type RateLimitTransport struct {
+	// the mutex guards lockedUntil and pacers, it is only held to read or
+	// update them, never while a request is in flight
 	sync.Mutex
 
-	transport         http.RoundTripper
-	lockedUntil       time.Time
+	transport http.RoundTripper
+	// lockedUntil is the end of the lockout of each resource
+	lockedUntil       map[string]time.Time
 	logger            log.Logger
 	defaultAbuseSleep time.Duration
+
+	// sem bounds the number of requests in flight, one unless WithConcurrency
+	// is used. A request holds its slot while it waits for the lockout or the
+	// pacer, and until its response is received
+	sem chan struct{}
+
+	// statuses is guarded by its own mutex, it is read by RateStatus at any
+	// time, also by wait while it holds the main one
+	statusMu sync.RWMutex
+	statuses map[string]RateStatus
+
+	// pacers, when set, spread the requests of each resource until its rate
+	// limit reset. They are created as the resources are seen
+	pacers        map[string]*pacer
+	pacingReserve int
+	sleep         func(time.Duration)
+}
+
+// DefaultRateResource is the rate limit resource of the responses without the
+// 'X-RateLimit-Resource' header, and of the requests that are not sent to the
+// REST API v3
+const DefaultRateResource = "graphql"
+
+// coreRateResource is the rate limit resource of the REST API v3
+const coreRateResource = "core"
+
+// RateStatus is the rate limit status of a token, as reported by the headers
+// of the last API response
+type RateStatus struct {
+	// Known is false until a response with the rate limit headers is received
+	Known     bool
+	Remaining int
+	Reset     time.Time
+}
+
+// Headroom returns the remaining requests at the given time, the status is
+// considered unknown after its reset. ok is false for an unknown status
+func (s RateStatus) Headroom(now time.Time) (remaining int, ok bool) {
+	if !s.Known || !now.Before(s.Reset) {
+		return 0, false
+	}
+
+	return s.Remaining, true
 }
 
 // SetRateLimitTransport wraps the passed client.Transport with a RateLimitTransport
-func SetRateLimitTransport(client *http.Client, logger log.Logger) {
-	client.Transport = NewRateLimitTransport(client.Transport, logger)
+func SetRateLimitTransport(client *http.Client, logger log.Logger, opts ...RateLimitOption) {
+	client.Transport = NewRateLimitTransport(client.Transport, logger, opts...)
 }
 
 // NewRateLimitTransport returns a new NewRateLimitTransport, who will call the passed
 // http.RoundTripper to process the http.Request
 // Each client (with its own token) should use its own RateLimitTransport
-func NewRateLimitTransport(rt http.RoundTripper, logger log.Logger) *RateLimitTransport {
-	return &RateLimitTransport{
+func NewRateLimitTransport(rt http.RoundTripper, logger log.Logger, opts ...RateLimitOption) *RateLimitTransport {
+	t := &RateLimitTransport{
 		transport:         rt,
 		logger:            logger,
 		defaultAbuseSleep: defaultAbuseRetryAfter,
+		lockedUntil:       make(map[string]time.Time),
+		statuses:          make(map[string]RateStatus),
+		sem:               make(chan struct{}, 1),
+		sleep:             time.Sleep,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// WithConcurrency lets the RateLimitTransport send up to n requests at the
+// same time. A rate limit or abuse hit by any of them stops sending new
+// requests until it expires, the ones in flight are not cancelled
+func WithConcurrency(n int) RateLimitOption {
+	return func(rt *RateLimitTransport) {
+		if n < 1 {
+			n = 1
+		}
+
+		rt.sem = make(chan struct{}, n)
 	}
 }
 
@@ -55,14 +129,11 @@ func NewRateLimitTransport(rt http.RoundTripper, logger log.Logger) *RateLimitTr
 // If the request hitted an API RateLimit or Abuse, it will return an ErrorRateLimit
 // and it no longer process any further Requests until the Limit has been expired.
 func (rt *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	// Make Requests serially
-	rt.Lock()
-	defer rt.Unlock()
+	rt.sem <- struct{}{}
+	defer func() { <-rt.sem }()
 
-	if time.Now().Before(rt.lockedUntil) {
-		rt.logger.Infof("rate limit reached, sleeping until %s", rt.lockedUntil)
-		time.Sleep(rt.lockedUntil.Sub(time.Now()))
-	}
+	resource := requestRateResource(req)
+	rt.wait(resource)
 
 	resp, err := rt.transport.RoundTrip(req)
 	if err != nil {
@@ -73,14 +144,140 @@ func (rt *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, erro
 		return resp, errUnauth
 	}
 
+	if h := resp.Header.Get("X-RateLimit-Resource"); h != "" {
+		resource = h
+	}
+
 	if errRateLimit := checkResponseRateLimit(resp, rt.logger, rt.defaultAbuseSleep); errRateLimit != nil {
-		rt.lockedUntil = errRateLimit.when()
+		rt.Lock()
+		if when := errRateLimit.when(); when.After(rt.lockedUntil[resource]) {
+			rt.lockedUntil[resource] = when
+		}
+
+		rt.setStatus(resource, RateStatus{Known: true, Remaining: 0, Reset: rt.lockedUntil[resource]})
+		rt.Unlock()
 		return resp, errRateLimit
 	}
 
+	if status, ok := asRateStatus(resp); ok {
+		rt.Lock()
+		rt.setStatus(resource, status)
+		if p := rt.pacer(resource); p != nil {
+			p.observe(status)
+		}
+		rt.Unlock()
+	}
+
 	return resp, nil
 }
 
+// wait blocks until a request of the given resource can be sent: the lockout
+// after a rate limit or abuse has expired and, when pacing, its turn has come.
+// The lockout is checked again after sleeping, other requests in flight could
+// extend it
+func (rt *RateLimitTransport) wait(resource string) {
+	paced := rt.pacers == nil
+	for {
+		rt.Lock()
+		now := time.Now()
+		until := rt.lockedUntil[resource]
+		wait := until.Sub(now)
+		locked := wait > 0
+		if !locked && !paced {
+			p := rt.pacer(resource)
+			wait = p.wait(rt.ResourceRateStatus(resource), now)
+			p.last = now.Add(wait)
+			paced = true
+		}
+		rt.Unlock()
+
+		if wait <= 0 {
+			return
+		}
+
+		if locked {
+			rt.logger.Infof("rate limit reached, sleeping until %s", until)
+		} else {
+			rt.logger.Debugf("pacing requests, sleeping %s", wait)
+		}
+
+		rt.sleep(wait)
+	}
+}
+
+// RateStatus returns the rate limit status of the token for the
+// DefaultRateResource, from the headers of the last response of the GraphQL
+// API. A rate or abuse limit hit is reported with 0 remaining requests until
+// it expires
+func (rt *RateLimitTransport) RateStatus() RateStatus {
+	return rt.ResourceRateStatus(DefaultRateResource)
+}
+
+// ResourceRateStatus returns the rate limit status of the token for the given
+// resource, like "core" for the REST API v3
+func (rt *RateLimitTransport) ResourceRateStatus(resource string) RateStatus {
+	rt.statusMu.RLock()
+	defer rt.statusMu.RUnlock()
+
+	return rt.statuses[resource]
+}
+
+func (rt *RateLimitTransport) setStatus(resource string, status RateStatus) {
+	rt.statusMu.Lock()
+	defer rt.statusMu.Unlock()
+
+	rt.statuses[resource] = status
+}
+
+// pacer returns the pacer of the given resource, or nil when not pacing. It
+// must be called with the mutex held
+func (rt *RateLimitTransport) pacer(resource string) *pacer {
+	if rt.pacers == nil {
+		return nil
+	}
+
+	p, ok := rt.pacers[resource]
+	if !ok {
+		p = &pacer{reserve: rt.pacingReserve, cost: 1}
+		rt.pacers[resource] = p
+	}
+
+	return p
+}
+
+// requestRateResource returns the rate limit resource a request is expected
+// to count against before its response tells it: "core" for the REST API v3
+// of github.com or of a GitHub Enterprise Server, DefaultRateResource
+// otherwise
+func requestRateResource(req *http.Request) string {
+	path := req.URL.Path
+	if strings.HasSuffix(path, "/graphql") {
+		return DefaultRateResource
+	}
+
+	if req.URL.Host == "api.github.com" || strings.Contains(path, "/api/v3/") {
+		return coreRateResource
+	}
+
+	return DefaultRateResource
+}
+
+// asRateStatus returns the RateStatus of the 'X-RateLimit-Remaining' and
+// 'X-RateLimit-Reset' headers of the response, ok is false if they are missing
+func asRateStatus(resp *http.Response) (status RateStatus, ok bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateStatus{}, false
+	}
+
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return RateStatus{}, false
+	}
+
+	return RateStatus{Known: true, Remaining: remaining, Reset: time.Unix(reset, 0)}, true
+}
+
 // checkResponseUnauth checks whether the request is authenticated
 func checkResponseUnauth(resp *http.Response) error {
 	if resp.StatusCode == http.StatusUnauthorized {
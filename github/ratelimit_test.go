@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -38,6 +40,13 @@ var testCases = map[string]response{
 		response:   apiResponse{Data: "whatever"},
 		err:        nil,
 	},
+	// regular answer reporting the remaining requests
+	"/ratelimit_remaining": response{
+		statusCode: http.StatusOK,
+		headers:    remainingHeaders(42, time.Hour),
+		response:   apiResponse{Data: "success"},
+		err:        nil,
+	},
 	// proper abuse reset in defaultRateLimitReset
 	"/abuse_sleep": response{
 		statusCode: http.StatusForbidden,
@@ -100,6 +109,15 @@ func rateLimitHeaders(wait time.Duration) func(time.Time) map[string]string {
 	}
 }
 
+func remainingHeaders(remaining int, wait time.Duration) func(time.Time) map[string]string {
+	return func(when time.Time) map[string]string {
+		return map[string]string{
+			"X-RateLimit-Reset":     strconv.FormatInt(when.Add(wait).Unix(), 10),
+			"X-RateLimit-Remaining": strconv.Itoa(remaining),
+		}
+	}
+}
+
 func abuseHeaders(wait time.Duration) func(time.Time) map[string]string {
 	return func(when time.Time) map[string]string {
 		return map[string]string{
@@ -301,6 +319,35 @@ func (s *RateLimitSuite) TestAbuse() {
 	s.Equal("", s.loggerMock.Next())
 }
 
+// TestRateStatus ensures that the status of the last response is reported,
+// and that hitting the RateLimit is reported as no remaining requests
+func (s *RateLimitSuite) TestRateStatus() {
+	now := time.Now()
+	s.False(s.transport.RateStatus().Known)
+
+	_, err := s.transport.RoundTrip(newRequest("/normal"))
+	s.require.NoError(err)
+	s.False(s.transport.RateStatus().Known)
+
+	_, err = s.transport.RoundTrip(newRequest("/ratelimit_remaining"))
+	s.require.NoError(err)
+
+	remaining, ok := s.transport.RateStatus().Headroom(now)
+	s.True(ok)
+	s.Equal(42, remaining)
+
+	_, ok = s.transport.RateStatus().Headroom(now.Add(2 * time.Hour))
+	s.False(ok, "the status should be unknown after the reset")
+
+	_, err = s.transport.RoundTrip(newRequest("/ratelimit_sleep"))
+	s.require.Error(err)
+
+	status := s.transport.RateStatus()
+	s.True(status.Known)
+	s.Equal(0, status.Remaining)
+	s.True(status.Reset.After(now))
+}
+
 // TestUnauthorized ensures that hitting unauthroized requests doesn't cause a wait period
 func (s *RateLimitSuite) TestUnauthorized() {
 	t0 := time.Now()
@@ -418,3 +465,117 @@ func (s *RateLimitSuite) TestRequestBodyIsKept() {
 
 	s.Equal(defaultRequestBody, string(receivedRequestContent))
 }
+
+// delayedResponseMock answers like gitHubTransportResponseMock after a delay,
+// except the rate limit hits, and keeps the maximum of requests in flight
+type delayedResponseMock struct {
+	delay       time.Duration
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (m *delayedResponseMock) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for max := atomic.LoadInt32(&m.maxInFlight); n > max; max = atomic.LoadInt32(&m.maxInFlight) {
+		if atomic.CompareAndSwapInt32(&m.maxInFlight, max, n) {
+			break
+		}
+	}
+
+	if req.URL.String() != "/ratelimit_sleep" {
+		time.Sleep(m.delay)
+	}
+
+	return (&gitHubTransportResponseMock{}).RoundTrip(req)
+}
+
+// roundTripConcurrently sends n requests to the given url at the same time
+func roundTripConcurrently(rt http.RoundTripper, url string, n int) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = rt.RoundTrip(newRequest(url))
+		}(i)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// TestConcurrency ensures that WithConcurrency sends up to the given number of
+// requests at the same time, and that they are serial by default
+func (s *RateLimitSuite) TestConcurrency() {
+	mock := &delayedResponseMock{delay: 200 * time.Millisecond}
+	transport := NewRateLimitTransport(mock, s.loggerMock, WithConcurrency(3))
+
+	t0 := time.Now()
+	for _, err := range roundTripConcurrently(transport, "/normal", 6) {
+		s.require.NoError(err)
+	}
+
+	elapsed := time.Now().Sub(t0)
+	s.True(elapsed >= 400*time.Millisecond, "request took %s, but it should be, at least %s", elapsed, 400*time.Millisecond)
+	s.True(elapsed < 1000*time.Millisecond, "request took %s, but the requests should be concurrent", elapsed)
+	s.Equal(int32(3), mock.maxInFlight)
+
+	mock = &delayedResponseMock{delay: 100 * time.Millisecond}
+	transport = NewRateLimitTransport(mock, s.loggerMock)
+	roundTripConcurrently(transport, "/normal", 3)
+	s.Equal(int32(1), mock.maxInFlight)
+}
+
+// TestConcurrentLockout ensures that a RateLimit hit by a request stops
+// sending the new ones until it expires, without affecting the ones in flight
+func (s *RateLimitSuite) TestConcurrentLockout() {
+	mock := &delayedResponseMock{delay: 300 * time.Millisecond}
+	transport := NewRateLimitTransport(mock, s.loggerMock, WithConcurrency(3))
+
+	t0 := time.Now()
+	inFlight := make(chan error)
+	go func() {
+		_, err := transport.RoundTrip(newRequest("/normal"))
+		inFlight <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	_, err := transport.RoundTrip(newRequest("/ratelimit_sleep"))
+	s.require.Error(err)
+	s.IsType(&ErrRateLimit{}, err)
+	lockedUntil := transport.RateStatus().Reset
+
+	s.require.NoError(<-inFlight)
+	elapsed := time.Now().Sub(t0)
+	s.True(elapsed < defaultRateLimitReset, "request took %s, but the request in flight should not wait", elapsed)
+
+	for _, err := range roundTripConcurrently(transport, "/normal", 2) {
+		s.require.NoError(err)
+	}
+
+	s.True(time.Now().After(lockedUntil), "the requests should wait until %s", lockedUntil)
+	s.Contains(s.loggerMock.Next(), "rate limit reached, sleeping until")
+	s.Contains(s.loggerMock.Next(), "rate limit reached, sleeping until")
+	s.Equal("", s.loggerMock.Next())
+}
+
+// TestConcurrentAbuse ensures that an abuse hit by a request makes all the
+// concurrent ones wait
+func (s *RateLimitSuite) TestConcurrentAbuse() {
+	mock := &delayedResponseMock{delay: 10 * time.Millisecond}
+	transport := NewRateLimitTransport(mock, s.loggerMock, WithConcurrency(3))
+
+	t0 := time.Now()
+	_, err := transport.RoundTrip(newRequest("/abuse_sleep"))
+	s.require.Error(err)
+	s.IsType(&ErrAbuseRateLimit{}, err)
+
+	for _, err := range roundTripConcurrently(transport, "/normal", 3) {
+		s.require.NoError(err)
+	}
+
+	elapsed := time.Now().Sub(t0)
+	s.True(elapsed > defaultAbuseReset, "request took %s, but it should be, at least %s", elapsed, defaultAbuseReset)
+}
@@ -0,0 +1,264 @@
+// Package recorder records the HTTP interactions of the downloaders into
+// cassettes, and replays them to run the downloaders offline, like in tests.
+//
+// The cassettes are JSON files, gzipped when their name ends with ".gz", with
+// the requests identified by their method, path and, for GraphQL, the
+// normalized query and variables, and the responses with their status,
+// headers and body.
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CassetteVersion is the version of the cassette format written by this
+// package
+const CassetteVersion = 1
+
+// volatileHeaders are the response headers that change in every request, they
+// are not recorded to keep the cassettes diffable
+var volatileHeaders = []string{"Date", "Set-Cookie", "X-Github-Request-Id"}
+
+// Cassette is a list of recorded HTTP interactions
+type Cassette struct {
+	Version      int           `json:"version"`
+	RecordedAt   time.Time     `json:"recorded_at"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response, or the error returned instead
+type Interaction struct {
+	Request  Request   `json:"request"`
+	Response *Response `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Request is a recorded request. The GraphQL ones have their query and
+// variables, the rest their raw body, if any
+type Request struct {
+	Method    string          `json:"method"`
+	URL       string          `json:"url"`
+	Query     string          `json:"query,omitempty"`
+	Variables json.RawMessage `json:"variables,omitempty"`
+	Body      string          `json:"body,omitempty"`
+}
+
+// Response is a recorded response. The JSON bodies are kept as they are, to
+// be readable, and the rest as text
+type Response struct {
+	Status   int             `json:"status"`
+	Headers  http.Header     `json:"headers,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"body_text,omitempty"`
+}
+
+// Key returns the string that identifies the request in a cassette: its
+// method, its path and its body, with the GraphQL query and variables
+// normalized
+func (r Request) Key() string {
+	path := r.URL
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+		if j := strings.Index(path, "/"); j >= 0 {
+			path = path[j:]
+		} else {
+			path = "/"
+		}
+	}
+
+	key := r.Method + " " + path
+	if r.Query != "" {
+		key += " " + r.Query
+	}
+
+	if len(r.Variables) > 0 {
+		// the variables read from a cassette file are indented
+		var variables bytes.Buffer
+		if err := json.Compact(&variables, r.Variables); err != nil {
+			variables.Write(r.Variables)
+		}
+
+		key += " " + variables.String()
+	}
+
+	if r.Body != "" {
+		key += " " + r.Body
+	}
+
+	return key
+}
+
+// NewRequest returns the recorded form of the request with the given body
+func NewRequest(req *http.Request, body []byte) (Request, error) {
+	r := Request{Method: req.Method, URL: req.URL.String()}
+	if len(body) == 0 {
+		return r, nil
+	}
+
+	var graphql struct {
+		Query     string      `json:"query"`
+		Variables interface{} `json:"variables"`
+	}
+
+	if err := json.Unmarshal(body, &graphql); err != nil || graphql.Query == "" {
+		r.Body = string(body)
+		return r, nil
+	}
+
+	r.Query = normalizeQuery(graphql.Query)
+	if graphql.Variables != nil {
+		// the map keys are sorted when marshaled
+		variables, err := json.Marshal(graphql.Variables)
+		if err != nil {
+			return Request{}, err
+		}
+
+		r.Variables = variables
+	}
+
+	return r, nil
+}
+
+// normalizeQuery collapses the whitespace of a GraphQL query
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// NewResponse returns the recorded form of the response with the given body
+func NewResponse(resp *http.Response, body []byte) *Response {
+	r := &Response{Status: resp.StatusCode}
+	for name, values := range resp.Header {
+		if !isVolatile(name) {
+			if r.Headers == nil {
+				r.Headers = make(http.Header)
+			}
+
+			r.Headers[name] = values
+		}
+	}
+
+	if json.Valid(body) {
+		var compact bytes.Buffer
+		json.Compact(&compact, body)
+		r.Body = compact.Bytes()
+	} else {
+		r.BodyText = string(body)
+	}
+
+	return r
+}
+
+func isVolatile(header string) bool {
+	for _, h := range volatileHeaders {
+		if http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTTPResponse returns the response to the given request
+func (r *Response) HTTPResponse(req *http.Request) *http.Response {
+	body := []byte(r.BodyText)
+	if len(r.Body) > 0 {
+		// the bodies read from a cassette file are indented
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, r.Body); err != nil {
+			compact.Write(r.Body)
+		}
+
+		body = compact.Bytes()
+	}
+
+	header := make(http.Header)
+	for name, values := range r.Headers {
+		header[name] = values
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// LoadCassette reads the cassette in the given file
+func LoadCassette(path string) (*Cassette, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(path, ".gz") {
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+
+		content, err = ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(content, &c); err != nil {
+		return nil, fmt.Errorf("could not read the cassette %s: %v", path, err)
+	}
+
+	if c.Version > CassetteVersion {
+		return nil, fmt.Errorf("the cassette %s has version %d, the newest supported one is %d", path, c.Version, CassetteVersion)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to the given file
+func (c *Cassette) Save(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	content = append(content, '\n')
+	if strings.HasSuffix(path, ".gz") {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(content); err != nil {
+			return err
+		}
+
+		if err := writer.Close(); err != nil {
+			return err
+		}
+
+		content = buf.Bytes()
+	}
+
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// index returns the positions of the interactions of each request key
+func (c *Cassette) index() map[string][]int {
+	index := make(map[string][]int)
+	for i, interaction := range c.Interactions {
+		key := interaction.Request.Key()
+		index[key] = append(index[key], i)
+	}
+
+	return index
+}
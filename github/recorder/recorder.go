@@ -0,0 +1,161 @@
+package recorder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Mode is what a Recorder does with the requests
+type Mode int
+
+const (
+	// Replay answers the requests with the responses of the cassette,
+	// without sending them
+	Replay Mode = iota
+	// Record sends the requests and records their responses in the cassette
+	Record
+)
+
+// Recorder is a http.RoundTripper that records the interactions of its
+// transport in a cassette, or replays them from it
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	// index and replayed are the interactions of each request key, and how
+	// many of them were replayed: the repeated requests are answered in the
+	// recorded order, and the last answer is repeated after them
+	index    map[string][]int
+	replayed map[string]int
+}
+
+// New returns a Recorder for the cassette in the given file. In Replay mode
+// the cassette is loaded, in Record mode the interactions of the given
+// transport, http.DefaultTransport if nil, are recorded until Save
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+		replayed:  make(map[string]int),
+	}
+
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+
+	if mode == Record {
+		r.cassette = &Cassette{Version: CassetteVersion, RecordedAt: time.Now().UTC()}
+		return r, nil
+	}
+
+	var err error
+	r.cassette, err = LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.index = r.cassette.index()
+	return r, nil
+}
+
+// Client returns an HTTP client that uses the Recorder as its transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	request, err := NewRequest(req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == Record {
+		return r.record(req, request)
+	}
+
+	return r.replay(req, request)
+}
+
+func (r *Recorder) record(req *http.Request, request Request) (*http.Response, error) {
+	interaction := Interaction{Request: request}
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		interaction.Error = err.Error()
+	} else {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		interaction.Response = NewResponse(resp, body)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, err
+}
+
+func (r *Recorder) replay(req *http.Request, request Request) (*http.Response, error) {
+	key := request.Key()
+
+	r.mu.Lock()
+	positions := r.index[key]
+	if len(positions) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("recorder: no recorded interaction for %s", key)
+	}
+
+	n := r.replayed[key]
+	if n >= len(positions) {
+		n = len(positions) - 1
+	}
+
+	r.replayed[key]++
+	interaction := r.cassette.Interactions[positions[n]]
+	r.mu.Unlock()
+
+	if interaction.Response == nil {
+		return nil, errors.New(interaction.Error)
+	}
+
+	return interaction.Response.HTTPResponse(req), nil
+}
+
+// Save writes the recorded cassette to its file, it does nothing in Replay
+// mode
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
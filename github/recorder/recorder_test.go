@@ -0,0 +1,119 @@
+package recorder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a server with a GraphQL endpoint that counts the
+// requests, and a REST one that is not found
+func newServer() *httptest.Server {
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-GitHub-Request-Id", "ABCD:1234")
+		w.Write([]byte(`{"data": {"viewer": {"login": "octocat", "requests": ` + string('0'+rune(requests)) + `}}}`))
+	})
+	mux.HandleFunc("/users/ghost", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`Not Found`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func post(require *require.Assertions, client *http.Client, url, body string) string {
+	resp, err := client.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(err)
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	require.NoError(err)
+	return string(content)
+}
+
+func TestRecordReplay(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "recorder")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"cassette.json", "cassette.json.gz"} {
+		path := filepath.Join(dir, name)
+		server := newServer()
+		defer server.Close()
+
+		r, err := New(path, Record, nil)
+		require.NoError(err)
+
+		query := `{"query":"query($login: String!) { user(login: $login) { name } }","variables":{"login":"octocat","first":10}}`
+		require.Equal(`{"data": {"viewer": {"login": "octocat", "requests": 1}}}`, post(require, r.Client(), server.URL+"/graphql", query))
+		require.Equal(`{"data": {"viewer": {"login": "octocat", "requests": 2}}}`, post(require, r.Client(), server.URL+"/graphql", query))
+
+		resp, err := r.Client().Get(server.URL + "/users/ghost")
+		require.NoError(err)
+		require.Equal(http.StatusNotFound, resp.StatusCode)
+
+		_, err = r.Client().Get("http://127.0.0.1:0/unreachable")
+		require.Error(err)
+
+		require.NoError(r.Save())
+
+		cassette, err := LoadCassette(path)
+		require.NoError(err)
+		require.Equal(CassetteVersion, cassette.Version)
+		require.Len(cassette.Interactions, 4)
+		require.Equal("query($login: String!) { user(login: $login) { name } }", cassette.Interactions[0].Request.Query)
+		require.Equal("4999", cassette.Interactions[0].Response.Headers.Get("X-RateLimit-Remaining"))
+		require.Empty(cassette.Interactions[0].Response.Headers.Get("X-GitHub-Request-Id"))
+		require.Equal("Not Found", cassette.Interactions[2].Response.BodyText)
+		require.NotEmpty(cassette.Interactions[3].Error)
+
+		// the replay does not depend on the host, the whitespace of the query
+		// or the order of the variables
+		r, err = New(path, Replay, nil)
+		require.NoError(err)
+
+		query = `{"variables":{"first":10,"login":"octocat"},"query":"query($login: String!) {\n  user(login: $login) {\n    name\n  }\n}"}`
+		require.Equal(`{"data":{"viewer":{"login":"octocat","requests":1}}}`, post(require, r.Client(), "https://api.github.com/graphql", query))
+		require.Equal(`{"data":{"viewer":{"login":"octocat","requests":2}}}`, post(require, r.Client(), "https://api.github.com/graphql", query))
+		// the last answer is repeated
+		require.Equal(`{"data":{"viewer":{"login":"octocat","requests":2}}}`, post(require, r.Client(), "https://api.github.com/graphql", query))
+
+		resp, err = r.Client().Get("https://api.github.com/users/ghost")
+		require.NoError(err)
+		require.Equal(http.StatusNotFound, resp.StatusCode)
+		require.Equal("404 Not Found", resp.Status)
+
+		_, err = r.Client().Get("http://127.0.0.1:0/unreachable")
+		require.Error(err)
+
+		_, err = r.Client().Get("https://api.github.com/users/octocat")
+		require.Error(err)
+		require.Contains(err.Error(), "recorder: no recorded interaction for GET /users/octocat")
+	}
+}
+
+func TestLoadCassetteVersion(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "recorder")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassette.json")
+	require.NoError((&Cassette{Version: CassetteVersion + 1}).Save(path))
+
+	_, err = New(path, Replay, nil)
+	require.Error(err)
+}
@@ -0,0 +1,173 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+)
+
+// restPerPage is the page size used for the paginated REST API resources
+const restPerPage = 100
+
+// linkNextRegexp matches the next page URL of a Link header
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// RESTClient requests the REST API v3 resources that the GraphQL API does not
+// provide, or does not return with the scopes of the token. Its HTTP client
+// is expected to be the same one used by the Downloader, so the requests are
+// authenticated and go through the RateLimitTransport and retry transport
+type RESTClient struct {
+	client  *http.Client
+	restURL string
+}
+
+// NewRESTClient returns a RESTClient for the given REST API base URL,
+// DefaultRESTURL when empty; a nil httpClient uses http.DefaultClient
+func NewRESTClient(httpClient *http.Client, restURL string) *RESTClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if restURL == "" {
+		restURL = DefaultRESTURL
+	}
+
+	return &RESTClient{
+		client:  httpClient,
+		restURL: strings.TrimSuffix(restURL, "/") + "/",
+	}
+}
+
+// get requests the given URL, decodes the JSON response into v and returns
+// the URL of the next page, empty if it was the last one
+func (c *RESTClient) get(ctx context.Context, u string, v interface{}) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	path := strings.TrimPrefix(u, c.restURL)
+	if resp.StatusCode != http.StatusOK {
+		errorResponse := &apiErrorResponse{}
+		if err := readAPIErrorResponse(resp, errorResponse); err != nil {
+			return "", fmt.Errorf("GET %s: %s", path, resp.Status)
+		}
+
+		return "", fmt.Errorf("GET %s: %s: %s", path, resp.Status, errorResponse.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("GET %s: could not decode the response: %v", path, err)
+	}
+
+	match := linkNextRegexp.FindStringSubmatch(resp.Header.Get("Link"))
+	if match == nil {
+		return "", nil
+	}
+
+	return match[1], nil
+}
+
+// RESTUser contains the fields of
+// https://developer.github.com/v3/users/#get-a-single-user missing in
+// graphql.UserExtendedFields
+type RESTUser struct {
+	// Email is the public email, or any email with the user:email scope
+	Email *string `json:"email"`
+	// PrivateGists is only returned for the authenticated user
+	PrivateGists int `json:"private_gists"`
+	PublicGists  int `json:"public_gists"`
+}
+
+// User returns the REST API fields of the user with the given login
+func (c *RESTClient) User(ctx context.Context, login string) (*RESTUser, error) {
+	var user RESTUser
+	_, err := c.get(ctx, c.restURL+"users/"+url.PathEscape(login), &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// RESTReviewComment contains the fields of
+// https://developer.github.com/v3/pulls/comments/ missing in
+// graphql.PullRequestReviewCommentFields
+type RESTReviewComment struct {
+	ID int `json:"id"`
+	// InReplyToID is 0 if the comment is not a reply
+	InReplyToID int `json:"in_reply_to_id"`
+}
+
+// PullRequestReviewComments returns the REST API fields of all the review
+// comments of the given pull request
+func (c *RESTClient) PullRequestReviewComments(ctx context.Context, owner string, name string, number int) ([]RESTReviewComment, error) {
+	u := fmt.Sprintf("%srepos/%s/%s/pulls/%d/comments?per_page=%d",
+		c.restURL, url.PathEscape(owner), url.PathEscape(name), number, restPerPage)
+
+	comments := []RESTReviewComment{}
+	for u != "" {
+		var page []RESTReviewComment
+		var err error
+		u, err = c.get(ctx, u, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, page...)
+	}
+
+	return comments, nil
+}
+
+// userExtended returns the UserExtended with the given GraphQL fields, and
+// the REST API ones with WithRESTFallback
+func (d Downloader) userExtended(ctx context.Context, fields graphql.UserExtendedFields) (*graphql.UserExtended, error) {
+	user := &graphql.UserExtended{UserExtendedFields: fields}
+	if d.rest == nil {
+		return user, nil
+	}
+
+	restUser, err := d.rest.User(ctx, fields.Login)
+	if err != nil {
+		return nil, fmt.Errorf("REST query for user %v failed: %v", fields.Login, err)
+	}
+
+	if restUser.Email != nil {
+		user.Email = *restUser.Email
+	}
+
+	user.PrivateGists = restUser.PrivateGists
+	user.PublicGists = restUser.PublicGists
+	return user, nil
+}
+
+// reviewCommentsInReplyTo returns the comment replied by each review comment
+// of the given pull request, indexed by the comment ID
+func (d Downloader) reviewCommentsInReplyTo(ctx context.Context, owner string, name string, number int) (map[int]int, error) {
+	comments, err := d.rest.PullRequestReviewComments(ctx, owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("REST query for review comments of PR #%v failed: %v", number, err)
+	}
+
+	inReplyTo := make(map[int]int, len(comments))
+	for _, c := range comments {
+		inReplyTo[c.ID] = c.InReplyToID
+	}
+
+	return inReplyTo, nil
+}
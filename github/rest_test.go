@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newRESTServer returns a stand-in REST API v3 with a user and the two pages
+// of review comments of a pull request
+func newRESTServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/meta", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"installed_version":"3.9.0"}`))
+	})
+	mux.HandleFunc("/api/v3/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"octocat","email":"octocat@github.com","public_gists":8,"private_gists":81}`))
+	})
+	mux.HandleFunc("/api/v3/users/ghost", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	})
+	mux.HandleFunc("/api/v3/repos/src-d/go-git/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"id":12,"in_reply_to_id":10}]`))
+			return
+		}
+
+		next := fmt.Sprintf("http://%s%s?per_page=100&page=2", r.Host, r.URL.Path)
+		w.Header().Set("Link", `<`+next+`>; rel="next", <`+next+`>; rel="last"`)
+		w.Write([]byte(`[{"id":10},{"id":11,"in_reply_to_id":10}]`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestRESTClient(t *testing.T) {
+	require := require.New(t)
+
+	server := newRESTServer()
+	defer server.Close()
+
+	c := NewRESTClient(server.Client(), server.URL+"/api/v3")
+
+	user, err := c.User(context.TODO(), "octocat")
+	require.NoError(err)
+	require.Equal("octocat@github.com", *user.Email)
+	require.Equal(8, user.PublicGists)
+
+	_, err = c.User(context.TODO(), "ghost")
+	require.EqualError(err, "GET users/ghost: 404 Not Found: Not Found")
+
+	comments, err := c.PullRequestReviewComments(context.TODO(), "src-d", "go-git", 1)
+	require.NoError(err)
+	require.Equal([]RESTReviewComment{{ID: 10}, {ID: 11, InReplyToID: 10}, {ID: 12, InReplyToID: 10}}, comments)
+}
+
+func TestRESTFallback(t *testing.T) {
+	require := require.New(t)
+
+	server := newRESTServer()
+	defer server.Close()
+
+	d, err := NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/api/graphql", server.URL+"/api/v3/"),
+		WithRESTFallback())
+	require.NoError(err)
+
+	user, err := d.userExtended(context.TODO(), graphql.UserExtendedFields{Login: "octocat"})
+	require.NoError(err)
+	require.Equal("octocat", user.Login)
+	require.Equal("octocat@github.com", user.Email)
+	require.Equal(81, user.PrivateGists)
+	require.Equal(8, user.PublicGists)
+
+	inReplyTo, err := d.reviewCommentsInReplyTo(context.TODO(), "src-d", "go-git", 1)
+	require.NoError(err)
+	require.Equal(map[int]int{10: 0, 11: 10, 12: 10}, inReplyTo)
+
+	// without the option the fields are left empty, no request is done
+	d, err = NewDownloader(server.Client(), &testutils.Memory{},
+		WithEndpoints(server.URL+"/api/graphql", server.URL+"/api/v3/"))
+	require.NoError(err)
+
+	user, err = d.userExtended(context.TODO(), graphql.UserExtendedFields{Login: "ghost"})
+	require.NoError(err)
+	require.Equal("", user.Email)
+}
@@ -6,35 +6,122 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/src-d/metadata-retrieval/utils/ctxlog"
+
 	"github.com/cenkalti/backoff"
-	"gopkg.in/src-d/go-log.v1"
 )
 
 // SetRetryTransport wraps the passed client.Transport with a RetryTransport
+// with the DefaultRetryPolicy
 func SetRetryTransport(client *http.Client) {
-	client.Transport = &retryTransport{client.Transport}
+	client.Transport = NewRetryTransport(client.Transport, DefaultRetryPolicy())
+}
+
+// RetryPolicy configures which failed requests RetryTransport retries, and how
+// long it waits before each attempt
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries of a request
+	MaxRetries int
+	// InitialInterval is the wait before the first retry, it is multiplied by
+	// Multiplier for each next one, up to MaxInterval
+	InitialInterval time.Duration
+	Multiplier      float64
+	MaxInterval     time.Duration
+	// Jitter randomizes each wait by up to the given fraction of it, so the
+	// requests that failed together are not retried together
+	Jitter float64
+	// MaxElapsedTime bounds the time spent retrying a request, 0 means no bound
+	MaxElapsedTime time.Duration
+	// Statuses overrides whether the responses with each status code are
+	// retried, by default only the 5xx ones (server errors) are
+	Statuses map[int]bool
+	// OnRetry, when set, is called before waiting for each retry, with the
+	// number of the attempt that failed, its error and the wait
+	OnRetry func(req *http.Request, attempt int, err error, wait time.Duration)
+}
+
+// DefaultRetryPolicy returns the policy of SetRetryTransport: up to 10 retries
+// during 15 minutes, waiting kind of 10ms, 60ms, 360ms, 2.2s, 10s, 10s ...
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:      10,
+		InitialInterval: 10 * time.Millisecond,
+		Multiplier:      6,
+		MaxInterval:     10 * time.Second,
+		Jitter:          0.5,
+		MaxElapsedTime:  15 * time.Minute,
+	}
+}
+
+// retryStatus returns whether the responses with the given status code are
+// retried
+func (p RetryPolicy) retryStatus(statusCode int) bool {
+	if retry, ok := p.Statuses[statusCode]; ok {
+		return retry
+	}
+
+	return statusCode >= 500
+}
+
+// backOff returns the exponential backoff of the policy, and the same one
+// bounded by the max retries and stopped when the context is done
+func (p RetryPolicy) backOff(ctx context.Context) (*backoff.ExponentialBackOff, backoff.BackOff) {
+	exponential := backoff.NewExponentialBackOff()
+	exponential.InitialInterval = p.InitialInterval
+	exponential.Multiplier = p.Multiplier
+	exponential.MaxInterval = p.MaxInterval
+	exponential.RandomizationFactor = p.Jitter
+	exponential.MaxElapsedTime = p.MaxElapsedTime
+	exponential.Reset()
+
+	return exponential, backoff.WithContext(backoff.WithMaxRetries(exponential, uint64(p.MaxRetries)), ctx)
 }
 
-// retryTransport retries a http.Request if it fails when processing, or if
-// its http.Response has StatusCode in 5xx range (server errors)
-type retryTransport struct {
-	T http.RoundTripper
+// RetryTransport retries a http.Request if it fails when processing, if its
+// http.Response has a StatusCode retried by its RetryPolicy, the 5xx range
+// (server errors) by default, or if it is a GraphQL response with transient
+// errors, like timeouts or exceeded resource limits. It stops retrying when
+// the request context is done, even while waiting
+type RetryTransport struct {
+	T      http.RoundTripper
+	policy RetryPolicy
 }
 
-func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+// NewRetryTransport returns a RetryTransport that retries the requests of the
+// given transport following the given policy
+func NewRetryTransport(rt http.RoundTripper, policy RetryPolicy) *RetryTransport {
+	return &RetryTransport{T: rt, policy: policy}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	var response *http.Response
-	requestBodyContent, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not backup the response before sending it through the retry loop: %s", err)
+	var requestBodyContent []byte
+	// the requests without body, like the REST API GET ones, have a nil Body
+	if req.Body != nil {
+		var err error
+		requestBodyContent, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not backup the response before sending it through the retry loop: %s", err)
+		}
 	}
 
+	ctx := req.Context()
+	logger := ctxlog.Get(ctx)
+
+	var attempt int
 	do := func() error {
+		attempt++
+
 		var err error
-		req.Body = ioutil.NopCloser(bytes.NewReader(requestBodyContent))
+		if req.Body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(requestBodyContent))
+		}
+
 		response, err = t.T.RoundTrip(req)
-		if err == context.Canceled {
+		if err != nil && ctx.Err() != nil {
 			return backoff.Permanent(err)
 		}
 
@@ -46,51 +133,95 @@ func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			return err
 		}
 
-		if response.StatusCode >= 500 {
+		if t.policy.retryStatus(response.StatusCode) {
 			responseBody, err := readResponseAndRestore(response)
 			if err != nil {
 				return err
 			}
 
-			return fmt.Errorf("%s: %s", response.Status, responseBody)
+			err = &errStatus{response.StatusCode, response.Status, responseBody}
+			if noTimeoutRetries(req) && isTimeout(err) {
+				return backoff.Permanent(err)
+			}
+
+			return err
+		}
+
+		if response.StatusCode == http.StatusOK && req.Method == http.MethodPost {
+			errs, err := readGraphQLErrors(response)
+			if err != nil {
+				return err
+			}
+
+			if err := asGraphQLError(errs); isTransient(err) {
+				if noTimeoutRetries(req) && isTimeout(err) {
+					return backoff.Permanent(err)
+				}
+
+				return err
+			}
 		}
 
 		return nil
 	}
 
-	return response, retry(do)
+	notify := func(reason error, wait time.Duration) {
+		logger.Warningf("retrying in %s; got %s", wait, reason)
+		if t.policy.OnRetry != nil {
+			t.policy.OnRetry(req, attempt, reason, wait)
+		}
+	}
+
+	exponential, b := t.policy.backOff(ctx)
+	err := backoff.RetryNotify(do, b, notify)
+	if err != nil {
+		elapsed := exponential.GetElapsedTime().Seconds()
+		logger.Errorf(err, "retry was aborted after %d attempts and %fs", attempt, elapsed)
+	}
+
+	return response, err
 }
 
-const (
-	maxRetries      = 10
-	initialInterval = 10 * time.Millisecond
-	maxInterval     = 10 * time.Second
-	multiplier      = 6 // this multiplier, with these defaults will cause kind of: 10ms, 60ms, 360ms, 2.2s, 10s, 10s ...
-)
+// errStatus is returned when a http.Response has a StatusCode retried by the
+// RetryPolicy
+type errStatus struct {
+	statusCode int
+	status     string
+	body       []byte
+}
 
-// retry retries the passed operation until it returns no err or a permanent one
-// or until it reaches the passed max number of attempts.
-// If returns either the first backoff.PermanentError it gets, or the last obtained error
-// when reaching the max number of attempts
-func retry(operation backoff.Operation) error {
-	retryCount := 0
+func (e *errStatus) Error() string {
+	return fmt.Sprintf("%s: %s", e.status, e.body)
+}
 
-	onError := func(reason error, nextSlep time.Duration) {
-		retryCount++
-		log.Warningf("retrying in %s; got %s", nextSlep, reason)
+// isTimeout returns whether the error is a timeout of the request, either from
+// the GitHub gateway or from the GraphQL query, that may succeed with a
+// smaller page
+func isTimeout(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
 	}
 
-	backoffPolicy := backoff.NewExponentialBackOff()
-	backoffPolicy.InitialInterval = initialInterval
-	backoffPolicy.MaxInterval = maxInterval
-	backoffPolicy.Multiplier = multiplier
+	switch e := err.(type) {
+	case *errStatus:
+		return e.statusCode == http.StatusBadGateway || e.statusCode == http.StatusGatewayTimeout
+	case *ErrTimeout:
+		return true
+	default:
+		return false
+	}
+}
 
-	err := backoff.RetryNotify(operation, backoff.WithMaxRetries(backoffPolicy, maxRetries), onError)
+type noTimeoutRetriesKey struct{}
 
-	if err != nil {
-		elapsed := backoffPolicy.GetElapsedTime().Seconds()
-		log.Errorf(err, "retry was aborted after %d attempts and %fs", retryCount, elapsed)
-	}
+// withoutTimeoutRetries returns a context where RetryTransport does not retry
+// the requests that time out, for the queries whose page size is reduced
+// instead
+func withoutTimeoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTimeoutRetriesKey{}, true)
+}
 
-	return err
+func noTimeoutRetries(req *http.Request) bool {
+	noRetries, _ := req.Context().Value(noTimeoutRetriesKey{}).(bool)
+	return noRetries
 }
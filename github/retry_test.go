@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetryPolicy retries up to 3 times without waiting much
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:      3,
+		InitialInterval: time.Millisecond,
+		Multiplier:      2,
+		MaxInterval:     time.Millisecond,
+	}
+}
+
+// statusTransport answers every request with the given status code, and
+// counts them
+func statusTransport(statusCode int, requests *int) http.RoundTripper {
+	return RoundTripFunc(func(req *http.Request) *http.Response {
+		*requests++
+		resp := newGraphQLResponse(`{"message":"error"}`)
+		resp.StatusCode = statusCode
+		resp.Status = http.StatusText(statusCode)
+		return resp
+	})
+}
+
+func TestRetryStatuses(t *testing.T) {
+	require := require.New(t)
+
+	policy := fastRetryPolicy()
+	policy.Statuses = map[int]bool{http.StatusServiceUnavailable: false, http.StatusTooManyRequests: true}
+
+	var requests int
+	_, err := NewRetryTransport(statusTransport(http.StatusInternalServerError, &requests), policy).RoundTrip(newRequest("/"))
+	require.Error(err)
+	require.Equal(4, requests)
+
+	requests = 0
+	_, err = NewRetryTransport(statusTransport(http.StatusTooManyRequests, &requests), policy).RoundTrip(newRequest("/"))
+	require.Error(err)
+	require.Equal(4, requests)
+
+	requests = 0
+	resp, err := NewRetryTransport(statusTransport(http.StatusServiceUnavailable, &requests), policy).RoundTrip(newRequest("/"))
+	require.NoError(err)
+	require.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(1, requests)
+
+	requests = 0
+	_, err = NewRetryTransport(statusTransport(http.StatusNotFound, &requests), policy).RoundTrip(newRequest("/"))
+	require.NoError(err)
+	require.Equal(1, requests)
+}
+
+func TestRetryOnRetry(t *testing.T) {
+	require := require.New(t)
+
+	var attempts []int
+	policy := fastRetryPolicy()
+	policy.OnRetry = func(req *http.Request, attempt int, err error, wait time.Duration) {
+		require.Equal("/retried", req.URL.String())
+		require.EqualError(err, `Internal Server Error: {"message":"error"}`)
+		require.Equal(time.Millisecond, wait)
+		attempts = append(attempts, attempt)
+	}
+
+	var requests int
+	_, err := NewRetryTransport(statusTransport(http.StatusInternalServerError, &requests), policy).RoundTrip(newRequest("/retried"))
+	require.Error(err)
+	require.Equal([]int{1, 2, 3}, attempts)
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	require := require.New(t)
+
+	policy := fastRetryPolicy()
+	policy.InitialInterval = time.Hour
+	policy.MaxInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	var requests int
+	t0 := time.Now()
+	_, err := NewRetryTransport(statusTransport(http.StatusInternalServerError, &requests), policy).RoundTrip(newRequest("/").WithContext(ctx))
+	require.Error(err)
+	require.Equal(1, requests)
+	require.True(time.Since(t0) < time.Second, "the wait should stop when the context is canceled")
+
+	// the waits beyond the deadline are not even started
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	requests = 0
+	t0 = time.Now()
+	_, err = NewRetryTransport(statusTransport(http.StatusInternalServerError, &requests), policy).RoundTrip(newRequest("/").WithContext(ctx))
+	require.Error(err)
+	require.Equal(1, requests)
+	require.True(time.Since(t0) < time.Second, "the retry should not wait beyond the deadline")
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	require := require.New(t)
+
+	policy := fastRetryPolicy()
+	policy.MaxRetries = 1000
+	policy.InitialInterval = 20 * time.Millisecond
+	policy.MaxInterval = 20 * time.Millisecond
+	policy.MaxElapsedTime = 100 * time.Millisecond
+
+	var requests int
+	t0 := time.Now()
+	_, err := NewRetryTransport(statusTransport(http.StatusBadGateway, &requests), policy).RoundTrip(newRequest("/"))
+	require.Error(err)
+	require.True(requests > 1 && requests < 10, "%d requests, they should be bounded by the elapsed time", requests)
+	require.True(time.Since(t0) < time.Second)
+}
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/src-d/metadata-retrieval/utils/ctxlog"
+)
+
+// SkippedItem is an item that could not be downloaded because of a FORBIDDEN
+// or NOT_FOUND error, see Downloader.Skipped
+type SkippedItem struct {
+	// Type is the GraphQL error type, like FORBIDDEN or NOT_FOUND
+	Type string
+	// Path is the field of the response, or the connection, that is missing
+	Path    string
+	Message string
+}
+
+// skippedItems records the items that DownloadRepository and
+// DownloadOrganization could not download because of FORBIDDEN or NOT_FOUND
+// errors, to download the rest and report them at the end
+type skippedItems struct {
+	items []SkippedItem
+}
+
+// skippedLog keeps the items skipped by the last download of a Downloader
+type skippedLog struct {
+	sync.Mutex
+	items []SkippedItem
+}
+
+// Skipped returns the items skipped by the last call to DownloadRepository or
+// DownloadOrganization because of FORBIDDEN or NOT_FOUND errors, the rest of
+// the items were downloaded. It is empty if none was skipped
+func (d *Downloader) Skipped() []SkippedItem {
+	if d.skippedLog == nil {
+		return nil
+	}
+
+	d.skippedLog.Lock()
+	defer d.skippedLog.Unlock()
+
+	return append([]SkippedItem(nil), d.skippedLog.items...)
+}
+
+// reportSkipped logs the summary of the items skipped by the download, and
+// keeps them to be returned by Skipped
+func (d Downloader) reportSkipped(ctx context.Context) {
+	if len(d.skipped.items) > 0 {
+		ctxlog.Get(ctx).Warningf("%d items skipped: %s", len(d.skipped.items), d.skipped.summary())
+	}
+
+	if d.skippedLog != nil {
+		d.skippedLog.Lock()
+		d.skippedLog.items = d.skipped.items
+		d.skippedLog.Unlock()
+	}
+}
+
+// recordFields records the fields missing in a partial response
+func (s *skippedItems) recordFields(ctx context.Context, errs GraphQLErrors) {
+	for _, err := range errs {
+		s.record(ctx, SkippedItem{Type: err.Type, Path: err.PathString(), Message: err.Message})
+	}
+}
+
+// recordConnection records the connection of the item with the given ID
+// whose download was interrupted
+func (s *skippedItems) recordConnection(ctx context.Context, name string, id interface{}, err graphqlErrorer) {
+	errs := err.graphqlErrors()
+	s.record(ctx, SkippedItem{
+		Type:    errs[0].Type,
+		Path:    fmt.Sprintf("node(%v).%s", id, name),
+		Message: errs.Error(),
+	})
+}
+
+func (s *skippedItems) record(ctx context.Context, item SkippedItem) {
+	ctxlog.Get(ctx).Warningf("skipping %s, %s: %s", item.Path, item.Type, item.Message)
+	s.items = append(s.items, item)
+}
+
+// summary returns the number of skipped items of each type,
+// e.g. "2 FORBIDDEN, 1 NOT_FOUND"
+func (s *skippedItems) summary() string {
+	counts := make(map[string]int)
+	for _, item := range s.items {
+		counts[item.Type]++
+	}
+
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+
+	sort.Strings(types)
+	for i, t := range types {
+		types[i] = fmt.Sprintf("%d %s", counts[t], t)
+	}
+
+	return strings.Join(types, ", ")
+}
+
+// skipConnection records the connection of the item with the given ID and
+// returns whether its download can be interrupted, keeping the rest: the
+// connections are skipped in DownloadRepository and DownloadOrganization when
+// their item is forbidden or gone
+func (d Downloader) skipConnection(ctx context.Context, connection string, id interface{}, err error) bool {
+	if d.skipped == nil || !isSkippable(err) {
+		return false
+	}
+
+	d.skipped.recordConnection(ctx, connection, id, err.(graphqlErrorer))
+	return true
+}
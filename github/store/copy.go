@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// NewBufferedDB returns a DB that buffers the rows saved in each versioned
+// table, instead of inserting them one by one. Every flushRows rows of a
+// table, and on Commit, they are loaded with COPY into a temporary staging
+// table and merged into the versioned table, appending the version to the
+// rows that already exist. The rows saved more than once between two merges
+// are merged once, and the version is not appended again to the rows that
+// already have it.
+//
+// The versioned tables are only written by the merges, the rows saved since
+// the last one are lost if the connection fails before Commit
+func NewBufferedDB(db *sql.DB, flushRows int) *DB {
+	return &DB{DB: db, flushRows: flushRows}
+}
+
+// copyTable are the rows saved in a versioned table since its last merge
+type copyTable struct {
+	cols []string
+	rows [][]interface{}
+	// staged is set once the staging table is created in the transaction
+	staged bool
+}
+
+// buffer keeps the row until the rows of the table are flushed
+func (s *DB) buffer(ctx context.Context, table, cols string, sum256 string, row []interface{}) error {
+	t, ok := s.buffered[table]
+	if !ok {
+		t = &copyTable{cols: strings.Split(cols, ", ")}
+		s.buffered[table] = t
+	}
+
+	values := []interface{}{sum256}
+	for _, v := range row {
+		if a, ok := v.([]string); ok {
+			v = pq.Array(a)
+		}
+
+		values = append(values, v)
+	}
+
+	t.rows = append(t.rows, values)
+	if len(t.rows) < s.flushRows {
+		return nil
+	}
+
+	return s.flush(ctx, table, t)
+}
+
+// flushAll flushes the rows buffered in all the tables
+func (s *DB) flushAll(ctx context.Context) error {
+	for _, table := range tables {
+		t, ok := s.buffered[table]
+		if !ok || len(t.rows) == 0 {
+			continue
+		}
+
+		if err := s.flush(ctx, table, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flush copies the buffered rows of the table into its staging table, and
+// merges them into the table
+func (s *DB) flush(ctx context.Context, table string, t *copyTable) error {
+	staging := strings.Replace(table, "_versioned", "_staging", 1)
+	cols := strings.Join(t.cols, ", ")
+
+	if !t.staged {
+		// the staging table has the columns of the table without versions
+		// and without constraints, it is dropped with the transaction
+		_, err := s.tx.ExecContext(ctx, fmt.Sprintf(`CREATE TEMPORARY TABLE %s
+			ON COMMIT DROP
+			AS SELECT sum256, %s FROM %s
+			WITH NO DATA`, staging, cols, table))
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", staging, err)
+		}
+
+		t.staged = true
+	}
+
+	if err := s.copyIn(ctx, staging, t); err != nil {
+		return fmt.Errorf("failed to copy into %s: %v", staging, err)
+	}
+
+	// the rows with the same hash are the same, they are merged once
+	_, err := s.tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %[1]s
+		(sum256, versions, %[3]s)
+		SELECT DISTINCT ON (sum256) sum256, ARRAY[$1::integer], %[3]s
+		FROM %[2]s
+		ON CONFLICT (sum256)
+		DO UPDATE
+		SET versions = array_append(%[1]s.versions, $1::integer)
+		WHERE $1::integer <> ALL(%[1]s.versions)`, table, staging, cols), s.v)
+	if err != nil {
+		return fmt.Errorf("failed to merge %s: %v", staging, err)
+	}
+
+	_, err = s.tx.ExecContext(ctx, fmt.Sprintf(`TRUNCATE %s`, staging))
+	if err != nil {
+		return fmt.Errorf("failed to truncate %s: %v", staging, err)
+	}
+
+	t.rows = t.rows[:0]
+	return nil
+}
+
+// copyIn loads the buffered rows of the table into the staging table
+func (s *DB) copyIn(ctx context.Context, staging string, t *copyTable) error {
+	stmt, err := s.tx.PrepareContext(ctx, pq.CopyIn(staging, append([]string{"sum256"}, t.cols...)...))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range t.rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	// the COPY ends with an Exec without arguments
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+
+	return stmt.Close()
+}
@@ -0,0 +1,482 @@
+package store
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+)
+
+// JSONLKinds are the kinds of items saved by JSONL, each transaction in the
+// segment file <kind>.<version>.<seq>.jsonl, or .jsonl.gz when it is compressed
+var JSONLKinds = []string{
+	"organizations",
+	"users",
+	"contributors",
+	"repositories",
+	"issues",
+	"issue_comments",
+	"pull_requests",
+	"pull_request_comments",
+	"pull_request_reviews",
+	"pull_request_review_comments",
+	"commit_comments",
+	"projects",
+	"project_columns",
+	"project_cards",
+	"projects_v2",
+	"project_v2_items",
+}
+
+// JSONL saves the items as JSON Lines, with segment files for each kind of
+// item in a directory. Each line is a JSON object with the version, the item
+// under a key like "issue" or "comment", and the arguments it was saved with,
+// like the repository owner and name or the number of its issue. The items
+// saved again, like when a repository is downloaded again, are written again
+// in a later segment.
+//
+// The items are written to temporary files, that Commit renames to a new
+// segment for each kind, with the sequence number of the transaction, and
+// Rollback removes. When a rename fails, the segments renamed before it are
+// removed and Commit returns the error, so the transaction is discarded as a
+// whole; only a crash in the middle of Commit may leave some of its segments.
+// A directory is written by a single JSONL. The segments keep the items of all
+// the versions, SetActiveVersion and Cleanup do nothing
+type JSONL struct {
+	dir      string
+	compress bool
+
+	mu  sync.Mutex
+	v   int
+	tx  bool
+	tmp map[string]*jsonlFile
+}
+
+// NewJSONL returns a JSONL that writes the files in dir, compressed with
+// gzip when compress is set
+func NewJSONL(dir string, compress bool) *JSONL {
+	return &JSONL{dir: dir, compress: compress}
+}
+
+// ext returns the extension of the segment files
+func (s *JSONL) ext() string {
+	if s.compress {
+		return ".jsonl.gz"
+	}
+
+	return ".jsonl"
+}
+
+// Path returns the path of the segment file with the items of the given kind
+// and version saved by the transaction with the given sequence number
+func (s *JSONL) Path(kind string, version int, seq int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%d.%d%s", kind, version, seq, s.ext()))
+}
+
+// jsonlSegment is a segment file of a kind
+type jsonlSegment struct {
+	path    string
+	kind    string
+	version int
+	seq     int
+}
+
+// segments returns the segment files in the directory, in the order they were
+// committed
+func (s *JSONL) segments() ([]jsonlSegment, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var segments []jsonlSegment
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, s.ext()) {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimSuffix(name, s.ext()), ".")
+		if len(parts) != 3 {
+			continue
+		}
+
+		version, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		seq, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, jsonlSegment{
+			path: filepath.Join(s.dir, name), kind: parts[0], version: version, seq: seq,
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].seq < segments[j].seq })
+	return segments, nil
+}
+
+// Segments returns the paths of the segment files with the items of the given
+// kind, in the order they were committed
+func (s *JSONL) Segments(kind string) ([]string, error) {
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, segment := range segments {
+		if segment.kind == kind {
+			paths = append(paths, segment.path)
+		}
+	}
+
+	return paths, nil
+}
+
+// jsonlFile is the temporary file with the items of a kind saved since Begin
+type jsonlFile struct {
+	f  *os.File
+	w  *bufio.Writer
+	gz *gzip.Writer
+}
+
+func (f *jsonlFile) write(line []byte) error {
+	var w io.Writer = f.w
+	if f.gz != nil {
+		w = f.gz
+	}
+
+	_, err := w.Write(line)
+	return err
+}
+
+// flush writes the buffered items to the file
+func (f *jsonlFile) flush() error {
+	if f.gz != nil {
+		if err := f.gz.Close(); err != nil {
+			return err
+		}
+	}
+
+	return f.w.Flush()
+}
+
+func (f *jsonlFile) close() error {
+	if err := f.flush(); err != nil {
+		f.f.Close()
+		return err
+	}
+
+	return f.f.Close()
+}
+
+func (s *JSONL) Begin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx {
+		return fmt.Errorf("a transaction is already in progress")
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	s.tx = true
+	s.tmp = make(map[string]*jsonlFile)
+	return nil
+}
+
+func (s *JSONL) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.tx {
+		return fmt.Errorf("there is no transaction in progress")
+	}
+
+	seq, err := s.nextSeq()
+
+	var committed []string
+	for kind, tmp := range s.tmp {
+		if err == nil {
+			path := s.Path(kind, s.v, seq)
+			err = commitSegment(tmp, path)
+			if err == nil {
+				committed = append(committed, path)
+			} else {
+				err = fmt.Errorf("could not commit the %s: %v", kind, err)
+			}
+		} else {
+			tmp.close()
+		}
+
+		os.Remove(tmp.f.Name())
+	}
+
+	// the transaction is discarded as a whole
+	if err != nil {
+		for _, path := range committed {
+			os.Remove(path)
+		}
+	}
+
+	s.tx = false
+	s.tmp = nil
+	return err
+}
+
+// nextSeq returns the sequence number of the segments of a new transaction
+func (s *JSONL) nextSeq() (int, error) {
+	segments, err := s.segments()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(segments) == 0 {
+		return 1, nil
+	}
+
+	return segments[len(segments)-1].seq + 1, nil
+}
+
+// commitSegment writes the items of the temporary file to the disk, and
+// renames it to the segment path
+func commitSegment(tmp *jsonlFile, path string) error {
+	if err := tmp.flush(); err != nil {
+		tmp.f.Close()
+		return err
+	}
+
+	if err := tmp.f.Sync(); err != nil {
+		tmp.f.Close()
+		return err
+	}
+
+	if err := tmp.f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.f.Name(), path)
+}
+
+func (s *JSONL) Rollback() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.tx {
+		return fmt.Errorf("there is no transaction in progress")
+	}
+
+	for _, tmp := range s.tmp {
+		tmp.close()
+		os.Remove(tmp.f.Name())
+	}
+
+	s.tx = false
+	s.tmp = nil
+	return nil
+}
+
+func (s *JSONL) Version(v int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.v = v
+}
+
+// SetActiveVersion does nothing, the items of each version are distinguished
+// by their version field
+func (s *JSONL) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+// Cleanup does nothing, the segments keep the items of all the versions
+func (s *JSONL) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
+
+// save writes a line with the version and the given fields to the temporary
+// file of the kind
+func (s *JSONL) save(kind string, fields map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.tx {
+		return fmt.Errorf("could not save the %s: Begin was not called", kind)
+	}
+
+	fields["version"] = s.v
+	line, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("could not serialize the %s: %v", kind, err)
+	}
+
+	tmp, ok := s.tmp[kind]
+	if !ok {
+		f, err := ioutil.TempFile(s.dir, "."+kind+s.ext()+".*.tx")
+		if err != nil {
+			return err
+		}
+
+		tmp = &jsonlFile{f: f, w: bufio.NewWriter(f)}
+		if s.compress {
+			tmp.gz = gzip.NewWriter(tmp.w)
+		}
+
+		s.tmp[kind] = tmp
+	}
+
+	if err := tmp.write(append(line, '\n')); err != nil {
+		return fmt.Errorf("could not write the %s: %v", kind, err)
+	}
+
+	return nil
+}
+
+func (s *JSONL) SaveOrganization(ctx context.Context, organization *graphql.Organization) error {
+	return s.save("organizations", map[string]interface{}{
+		"organization": organization,
+	})
+}
+
+func (s *JSONL) SaveUser(ctx context.Context, orgID int, orgLogin string, user *graphql.UserExtended) error {
+	return s.save("users", map[string]interface{}{
+		"organization_id":    orgID,
+		"organization_login": orgLogin,
+		"user":               user,
+	})
+}
+
+func (s *JSONL) SaveContributor(ctx context.Context, user *graphql.UserExtended) error {
+	return s.save("contributors", map[string]interface{}{
+		"user": user,
+	})
+}
+
+func (s *JSONL) SaveRepository(ctx context.Context, repository *graphql.RepositoryFields, topics []string) error {
+	return s.save("repositories", map[string]interface{}{
+		"repository": repository,
+		"topics":     topics,
+	})
+}
+
+func (s *JSONL) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *graphql.Issue, assignees []string, labels []string) error {
+	return s.save("issues", map[string]interface{}{
+		"repository_owner": repositoryOwner,
+		"repository_name":  repositoryName,
+		"issue":            issue,
+		"assignees":        assignees,
+		"labels":           labels,
+	})
+}
+
+func (s *JSONL) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *graphql.IssueComment) error {
+	return s.save("issue_comments", map[string]interface{}{
+		"repository_owner": repositoryOwner,
+		"repository_name":  repositoryName,
+		"issue_number":     issueNumber,
+		"comment":          comment,
+	})
+}
+
+func (s *JSONL) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *graphql.PullRequest, assignees []string, labels []string) error {
+	return s.save("pull_requests", map[string]interface{}{
+		"repository_owner": repositoryOwner,
+		"repository_name":  repositoryName,
+		"pull_request":     pr,
+		"assignees":        assignees,
+		"labels":           labels,
+	})
+}
+
+func (s *JSONL) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *graphql.IssueComment) error {
+	return s.save("pull_request_comments", map[string]interface{}{
+		"repository_owner":    repositoryOwner,
+		"repository_name":     repositoryName,
+		"pull_request_number": pullRequestNumber,
+		"comment":             comment,
+	})
+}
+
+func (s *JSONL) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) error {
+	return s.save("pull_request_reviews", map[string]interface{}{
+		"repository_owner":    repositoryOwner,
+		"repository_name":     repositoryName,
+		"pull_request_number": pullRequestNumber,
+		"review":              review,
+	})
+}
+
+func (s *JSONL) SavePullRequestReviewComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, pullRequestReviewID int, comment *graphql.PullRequestReviewComment) error {
+	return s.save("pull_request_review_comments", map[string]interface{}{
+		"repository_owner":       repositoryOwner,
+		"repository_name":        repositoryName,
+		"pull_request_number":    pullRequestNumber,
+		"pull_request_review_id": pullRequestReviewID,
+		"comment":                comment,
+	})
+}
+
+func (s *JSONL) SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error {
+	return s.save("commit_comments", map[string]interface{}{
+		"repository_owner": repositoryOwner,
+		"repository_name":  repositoryName,
+		"comment":          comment,
+	})
+}
+
+func (s *JSONL) SaveProject(ctx context.Context, owner, repositoryName string, project *graphql.Project) error {
+	return s.save("projects", map[string]interface{}{
+		"owner":           owner,
+		"repository_name": repositoryName,
+		"project":         project,
+	})
+}
+
+func (s *JSONL) SaveProjectColumn(ctx context.Context, projectID string, column *graphql.ProjectColumn) error {
+	return s.save("project_columns", map[string]interface{}{
+		"project_id": projectID,
+		"column":     column,
+	})
+}
+
+func (s *JSONL) SaveProjectCard(ctx context.Context, projectID string, columnID string, card *graphql.ProjectCard) error {
+	return s.save("project_cards", map[string]interface{}{
+		"project_id": projectID,
+		"column_id":  columnID,
+		"card":       card,
+	})
+}
+
+func (s *JSONL) SaveProjectV2(ctx context.Context, owner, repositoryName string, project *graphql.ProjectV2) error {
+	return s.save("projects_v2", map[string]interface{}{
+		"owner":           owner,
+		"repository_name": repositoryName,
+		"project":         project,
+	})
+}
+
+func (s *JSONL) SaveProjectV2Item(ctx context.Context, projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) error {
+	return s.save("project_v2_items", map[string]interface{}{
+		"project_id":   projectID,
+		"item":         item,
+		"field_values": fieldValues,
+	})
+}
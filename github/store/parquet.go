@@ -0,0 +1,635 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// ParquetKinds are the kinds of items saved by Parquet, each one with the
+// columns of its versioned table, like issuesCols for the issues. As in the
+// tables, the issue and pull request comments are saved together in
+// issue_comments, and pull_request_comments has the review comments
+var ParquetKinds = []string{
+	"organizations",
+	"users",
+	"contributors",
+	"repositories",
+	"issues",
+	"issue_comments",
+	"pull_requests",
+	"pull_request_reviews",
+	"pull_request_comments",
+	"commit_comments",
+	"projects",
+	"project_columns",
+	"project_cards",
+	"projects_v2",
+	"project_v2_items",
+}
+
+// parquetCols are the columns of each kind of item
+var parquetCols = map[string]string{
+	"organizations":         organizationsCols,
+	"users":                 usersCols,
+	"contributors":          contributorsCols,
+	"repositories":          repositoriesCols,
+	"issues":                issuesCols,
+	"issue_comments":        issueCommentsCols,
+	"pull_requests":         pullRequestsCol,
+	"pull_request_reviews":  pullRequestReviewsCols,
+	"pull_request_comments": pullRequestReviewCommentsCols,
+	"commit_comments":       commitCommentsCols,
+	"projects":              projectsCols,
+	"project_columns":       projectColumnsCols,
+	"project_cards":         projectCardsCols,
+	"projects_v2":           projectsV2Cols,
+	"project_v2_items":      projectV2ItemsCols,
+}
+
+// columnTypes are the PostgreSQL types of the columns, the same in all the
+// tables
+var columnTypes = map[string]string{
+	"additions":                "bigint",
+	"allow_merge_commit":       "boolean",
+	"allow_rebase_merge":       "boolean",
+	"allow_squash_merge":       "boolean",
+	"archived":                 "boolean",
+	"assignees":                "text[]",
+	"author_association":       "text",
+	"avatar_url":               "text",
+	"base_ref":                 "text",
+	"base_repository_name":     "text",
+	"base_repository_owner":    "text",
+	"base_sha":                 "text",
+	"base_user":                "text",
+	"bio":                      "text",
+	"body":                     "text",
+	"changed_files":            "bigint",
+	"closed":                   "boolean",
+	"closed_at":                "timestamptz",
+	"closed_by_id":             "bigint",
+	"closed_by_login":          "text",
+	"collaborators":            "bigint",
+	"column_node_id":           "text",
+	"comments":                 "bigint",
+	"commit_id":                "text",
+	"commits":                  "bigint",
+	"company":                  "text",
+	"content_node_id":          "text",
+	"content_number":           "bigint",
+	"content_repository_name":  "text",
+	"content_repository_owner": "text",
+	"content_title":            "text",
+	"content_type":             "text",
+	"created_at":               "timestamptz",
+	"creator_login":            "text",
+	"default_branch":           "text",
+	"deletions":                "bigint",
+	"description":              "text",
+	"diff_hunk":                "text",
+	"disabled":                 "boolean",
+	"email":                    "text",
+	"field_values":             "jsonb",
+	"followers":                "bigint",
+	"following":                "bigint",
+	"fork":                     "boolean",
+	"forks_count":              "bigint",
+	"full_name":                "text",
+	"has_issues":               "boolean",
+	"has_wiki":                 "boolean",
+	"head_ref":                 "text",
+	"head_repository_name":     "text",
+	"head_repository_owner":    "text",
+	"head_sha":                 "text",
+	"head_user":                "text",
+	"hireable":                 "boolean",
+	"homepage":                 "text",
+	"htmlurl":                  "text",
+	"id":                       "bigint",
+	"in_reply_to":              "bigint",
+	"issue_number":             "bigint",
+	"labels":                   "text[]",
+	"language":                 "text",
+	"location":                 "text",
+	"locked":                   "boolean",
+	"login":                    "text",
+	"maintainer_can_modify":    "boolean",
+	"merge_commit_sha":         "text",
+	"mergeable":                "boolean",
+	"merged":                   "boolean",
+	"merged_at":                "timestamptz",
+	"merged_by_id":             "bigint",
+	"merged_by_login":          "text",
+	"milestone_id":             "text",
+	"milestone_title":          "text",
+	"name":                     "text",
+	"node_id":                  "text",
+	"note":                     "text",
+	"number":                   "bigint",
+	"open_issues_count":        "bigint",
+	"organization_id":          "bigint",
+	"organization_login":       "text",
+	"original_commit_id":       "text",
+	"original_position":        "bigint",
+	"owned_private_repos":      "bigint",
+	"owner_id":                 "bigint",
+	"owner_login":              "text",
+	"owner_type":               "text",
+	"path":                     "text",
+	"position":                 "bigint",
+	"private":                  "boolean",
+	"private_gists":            "bigint",
+	"project_node_id":          "text",
+	"public":                   "boolean",
+	"public_gists":             "bigint",
+	"public_repos":             "bigint",
+	"pull_request_number":      "bigint",
+	"pull_request_review_id":   "bigint",
+	"purpose":                  "text",
+	"pushed_at":                "timestamptz",
+	"readme":                   "text",
+	"repository_name":          "text",
+	"repository_owner":         "text",
+	"review_comments":          "bigint",
+	"short_description":        "text",
+	"sshurl":                   "text",
+	"stargazers_count":         "bigint",
+	"state":                    "text",
+	"submitted_at":             "timestamptz",
+	"title":                    "text",
+	"topics":                   "text[]",
+	"total_private_repos":      "bigint",
+	"type":                     "text",
+	"updated_at":               "timestamptz",
+	"user_id":                  "bigint",
+	"user_login":               "text",
+	"watchers_count":           "bigint",
+}
+
+// parquetColumn is the Go type of a Parquet column and its parquet-go tag
+type parquetColumn struct {
+	typ reflect.Type
+	tag string
+}
+
+// parquetColumns are the Parquet columns of each PostgreSQL type
+var parquetColumns = map[string]parquetColumn{
+	"bigint":      {reflect.TypeOf(int64(0)), "type=INT64"},
+	"boolean":     {reflect.TypeOf(false), "type=BOOLEAN"},
+	"text":        {reflect.TypeOf(""), "type=UTF8"},
+	"text[]":      {reflect.TypeOf([]string(nil)), "type=LIST, valuetype=UTF8"},
+	"jsonb":       {reflect.TypeOf(""), "type=JSON"},
+	"timestamptz": {reflect.TypeOf((*int64)(nil)), "type=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"},
+}
+
+// nullableColumns are the columns, other than the times, that are NULL for
+// some items, like the path of the comments that are not on a line
+var nullableColumns = map[string]bool{
+	"path":     true,
+	"position": true,
+}
+
+// parquetType returns the struct written by parquet-go for the given columns,
+// with a field for each one
+func parquetType(cols string) reflect.Type {
+	var fields []reflect.StructField
+	for _, col := range strings.Split(cols, ", ") {
+		c := parquetColumns[columnTypes[col]]
+		typ, tag := c.typ, c.tag
+		if nullableColumns[col] {
+			typ, tag = reflect.PtrTo(typ), tag+", repetitiontype=OPTIONAL"
+		}
+
+		fields = append(fields, reflect.StructField{
+			Name: "Col_" + col,
+			Type: typ,
+			Tag:  reflect.StructTag(fmt.Sprintf(`parquet:"name=%s, %s"`, col, tag)),
+		})
+	}
+
+	return reflect.StructOf(fields)
+}
+
+// parquetValue returns the value of a field of the given type in the struct
+// returned by parquetType, for a column of the given PostgreSQL type. The
+// times are kept as milliseconds since the Unix epoch, the ones saved as
+// strings are parsed
+func parquetValue(colType string, typ reflect.Type, v interface{}) (reflect.Value, error) {
+	if colType != "timestamptz" {
+		value := reflect.ValueOf(v)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Zero(typ), nil
+			}
+
+			value = value.Elem()
+		}
+
+		if typ.Kind() != reflect.Ptr {
+			return value.Convert(typ), nil
+		}
+
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(value.Convert(typ.Elem()))
+		return ptr, nil
+	}
+
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return reflect.Zero(typ), nil
+		}
+
+		t = *v
+	case string:
+		if v == "" {
+			return reflect.Zero(typ), nil
+		}
+
+		var err error
+		if t, err = time.Parse(time.RFC3339, v); err != nil {
+			return reflect.Value{}, err
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("unexpected time %v", v)
+	}
+
+	ms := t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+	return reflect.ValueOf(&ms), nil
+}
+
+const defaultRowGroupRows = 10000
+
+// Parquet saves the items in Parquet files, with a file for each kind of item
+// and version in a directory, named like issues.v1.parquet. The columns of
+// each kind are the ones of its versioned table, in the same order, without
+// sum256 and versions. Their types are:
+//
+//	bigint       INT64
+//	boolean      BOOLEAN
+//	text         BYTE_ARRAY (UTF8)
+//	text[]       LIST of BYTE_ARRAY (UTF8)
+//	jsonb        BYTE_ARRAY (JSON)
+//	timestamptz  optional INT64 (TIMESTAMP_MILLIS), in UTC
+//
+// The path and position of the comments are optional too, they are NULL for
+// the comments that are not on a line.
+//
+// The items saved since Begin are kept in memory, Commit writes them to the
+// files and Rollback discards them. The rows are written in row groups of a
+// fixed number of rows, the last one when the files are closed by Close.
+// Until then they are written to hidden temporary files, Close renames them.
+// The items saved again, like when a repository is downloaded again, are
+// written again.
+//
+// SetActiveVersion does nothing, and Cleanup removes the files of the other
+// versions
+type Parquet struct {
+	dir          string
+	rowGroupRows int
+
+	mu    sync.Mutex
+	v     int
+	tx    map[string][][]interface{}
+	files map[parquetKey]*parquetWriter
+}
+
+// parquetKey identifies the file of a kind of item and version
+type parquetKey struct {
+	kind    string
+	version int
+}
+
+// NewParquet returns a Parquet that writes the files in dir, with row groups
+// of rowGroupRows rows, or 10000 when it is 0
+func NewParquet(dir string, rowGroupRows int) *Parquet {
+	if rowGroupRows <= 0 {
+		rowGroupRows = defaultRowGroupRows
+	}
+
+	return &Parquet{
+		dir:          dir,
+		rowGroupRows: rowGroupRows,
+		files:        make(map[parquetKey]*parquetWriter),
+	}
+}
+
+// Path returns the path of the file with the items of the given kind and
+// version
+func (s *Parquet) Path(kind string, version int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.v%d.parquet", kind, version))
+}
+
+// parquetFile is the source.ParquetFile of a local file
+type parquetFile struct {
+	*os.File
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.Name()
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetFile{file}, nil
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetFile{file}, nil
+}
+
+// parquetWriter writes the rows of a file, flushing a row group every
+// rowGroupRows rows
+type parquetWriter struct {
+	path string
+	f    *parquetFile
+	w    *writer.ParquetWriter
+	typ  reflect.Type
+	rows int
+}
+
+func (s *Parquet) writer(kind string) (*parquetWriter, error) {
+	key := parquetKey{kind, s.v}
+	if w, ok := s.files[key]; ok {
+		return w, nil
+	}
+
+	path := s.Path(kind, s.v)
+	f, err := os.Create(filepath.Join(s.dir, "."+filepath.Base(path)+".tmp"))
+	if err != nil {
+		return nil, err
+	}
+
+	typ := parquetType(parquetCols[kind])
+	pw := &parquetWriter{path: path, f: &parquetFile{f}, typ: typ}
+	pw.w, err = writer.NewParquetWriter(pw.f, reflect.New(typ).Interface(), 1)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	s.files[key] = pw
+	return pw, nil
+}
+
+func (w *parquetWriter) write(row []interface{}, rowGroupRows int) error {
+	v := reflect.New(w.typ).Elem()
+	for i, value := range row {
+		field := v.Field(i)
+		col := strings.TrimPrefix(v.Type().Field(i).Name, "Col_")
+		value, err := parquetValue(columnTypes[col], field.Type(), value)
+		if err != nil {
+			return fmt.Errorf("could not write the column %s: %v", col, err)
+		}
+
+		field.Set(value)
+	}
+
+	if err := w.w.Write(v.Interface()); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows < rowGroupRows {
+		return nil
+	}
+
+	w.rows = 0
+	return w.w.Flush(true)
+}
+
+// close writes the last row group and the footer, and renames the file
+func (w *parquetWriter) close() error {
+	if err := w.w.WriteStop(); err != nil {
+		w.f.Close()
+		os.Remove(w.f.Name())
+		return err
+	}
+
+	if err := w.f.Sync(); err != nil {
+		w.f.Close()
+		os.Remove(w.f.Name())
+		return err
+	}
+
+	if err := w.f.Close(); err != nil {
+		os.Remove(w.f.Name())
+		return err
+	}
+
+	return os.Rename(w.f.Name(), w.path)
+}
+
+// Close writes the files of all the versions, the items must not be saved
+// after it
+func (s *Parquet) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for key, w := range s.files {
+		if e := w.close(); e != nil && err == nil {
+			err = fmt.Errorf("could not write the %s of version %d: %v", key.kind, key.version, e)
+		}
+	}
+
+	s.files = make(map[parquetKey]*parquetWriter)
+	return err
+}
+
+func (s *Parquet) Begin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx != nil {
+		return fmt.Errorf("a transaction is already in progress")
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	s.tx = make(map[string][][]interface{})
+	return nil
+}
+
+func (s *Parquet) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx == nil {
+		return fmt.Errorf("there is no transaction in progress")
+	}
+
+	tx := s.tx
+	s.tx = nil
+
+	// the kinds are written in the same order every time
+	for _, kind := range ParquetKinds {
+		rows := tx[kind]
+		if len(rows) == 0 {
+			continue
+		}
+
+		w, err := s.writer(kind)
+		if err != nil {
+			return fmt.Errorf("could not create the file of the %s: %v", kind, err)
+		}
+
+		for _, row := range rows {
+			if err := w.write(row, s.rowGroupRows); err != nil {
+				return fmt.Errorf("could not write the %s: %v", kind, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Parquet) Rollback() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx == nil {
+		return fmt.Errorf("there is no transaction in progress")
+	}
+
+	s.tx = nil
+	return nil
+}
+
+func (s *Parquet) Version(v int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.v = v
+}
+
+// SetActiveVersion does nothing, the items of each version are in their own
+// files
+func (s *Parquet) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+// Cleanup removes the files of the versions other than currentVersion
+func (s *Parquet) Cleanup(ctx context.Context, currentVersion int) error {
+	for _, kind := range ParquetKinds {
+		paths, err := filepath.Glob(filepath.Join(s.dir, kind+".v*.parquet"))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			if path == s.Path(kind, currentVersion) {
+				continue
+			}
+
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed in cleanup method: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// save keeps the row of the kind until the transaction is committed
+func (s *Parquet) save(kind string, row []interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx == nil {
+		return fmt.Errorf("could not save the %s: Begin was not called", kind)
+	}
+
+	s.tx[kind] = append(s.tx[kind], row)
+	return nil
+}
+
+func (s *Parquet) SaveOrganization(ctx context.Context, organization *graphql.Organization) error {
+	return s.save("organizations", organizationRow(organization))
+}
+
+func (s *Parquet) SaveUser(ctx context.Context, orgID int, orgLogin string, user *graphql.UserExtended) error {
+	return s.save("users", userRow(orgID, orgLogin, user))
+}
+
+func (s *Parquet) SaveContributor(ctx context.Context, user *graphql.UserExtended) error {
+	return s.save("contributors", contributorRow(user))
+}
+
+func (s *Parquet) SaveRepository(ctx context.Context, repository *graphql.RepositoryFields, topics []string) error {
+	return s.save("repositories", repositoryRow(repository, topics))
+}
+
+func (s *Parquet) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *graphql.Issue, assignees []string, labels []string) error {
+	return s.save("issues", issueRow(repositoryOwner, repositoryName, issue, assignees, labels))
+}
+
+func (s *Parquet) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *graphql.IssueComment) error {
+	return s.save("issue_comments", issueCommentRow(repositoryOwner, repositoryName, issueNumber, comment))
+}
+
+func (s *Parquet) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *graphql.PullRequest, assignees []string, labels []string) error {
+	return s.save("pull_requests", pullRequestRow(repositoryOwner, repositoryName, pr, assignees, labels))
+}
+
+func (s *Parquet) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *graphql.IssueComment) error {
+	return s.SaveIssueComment(ctx, repositoryOwner, repositoryName, pullRequestNumber, comment)
+}
+
+func (s *Parquet) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) error {
+	return s.save("pull_request_reviews", pullRequestReviewRow(repositoryOwner, repositoryName, pullRequestNumber, review))
+}
+
+func (s *Parquet) SavePullRequestReviewComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, pullRequestReviewId int, comment *graphql.PullRequestReviewComment) error {
+	return s.save("pull_request_comments", pullRequestReviewCommentRow(repositoryOwner, repositoryName, pullRequestNumber, pullRequestReviewId, comment))
+}
+
+func (s *Parquet) SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error {
+	return s.save("commit_comments", commitCommentRow(repositoryOwner, repositoryName, comment))
+}
+
+func (s *Parquet) SaveProject(ctx context.Context, owner, repositoryName string, project *graphql.Project) error {
+	return s.save("projects", projectRow(owner, repositoryName, project))
+}
+
+func (s *Parquet) SaveProjectColumn(ctx context.Context, projectID string, column *graphql.ProjectColumn) error {
+	return s.save("project_columns", projectColumnRow(projectID, column))
+}
+
+func (s *Parquet) SaveProjectCard(ctx context.Context, projectID string, columnID string, card *graphql.ProjectCard) error {
+	return s.save("project_cards", projectCardRow(projectID, columnID, card))
+}
+
+func (s *Parquet) SaveProjectV2(ctx context.Context, owner, repositoryName string, project *graphql.ProjectV2) error {
+	return s.save("projects_v2", projectV2Row(owner, repositoryName, project))
+}
+
+func (s *Parquet) SaveProjectV2Item(ctx context.Context, projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) error {
+	return s.save("project_v2_items", projectV2ItemRow(projectID, item, fieldValues))
+}
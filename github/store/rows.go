@@ -0,0 +1,411 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+)
+
+// organizationRow returns the values of organizationsCols
+func organizationRow(organization *graphql.Organization) []interface{} {
+	return []interface{}{
+		organization.AvatarURL,                    // avatar_url text,
+		organization.MembersWithRole.TotalCount,   // collaborators bigint,
+		organization.CreatedAt,                    // created_at timestamptz,
+		organization.Description,                  // description text,
+		organization.Email,                        // email text,
+		organization.URL,                          // htmlurl text,
+		organization.DatabaseID,                   // id bigint,
+		organization.Login,                        // login text,
+		organization.Name,                         // name text,
+		organization.ID,                           // node_id text,
+		organization.OwnedPrivateRepos.TotalCount, // owned_private_repos bigint,
+		organization.PublicRepos.TotalCount,       // public_repos bigint,
+		organization.TotalPrivateRepos.TotalCount, // total_private_repos bigint,
+		organization.UpdatedAt,                    // updated_at timestamptz,
+	}
+}
+
+// userRow returns the values of usersCols
+func userRow(orgID int, orgLogin string, user *graphql.UserExtended) []interface{} {
+	return []interface{}{
+		user.AvatarURL,                    // avatar_url text,
+		user.Bio,                          // bio text,
+		user.Company,                      // company text,
+		user.CreatedAt,                    // created_at timestamptz,
+		user.Email,                        // email text,
+		user.Followers.TotalCount,         // followers bigint,
+		user.Following.TotalCount,         // following bigint,
+		user.IsHireable,                   // hireable boolean,
+		user.URL,                          // htmlurl text,
+		user.DatabaseID,                   // id bigint,
+		user.Location,                     // location text,
+		user.Login,                        // login text,
+		user.Name,                         // name text,
+		user.ID,                           // node_id text,
+		orgID,                             // organization_id bigint NOT NULL
+		orgLogin,                          // organization_login text NOT NULL
+		user.OwnedPrivateRepos.TotalCount, // owned_private_repos bigint,
+		user.PrivateGists,                 // private_gists bigint,
+		user.PublicGists,                  // public_gists bigint,
+		user.PublicRepos.TotalCount,       // public_repos bigint,
+		user.TotalPrivateRepos.TotalCount, // total_private_repos bigint,
+		user.UpdatedAt,                    // updated_at timestamptz,
+	}
+}
+
+// contributorRow returns the values of contributorsCols
+func contributorRow(user *graphql.UserExtended) []interface{} {
+	return []interface{}{
+		user.AvatarURL,                    // avatar_url text,
+		user.Bio,                          // bio text,
+		user.Company,                      // company text,
+		user.CreatedAt,                    // created_at timestamptz,
+		user.Email,                        // email text,
+		user.Followers.TotalCount,         // followers bigint,
+		user.Following.TotalCount,         // following bigint,
+		user.IsHireable,                   // hireable boolean,
+		user.URL,                          // htmlurl text,
+		user.DatabaseID,                   // id bigint,
+		user.Location,                     // location text,
+		user.Login,                        // login text,
+		user.Name,                         // name text,
+		user.ID,                           // node_id text,
+		user.OwnedPrivateRepos.TotalCount, // owned_private_repos bigint,
+		user.PrivateGists,                 // private_gists bigint,
+		user.PublicGists,                  // public_gists bigint,
+		user.PublicRepos.TotalCount,       // public_repos bigint,
+		user.TotalPrivateRepos.TotalCount, // total_private_repos bigint,
+		user.UpdatedAt,                    // updated_at timestamptz,
+	}
+}
+
+// repositoryRow returns the values of repositoriesCols
+func repositoryRow(repository *graphql.RepositoryFields, topics []string) []interface{} {
+	return []interface{}{
+		repository.MergeCommitAllowed,    // allow_merge_commit boolean
+		repository.RebaseMergeAllowed,    // allow_rebase_merge boolean
+		repository.SquashMergeAllowed,    // allow_squash_merge boolean
+		repository.IsArchived,            // archived boolean
+		repository.CreatedAt,             // created_at timestamptz
+		repository.DefaultBranchRef.Name, // default_branch text
+		repository.Description,           // description text
+		repository.IsDisabled,            // disabled boolean
+		repository.IsFork,                // fork boolean
+		repository.ForkCount,             // forks_count bigint
+		repository.NameWithOwner,         // full_name text
+		repository.HasIssuesEnabled,      // has_issues boolean
+		repository.HasWikiEnabled,        // has_wiki boolean
+		repository.HomepageURL,           // homepage text
+		repository.URL,                   // htmlurl text
+		repository.DatabaseID,            // id bigint,
+		repository.PrimaryLanguage.Name,  // language text
+		repository.Name,                  // name text
+		repository.ID,                    // node_id text
+		repository.OpenIssues.TotalCount, // open_issues_count bigint
+		repoOwnerID(repository),          // owner_id bigint NOT NULL,
+		repository.Owner.Login,           // owner_login text NOT NULL,
+		repository.Owner.Typename,        // owner_type text NOT NULL
+		repository.IsPrivate,             // private boolean
+		repository.PushedAt,              // pushed_at timestamptz
+		repository.SSHURL,                // sshurl text
+		repository.Stargazers.TotalCount, // stargazers_count bigint
+		topics,                           // topics text[] NOT NULL
+		repository.UpdatedAt,             // updated_at timestamptz
+		repository.Watchers.TotalCount,   // watchers_count bigint
+	}
+}
+
+// issueRow returns the values of issuesCols
+func issueRow(repositoryOwner, repositoryName string, issue *graphql.Issue, assignees []string, labels []string) []interface{} {
+	closedByID := 0
+	closedByLogin := ""
+
+	if len(issue.ClosedBy.Nodes) > 0 {
+		closedByID = issue.ClosedBy.Nodes[0].ClosedEvent.Actor.DatabaseID
+		closedByLogin = issue.ClosedBy.Nodes[0].ClosedEvent.Actor.Login
+	}
+
+	return []interface{}{
+		assignees,                    // assignees text[] NOT NULL,
+		issue.Body,                   // body text,
+		issue.ClosedAt,               // closed_at timestamptz,
+		closedByID,                   // closed_by_id bigint NOT NULL
+		closedByLogin,                // closed_by_login text NOT NULL,
+		issue.Comments.TotalCount,    // comments bigint,
+		issue.CreatedAt,              // created_at timestamptz,
+		issue.URL,                    // htmlurl text,
+		issue.DatabaseID,             // id bigint,
+		labels,                       // labels text[] NOT NULL,
+		issue.Locked,                 // locked boolean,
+		issue.Milestone.ID,           // milestone_id text NOT NULL,
+		issue.Milestone.Title,        // milestone_title text NOT NULL,
+		issue.ID,                     // node_id text,
+		issue.Number,                 // number bigint,
+		repositoryName,               // repository_name text NOT NULL,
+		repositoryOwner,              // repository_owner text NOT NULL,
+		issue.State,                  // state text,
+		issue.Title,                  // title text,
+		issue.UpdatedAt,              // updated_at timestamptz,
+		issue.Author.User.DatabaseID, // user_id bigint NOT NULL,
+		issue.Author.Login,           // user_login text NOT NULL,
+	}
+}
+
+// issueCommentRow returns the values of issueCommentsCols
+func issueCommentRow(repositoryOwner, repositoryName string, issueNumber int, comment *graphql.IssueComment) []interface{} {
+	return []interface{}{
+		comment.AuthorAssociation,      // author_association text,
+		comment.Body,                   // body text,
+		comment.CreatedAt,              // created_at timestamptz,
+		comment.URL,                    // htmlurl text,
+		comment.DatabaseID,             // id bigint,
+		issueNumber,                    // issue_number bigint NOT NULL,
+		comment.ID,                     // node_id text,
+		repositoryName,                 // repository_name text NOT NULL,
+		repositoryOwner,                // repository_owner text NOT NULL,
+		comment.UpdatedAt,              // updated_at timestamptz,
+		comment.Author.User.DatabaseID, // user_id bigint NOT NULL,
+		comment.Author.Login,           // user_login text NOT NULL,
+	}
+}
+
+// pullRequestRow returns the values of pullRequestsCol
+func pullRequestRow(repositoryOwner, repositoryName string, pr *graphql.PullRequest, assignees []string, labels []string) []interface{} {
+	return []interface{}{
+		pr.Additions,                               // additions bigint,
+		assignees,                                  // assignees text[] NOT NULL,
+		pr.AuthorAssociation,                       // author_association text,
+		pr.BaseRef.Name,                            // base_ref text NOT NULL,
+		pr.BaseRef.Repository.Name,                 // base_repository_name text NOT NULL,
+		pr.BaseRef.Repository.Owner.Login,          // base_repository_owner text NOT NULL,
+		pr.BaseRef.Target.Oid,                      // base_sha text NOT NULL,
+		pr.BaseRef.Target.Commit.Author.User.Login, // base_user text NOT NULL,
+		pr.Body,                           // body text,
+		pr.ChangedFiles,                   // changed_files bigint,
+		pr.ClosedAt,                       // closed_at timestamptz,
+		pr.Comments.TotalCount,            // comments bigint,
+		pr.Commits.TotalCount,             // commits bigint,
+		pr.CreatedAt,                      // created_at timestamptz,
+		pr.Deletions,                      // deletions bigint,
+		pr.HeadRef.Name,                   // head_ref text NOT NULL,
+		pr.HeadRef.Repository.Name,        // head_repository_name text NOT NULL,
+		pr.HeadRef.Repository.Owner.Login, // head_repository_owner text NOT NULL,
+		pr.HeadRef.Target.Oid,             // head_sha text NOT NULL,
+		pr.HeadRef.Target.Commit.Author.User.Login, // head_user text NOT NULL,
+		pr.URL,                      // htmlurl text,
+		pr.DatabaseID,               // id bigint,
+		labels,                      // labels text[] NOT NULL,
+		pr.MaintainerCanModify,      // maintainer_can_modify boolean,
+		pr.MergeCommit.Oid,          // merge_commit_sha text,
+		pr.Mergeable == "MERGEABLE", // mergeable boolean,
+		pr.Merged,                   // merged boolean,
+		pr.MergedAt,                 // merged_at timestamptz,
+		pr.MergedBy.DatabaseID,      // merged_by_id bigint NOT NULL,
+		pr.MergedBy.Login,           // merged_by_login text NOT NULL,
+		pr.Milestone.ID,             // milestone_id text NOT NULL,
+		pr.Milestone.Title,          // milestone_title text NOT NULL,
+		pr.ID,                       // node_id text,
+		pr.Number,                   // number bigint,
+		repositoryName,              // repository_name text NOT NULL,
+		repositoryOwner,             // repository_owner text NOT NULL,
+		pr.ReviewThreads.TotalCount, // review_comments bigint,
+		pr.State,                    // state text,
+		pr.Title,                    // title text,
+		pr.UpdatedAt,                // updated_at timestamptz,
+		pr.Author.DatabaseID,        // user_id bigint NOT NULL,
+		pr.Author.Login,             // user_login text NOT NULL,
+	}
+}
+
+// pullRequestReviewRow returns the values of pullRequestReviewsCols
+func pullRequestReviewRow(repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) []interface{} {
+	return []interface{}{
+		review.Body,                   // body text,
+		review.Commit.Oid,             // commit_id text,
+		review.URL,                    // htmlurl text,
+		review.DatabaseID,             // id bigint,
+		review.ID,                     // node_id text,
+		pullRequestNumber,             // pull_request_number bigint NOT NULL,
+		repositoryName,                // repository_name text NOT NULL,
+		repositoryOwner,               // repository_owner text NOT NULL,
+		review.State,                  // state text,
+		review.SubmittedAt,            // submitted_at timestamptz,
+		review.Author.User.DatabaseID, // user_id bigint NOT NULL,
+		review.Author.Login,           // user_login text NOT NULL,
+	}
+}
+
+// pullRequestReviewCommentRow returns the values of pullRequestReviewCommentsCols
+func pullRequestReviewCommentRow(repositoryOwner, repositoryName string, pullRequestNumber int, pullRequestReviewId int, comment *graphql.PullRequestReviewComment) []interface{} {
+	return []interface{}{
+		comment.AuthorAssociation,  // author_association text,
+		comment.Body,               // body text,
+		comment.Commit.Oid,         // commit_id text,
+		comment.CreatedAt,          // created_at timestamptz,
+		comment.DiffHunk,           // diff_hunk text,
+		comment.URL,                // htmlurl text,
+		comment.DatabaseID,         // id bigint,
+		comment.InReplyTo,          // in_reply_to bigint,
+		comment.ID,                 // node_id text,
+		comment.OriginalCommit.Oid, // original_commit_id text,
+		comment.OriginalPosition,   // original_position bigint,
+		comment.Path,               // path text,
+		comment.Position,           // position bigint,
+		pullRequestNumber,          // pull_request_number bigint NOT NULL,
+		pullRequestReviewId,        // pull_request_review_id bigint,
+		repositoryName,             // repository_name text NOT NULL,
+		repositoryOwner,            // repository_owner text NOT NULL,
+		comment.UpdatedAt,          // updated_at timestamptz,
+		comment.Author.DatabaseID,  // user_id bigint NOT NULL,
+		comment.Author.Login,       // user_login text NOT NULL,
+	}
+}
+
+// commitCommentRow returns the values of commitCommentsCols
+func commitCommentRow(repositoryOwner, repositoryName string, comment *graphql.CommitComment) []interface{} {
+	return []interface{}{
+		comment.AuthorAssociation,      // author_association text,
+		comment.Body,                   // body text,
+		comment.Commit.Oid,             // commit_id text,
+		comment.CreatedAt,              // created_at timestamptz,
+		comment.URL,                    // htmlurl text,
+		comment.DatabaseID,             // id bigint,
+		comment.ID,                     // node_id text,
+		comment.Path,                   // path text,
+		comment.Position,               // position bigint,
+		repositoryName,                 // repository_name text NOT NULL,
+		repositoryOwner,                // repository_owner text NOT NULL,
+		comment.UpdatedAt,              // updated_at timestamptz,
+		comment.Author.User.DatabaseID, // user_id bigint NOT NULL,
+		comment.Author.Login,           // user_login text NOT NULL,
+	}
+}
+
+// projectRow returns the values of projectsCols
+func projectRow(owner, repositoryName string, project *graphql.Project) []interface{} {
+	return []interface{}{
+		project.Body,          // body text,
+		project.Closed,        // closed boolean,
+		project.ClosedAt,      // closed_at timestamptz,
+		project.CreatedAt,     // created_at timestamptz,
+		project.Creator.Login, // creator_login text NOT NULL,
+		project.URL,           // htmlurl text,
+		project.DatabaseID,    // id bigint,
+		project.Name,          // name text,
+		project.ID,            // node_id text,
+		project.Number,        // number bigint,
+		owner,                 // owner_login text NOT NULL,
+		repositoryName,        // repository_name text NOT NULL,
+		project.State,         // state text,
+		project.UpdatedAt,     // updated_at timestamptz,
+	}
+}
+
+// projectColumnRow returns the values of projectColumnsCols
+func projectColumnRow(projectID string, column *graphql.ProjectColumn) []interface{} {
+	return []interface{}{
+		column.CreatedAt,  // created_at timestamptz,
+		column.URL,        // htmlurl text,
+		column.DatabaseID, // id bigint,
+		column.Name,       // name text,
+		column.ID,         // node_id text,
+		projectID,         // project_node_id text NOT NULL,
+		column.Purpose,    // purpose text,
+		column.UpdatedAt,  // updated_at timestamptz,
+	}
+}
+
+// projectCardRow returns the values of projectCardsCols
+func projectCardRow(projectID string, columnID string, card *graphql.ProjectCard) []interface{} {
+	content := projectItemContent(card.Content)
+
+	return []interface{}{
+		card.IsArchived,                // archived boolean,
+		columnID,                       // column_node_id text NOT NULL,
+		content.ID,                     // content_node_id text NOT NULL,
+		content.Number,                 // content_number bigint NOT NULL,
+		content.Repository.Name,        // content_repository_name text NOT NULL,
+		content.Repository.Owner.Login, // content_repository_owner text NOT NULL,
+		card.Content.Typename,          // content_type text NOT NULL,
+		card.CreatedAt,                 // created_at timestamptz,
+		card.Creator.Login,             // creator_login text NOT NULL,
+		card.URL,                       // htmlurl text,
+		card.DatabaseID,                // id bigint,
+		card.ID,                        // node_id text,
+		card.Note,                      // note text,
+		projectID,                      // project_node_id text NOT NULL,
+		card.State,                     // state text,
+		card.UpdatedAt,                 // updated_at timestamptz,
+	}
+}
+
+// projectV2Row returns the values of projectsV2Cols
+func projectV2Row(owner, repositoryName string, project *graphql.ProjectV2) []interface{} {
+	return []interface{}{
+		project.Closed,           // closed boolean,
+		project.ClosedAt,         // closed_at timestamptz,
+		project.CreatedAt,        // created_at timestamptz,
+		project.Creator.Login,    // creator_login text NOT NULL,
+		project.URL,              // htmlurl text,
+		project.ID,               // node_id text,
+		project.Number,           // number bigint,
+		owner,                    // owner_login text NOT NULL,
+		project.Public,           // public boolean,
+		project.Readme,           // readme text,
+		repositoryName,           // repository_name text NOT NULL,
+		project.ShortDescription, // short_description text,
+		project.Title,            // title text,
+		project.UpdatedAt,        // updated_at timestamptz,
+	}
+}
+
+// projectV2ItemRow returns the values of projectV2ItemsCols
+func projectV2ItemRow(projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) []interface{} {
+	// a map of strings is always encoded
+	values, _ := json.Marshal(fieldValues)
+
+	content := projectItemContent(item.Content.ProjectItemContent)
+	title := content.Title
+	if item.Content.Typename == "DraftIssue" {
+		title = item.Content.DraftIssue.Title
+	}
+
+	return []interface{}{
+		item.IsArchived,                // archived boolean,
+		content.ID,                     // content_node_id text NOT NULL,
+		content.Number,                 // content_number bigint NOT NULL,
+		content.Repository.Name,        // content_repository_name text NOT NULL,
+		content.Repository.Owner.Login, // content_repository_owner text NOT NULL,
+		title,                          // content_title text NOT NULL,
+		item.Content.Typename,          // content_type text NOT NULL,
+		item.CreatedAt,                 // created_at timestamptz,
+		item.Creator.Login,             // creator_login text NOT NULL,
+		string(values),                 // field_values jsonb NOT NULL,
+		item.ID,                        // node_id text,
+		projectID,                      // project_node_id text NOT NULL,
+		item.Type,                      // type text,
+		item.UpdatedAt,                 // updated_at timestamptz,
+	}
+}
+
+func repoOwnerID(repository *graphql.RepositoryFields) int {
+	switch repository.Owner.Typename {
+	case "Orgazation":
+		return repository.Owner.Organization.DatabaseID
+	case "User":
+		return repository.Owner.User.DatabaseID
+	default:
+		return 0
+	}
+}
+
+// projectItemContent returns the Issue or PullRequest linked by a project
+// card or item, or an empty one for notes and draft issues
+func projectItemContent(content graphql.ProjectItemContent) graphql.ProjectItemIssue {
+	if linked := content.Linked(); linked != nil {
+		return *linked
+	}
+
+	return graphql.ProjectItemIssue{}
+}
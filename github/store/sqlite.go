@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SQLite saves the items in the versioned tables of a SQLite database,
+// migrated with sqlite.Migrate. The tables are the same as in PostgreSQL, with
+// the arrays encoded as JSON. SetActiveVersion creates the views of the
+// GitHub tables, and the unified views with their GitHub rows. Unlike in
+// PostgreSQL the unified views are not materialized, and they do not have the
+// rows of the other providers
+type SQLite struct {
+	*DB
+}
+
+func NewSQLite(db *sql.DB) *SQLite {
+	return &SQLite{DB: &DB{DB: db, dialect: sqlite{}}}
+}
+
+// hasVersion returns the condition of the rows with the version v in their
+// versions, kept as a JSON array without spaces
+func hasVersion(v int) string {
+	return fmt.Sprintf(`replace(replace(versions, '[', ','), ']', ',') LIKE '%%,%d,%%'`, v)
+}
+
+func (s *SQLite) SetActiveVersion(ctx context.Context, v int) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		viewName := strings.Replace(table, "_versioned", "", 1)
+		cols, err := sqliteColumns(ctx, tx, table)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to get columns for %s view: %v", viewName, err)
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`DROP VIEW IF EXISTS %s`, viewName))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to drop VIEW %s: %v", viewName, err)
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`CREATE VIEW %s AS
+		SELECT %s
+		FROM %s WHERE %s`, viewName, strings.Join(cols, ", "), table, hasVersion(v)))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create VIEW %s: %v", viewName, err)
+		}
+	}
+
+	// the unified views read the views of the version, created above
+	for _, view := range sqliteUnifiedViews {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`DROP VIEW IF EXISTS %s`, view.name))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to drop unified VIEW %s: %v", view.name, err)
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`CREATE VIEW %s AS %s`, view.name, view.query))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create unified VIEW %s: %v", view.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// sqliteUnifiedViews are the unified views of the SQLite databases, with the
+// same columns as the ones of database.SetUnifiedViews. They are created in
+// order, a view can read the ones before it
+var sqliteUnifiedViews = []struct {
+	name  string
+	query string
+}{
+	{"owners", `SELECT login, name FROM github_organizations`},
+	{"users", `
+		SELECT login, name FROM github_users
+		UNION
+		SELECT login, name FROM github_contributors`},
+	{"repositories", `
+		SELECT owner_login AS owner, name, full_name, private, description
+		FROM github_repositories`},
+	{"issues", `
+		SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+			number, state, title, body,
+			created_at, closed_at, updated_at, comments, user_id, user_login, htmlurl AS html_url, labels
+		FROM github_issues`},
+	{"issue_comments", `
+		SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+			c.issue_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+		FROM github_issue_comments AS c
+		JOIN github_issues AS i ON
+			i.repository_owner = c.repository_owner AND
+			i.repository_name = c.repository_name AND
+			i.number = c.issue_number`},
+	{"pull_requests", `
+		SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+			number, state, title, body, created_at, closed_at, merged_at, updated_at,
+			commits, comments, changed_files, additions, deletions, review_comments AS reviews,
+			user_id, user_login, base_repository_name, base_repository_owner,
+			base_repository_name || '/' || base_repository_owner AS base_repository_full_name,
+			head_ref, head_sha, merge_commit_sha, htmlurl AS html_url, labels
+		FROM github_pull_requests`},
+	{"pull_request_reviews", `
+		SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+			pull_request_number, submitted_at as created_at, user_id, user_login, htmlurl AS html_url,
+			CASE WHEN state = 'CHANGES_REQUESTED' THEN 'COMMENTED' ELSE state END AS state
+		FROM github_pull_request_reviews`},
+	{"pull_request_comments", `
+		SELECT c.repository_owner, c.repository_name, c.repository_owner || '/' || c.repository_name AS repository_full_name,
+			issue_number AS pull_request_number, c.created_at, c.body, c.user_id, c.user_login, c.htmlurl AS html_url
+		FROM github_issue_comments AS c
+		JOIN github_pull_requests AS p ON
+			p.repository_owner = c.repository_owner AND
+			p.repository_name = c.repository_name AND
+			p.number = c.issue_number
+		UNION
+		SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+			pull_request_number, created_at, body, user_id, user_login, htmlurl AS html_url
+		FROM github_pull_request_comments
+		UNION
+		SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+			pull_request_number, submitted_at as created_at, body, user_id, user_login, htmlurl AS html_url
+		FROM github_pull_request_reviews
+		WHERE body <> ''`},
+	{"comments", `
+		SELECT repository_owner, repository_name, repository_full_name, 'issue' AS kind,
+			issue_number AS number, NULL AS commit_sha, NULL AS path, NULL AS position,
+			created_at, body, user_id, user_login, html_url
+		FROM issue_comments
+		UNION ALL
+		SELECT repository_owner, repository_name, repository_full_name, 'pull_request' AS kind,
+			pull_request_number AS number, NULL AS commit_sha, NULL AS path, NULL AS position,
+			created_at, body, user_id, user_login, html_url
+		FROM pull_request_comments
+		UNION ALL
+		SELECT repository_owner, repository_name, repository_owner || '/' || repository_name AS repository_full_name,
+			'commit' AS kind, NULL AS number, commit_id AS commit_sha, path, position,
+			created_at, body, user_id, user_login, htmlurl AS html_url
+		FROM github_commit_comments`},
+}
+
+// sqliteColumns returns the columns of the table, without sum256 and versions
+func sqliteColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)
+		WHERE name NOT IN ('sum256', 'versions')
+		ORDER BY cid`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+
+		cols = append(cols, col)
+	}
+
+	return cols, rows.Err()
+}
+
+func (s *SQLite) Cleanup(ctx context.Context, currentVersion int) error {
+	for _, table := range tables {
+		// Delete all entries that do not belong to currentVersion
+		_, err := s.DB.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE NOT (%s)`, table, hasVersion(currentVersion)))
+		if err != nil {
+			return fmt.Errorf("failed in cleanup method, delete: %v", err)
+		}
+
+		// All remaining entries belong to currentVersion, replace the list of versions
+		// with an array of 1 entry
+		_, err = s.DB.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET versions = '[%d]'`, table, currentVersion))
+		if err != nil {
+			return fmt.Errorf("failed in cleanup method, update: %v", err)
+		}
+	}
+
+	return nil
+}
+
+type sqlite struct{}
+
+func (sqlite) upsert(table, cols string) string {
+	n := strings.Count(cols, ",") + 3
+	return fmt.Sprintf(`INSERT INTO %[1]s
+		(sum256, versions, %[2]s)
+		VALUES (%[3]s)
+		ON CONFLICT (sum256)
+		DO UPDATE
+		SET versions = substr(%[1]s.versions, 1, length(%[1]s.versions) - 1) || ',' || ?%[4]d || ']'
+		WHERE replace(replace(%[1]s.versions, '[', ','), ']', ',') NOT LIKE '%%,' || ?%[4]d || ',%%'`,
+		table, cols, strings.Repeat("?, ", n-1)+"?", n+1)
+}
+
+// array encodes the array as JSON, an empty array when it is nil
+func (sqlite) array(a interface{}) interface{} {
+	switch a := a.(type) {
+	case []string:
+		if a == nil {
+			return "[]"
+		}
+	case []int:
+		if a == nil {
+			return "[]"
+		}
+	}
+
+	// the slices of strings and ints are always encoded
+	content, _ := json.Marshal(a)
+	return string(content)
+}
@@ -19,6 +19,11 @@ func (s *Stdout) SaveUser(ctx context.Context, orgID int, orgLogin string, user
 	return nil
 }
 
+func (s *Stdout) SaveContributor(ctx context.Context, user *graphql.UserExtended) error {
+	fmt.Printf("contributor data fetched for %s\n", user.Login)
+	return nil
+}
+
 func (s *Stdout) SaveRepository(ctx context.Context, repository *graphql.RepositoryFields, topics []string) error {
 	fmt.Printf("repository data fetched for %s/%s\n", repository.Owner.Login, repository.Name)
 	return nil
@@ -54,6 +59,36 @@ func (s *Stdout) SavePullRequestReviewComment(ctx context.Context, repositoryOwn
 	return nil
 }
 
+func (s *Stdout) SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error {
+	fmt.Printf("commit comment data fetched by %s at %v on %s: %q\n", comment.Author.Login, comment.CreatedAt, comment.Commit.Oid, trim(comment.Body))
+	return nil
+}
+
+func (s *Stdout) SaveProject(ctx context.Context, owner, repositoryName string, project *graphql.Project) error {
+	fmt.Printf("project data fetched for #%v %s\n", project.Number, project.Name)
+	return nil
+}
+
+func (s *Stdout) SaveProjectColumn(ctx context.Context, projectID string, column *graphql.ProjectColumn) error {
+	fmt.Printf("  project column data fetched for %s\n", column.Name)
+	return nil
+}
+
+func (s *Stdout) SaveProjectCard(ctx context.Context, projectID string, columnID string, card *graphql.ProjectCard) error {
+	fmt.Printf("    project card data fetched by %s at %v: %q\n", card.Creator.Login, card.CreatedAt, trim(card.Note))
+	return nil
+}
+
+func (s *Stdout) SaveProjectV2(ctx context.Context, owner, repositoryName string, project *graphql.ProjectV2) error {
+	fmt.Printf("project v2 data fetched for #%v %s\n", project.Number, project.Title)
+	return nil
+}
+
+func (s *Stdout) SaveProjectV2Item(ctx context.Context, projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) error {
+	fmt.Printf("  project v2 item data fetched for %s %s\n", item.Type, item.ID)
+	return nil
+}
+
 func (s *Stdout) Begin() error {
 	return nil
 }
@@ -0,0 +1,986 @@
+package store_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/database"
+	"github.com/src-d/metadata-retrieval/database/sqlite"
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/github/graphql"
+	"github.com/src-d/metadata-retrieval/github/store"
+	"github.com/src-d/metadata-retrieval/github/storertest"
+	gitlabapi "github.com/src-d/metadata-retrieval/gitlab/api"
+	gitlabstore "github.com/src-d/metadata-retrieval/gitlab/store"
+
+	"github.com/golang-migrate/migrate/v4"
+	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// stdoutHarness runs the Suite against store.Stdout, that only prints the items
+type stdoutHarness struct{}
+
+func (stdoutHarness) NewStorer(t *testing.T) github.Storer {
+	return &store.Stdout{}
+}
+
+func (stdoutHarness) Features() storertest.Features {
+	return storertest.Features{}
+}
+
+func (stdoutHarness) Items(t *testing.T, s github.Storer, kind storertest.Kind, version int) []string {
+	return nil
+}
+
+func (stdoutHarness) ActiveItems(t *testing.T, s github.Storer, kind storertest.Kind) []string {
+	return nil
+}
+
+func TestStdout(t *testing.T) {
+	storertest.Run(t, stdoutHarness{})
+}
+
+// dbHarness runs the Suite against store.DB, reading the versioned tables and the
+// views created by SetActiveVersion
+type dbHarness struct {
+	db *sql.DB
+}
+
+func (h *dbHarness) NewStorer(t *testing.T) github.Storer {
+	for _, kind := range storertest.Kinds {
+		_, err := h.db.Exec(fmt.Sprintf("DELETE FROM github_%s_versioned", kind))
+		require.NoError(t, err)
+	}
+
+	return store.NewDB(h.db)
+}
+
+func (h *dbHarness) Features() storertest.Features {
+	return storertest.Features{Read: true, Versions: true, Transactions: true}
+}
+
+func (h *dbHarness) Items(t *testing.T, s github.Storer, kind storertest.Kind, version int) []string {
+	return h.query(t, fmt.Sprintf("SELECT node_id FROM github_%s_versioned WHERE $1 = ANY(versions)", kind), version)
+}
+
+func (h *dbHarness) ActiveItems(t *testing.T, s github.Storer, kind storertest.Kind) []string {
+	return h.query(t, fmt.Sprintf("SELECT node_id FROM github_%s", kind))
+}
+
+func (h *dbHarness) query(t *testing.T, query string, args ...interface{}) []string {
+	rows, err := h.db.QueryContext(context.TODO(), query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, rows.Err())
+	return ids
+}
+
+// storertestDB returns the database used by the tests and benchmarks of DB,
+// they need the PostgreSQL server configured for the github tests and are
+// skipped when PSQL_USER is not set. The Suite deletes the saved items, so it
+// uses its own database, created next to PSQL_DB, to run along the github
+// tests
+func storertestDB(tb testing.TB) *sql.DB {
+	dbURL := storertestURL(tb)
+	db, err := sql.Open("postgres", dbURL)
+	require.NoError(tb, err, "DB URL is not working")
+
+	require.NoError(tb, db.Ping(), "DB connection is not working")
+	require.NoError(tb, database.Migrate(dbURL), "Cannot migrate the DB")
+	return db
+}
+
+// storertestURL returns the URL of the database used by storertestDB,
+// creating it if it does not exist
+func storertestURL(tb testing.TB) string {
+	if os.Getenv("PSQL_USER") == "" {
+		tb.Skip("PSQL_USER env var not set")
+	}
+
+	require.NotEmpty(tb, os.Getenv("PSQL_DB"), "PSQL_DB env var not set")
+	name := os.Getenv("PSQL_DB") + "_storertest"
+
+	// PSQL_PWD is not required in case someone wants to run the tests in a default local config
+	dbURL := func(name string) string {
+		return fmt.Sprintf("postgres://%s:%s@localhost:5432/%s?sslmode=disable", os.Getenv("PSQL_USER"), os.Getenv("PSQL_PWD"), name)
+	}
+
+	db, err := sql.Open("postgres", dbURL(os.Getenv("PSQL_DB")))
+	require.NoError(tb, err, "DB URL is not working")
+	require.NoError(tb, db.Ping(), "DB connection is not working")
+
+	var exists bool
+	require.NoError(tb, db.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", name).Scan(&exists))
+	if !exists {
+		_, err = db.Exec(fmt.Sprintf("CREATE DATABASE %s", pq.QuoteIdentifier(name)))
+		require.NoError(tb, err, "Cannot create the DB")
+	}
+	db.Close()
+
+	return dbURL(name)
+}
+
+func TestDB(t *testing.T) {
+	db := storertestDB(t)
+	defer db.Close()
+
+	storertest.Run(t, &dbHarness{db: db})
+}
+
+// migrateDown reverts all the migrations of the database
+func migrateDown(t *testing.T, dbURL string) {
+	d, err := bindata.WithInstance(bindata.Resource(database.AssetNames(), database.Asset))
+	require.NoError(t, err)
+
+	m, err := migrate.NewWithSourceInstance("go-bindata", d, dbURL)
+	require.NoError(t, err)
+
+	if err := m.Down(); err != migrate.ErrNoChange {
+		require.NoError(t, err, "Cannot migrate down the DB")
+	}
+
+	_, err = m.Close()
+	require.NoError(t, err)
+}
+
+// columnTypes returns the type of each column of the given table or view
+func columnTypes(t *testing.T, db *sql.DB, table string) map[string]string {
+	rows, err := db.Query(`SELECT attname, format_type(atttypid, atttypmod)
+		FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped`, table)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var name, typ string
+		require.NoError(t, rows.Scan(&name, &typ))
+		types[name] = typ
+	}
+
+	require.NoError(t, rows.Err())
+	return types
+}
+
+// TestDBUnifiedViews saves rows of GitHub and GitLab, and checks the unified
+// views created with their union. The migrations are reverted at the end, with
+// the views in place, and applied again
+func TestDBUnifiedViews(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+
+	dbURL := storertestURL(t)
+	migrateDown(t, dbURL)
+	require.NoError(database.Migrate(dbURL), "Cannot migrate the DB")
+
+	db, err := sql.Open("postgres", dbURL)
+	require.NoError(err)
+	defer db.Close()
+
+	// the down migrations keep the initial tables, with the rows of the
+	// other tests
+	tables := []string{"gitlab_projects_versioned", "gitlab_issues_versioned", "gitlab_merge_requests_versioned", "gitlab_notes_versioned"}
+	for _, kind := range storertest.Kinds {
+		tables = append(tables, fmt.Sprintf("github_%s_versioned", kind))
+	}
+
+	for _, table := range tables {
+		_, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table))
+		require.NoError(err)
+	}
+
+	gh := store.NewDB(db)
+	gh.Version(1)
+	require.NoError(gh.Begin())
+	require.NoError(gh.SaveRepository(ctx, &graphql.RepositoryFields{Name: "repository", NameWithOwner: "acme/repository"}, []string{}))
+	require.NoError(gh.SaveIssue(ctx, "acme", "repository", &graphql.Issue{IssueFields: graphql.IssueFields{Number: 1}}, []string{}, []string{"bug"}))
+	require.NoError(gh.SaveIssueComment(ctx, "acme", "repository", 1, &graphql.IssueComment{Body: "issue comment"}))
+	require.NoError(gh.SavePullRequest(ctx, "acme", "repository", &graphql.PullRequest{PullRequestFields: graphql.PullRequestFields{Number: 2}}, []string{}, []string{}))
+	require.NoError(gh.SavePullRequestComment(ctx, "acme", "repository", 2, &graphql.IssueComment{Body: "PR comment"}))
+	require.NoError(gh.SaveCommitComment(ctx, "acme", "repository", &graphql.CommitComment{Body: "commit comment"}))
+	require.NoError(gh.Commit())
+
+	gl := gitlabstore.NewDB(db)
+	gl.Version(1)
+	require.NoError(gl.Begin())
+	require.NoError(gl.SaveProject(ctx, &gitlabapi.Project{
+		Path:              "project",
+		PathWithNamespace: "group/project",
+		Namespace:         gitlabapi.Namespace{FullPath: "group"},
+		Visibility:        "private",
+	}))
+	require.NoError(gl.SaveIssue(ctx, "group", "project", &gitlabapi.Issue{IID: 1, State: "opened"}))
+	require.NoError(gl.SaveIssueNote(ctx, "group", "project", 1, &gitlabapi.Note{Body: "issue note"}))
+	require.NoError(gl.SaveMergeRequest(ctx, "group", "project", &gitlabapi.MergeRequest{IID: 2, State: "merged"}))
+	require.NoError(gl.SaveMergeRequestNote(ctx, "group", "project", 2, &gitlabapi.Note{Body: "MR note"}))
+	require.NoError(gl.Commit())
+
+	require.NoError(gh.SetActiveVersion(ctx, 1))
+
+	var repositories []string
+	rows, err := db.Query(`SELECT full_name FROM repositories ORDER BY full_name`)
+	require.NoError(err)
+	for rows.Next() {
+		var fullName string
+		require.NoError(rows.Scan(&fullName))
+		repositories = append(repositories, fullName)
+	}
+	require.NoError(rows.Err())
+	rows.Close()
+	require.Equal([]string{"acme/repository", "group/project"}, repositories)
+
+	var count int
+	for view, expected := range map[string]int{"issues": 2, "pull_requests": 2, "comments": 5} {
+		require.NoError(db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, view)).Scan(&count))
+		require.Equal(expected, count, view)
+	}
+
+	// the columns of the providers are UNIONed with the same types
+	for view, expected := range map[string]map[string]string{
+		"repositories": {
+			"owner":     "text",
+			"full_name": "text",
+			"private":   "boolean",
+		},
+		"issues": {
+			"number":     "bigint",
+			"created_at": "timestamp with time zone",
+			"comments":   "bigint",
+			"labels":     "text[]",
+		},
+		"pull_requests": {
+			"number":        "bigint",
+			"merged_at":     "timestamp with time zone",
+			"commits":       "bigint",
+			"changed_files": "bigint",
+		},
+		"comments": {
+			"kind":       "text",
+			"number":     "bigint",
+			"commit_sha": "text",
+			"path":       "text",
+			"position":   "bigint",
+			"created_at": "timestamp with time zone",
+		},
+	} {
+		types := columnTypes(t, db, view)
+		for column, typ := range expected {
+			require.Equal(typ, types[column], "%s.%s", view, column)
+		}
+	}
+
+	// the down migrations drop the unified views before the tables they read
+	migrateDown(t, dbURL)
+	require.NoError(db.QueryRow(`SELECT COUNT(*) FROM pg_matviews WHERE schemaname = 'public'`).Scan(&count))
+	require.Equal(0, count)
+
+	require.NoError(database.Migrate(dbURL), "Cannot migrate the DB")
+}
+
+// saveContributorTwice saves the same contributor in two transactions of the
+// version 1, like two repositories of the same download
+func saveContributorTwice(t *testing.T, s github.Storer) {
+	user := &graphql.UserExtended{UserExtendedFields: graphql.UserExtendedFields{Login: "alice"}}
+	s.Version(1)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.Begin())
+		require.NoError(t, s.SaveContributor(context.TODO(), user))
+		require.NoError(t, s.Commit())
+	}
+}
+
+// TestDBRepeatedVersion checks that the version is not appended again to the
+// rows that already have it
+func TestDBRepeatedVersion(t *testing.T) {
+	db := storertestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM github_contributors_versioned")
+	require.NoError(t, err)
+
+	saveContributorTwice(t, store.NewDB(db))
+
+	var versions pq.Int64Array
+	require.NoError(t, db.QueryRow("SELECT versions FROM github_contributors_versioned").Scan(&versions))
+	require.Equal(t, pq.Int64Array{1}, versions)
+}
+
+// bufferedDBHarness runs the Suite against the DB returned by NewBufferedDB,
+// with a flush every 2 rows to merge the rows both while saving and on Commit
+type bufferedDBHarness struct {
+	dbHarness
+}
+
+func (h *bufferedDBHarness) NewStorer(t *testing.T) github.Storer {
+	h.dbHarness.NewStorer(t)
+	return store.NewBufferedDB(h.db, 2)
+}
+
+func TestBufferedDB(t *testing.T) {
+	db := storertestDB(t)
+	defer db.Close()
+
+	storertest.Run(t, &bufferedDBHarness{dbHarness{db: db}})
+}
+
+// BenchmarkDB compares saving the issue comments one by one with loading them
+// with COPY, each op saves 1000 comments in a transaction
+func BenchmarkDB(b *testing.B) {
+	db := storertestDB(b)
+	defer db.Close()
+
+	storers := []struct {
+		name string
+		new  func() *store.DB
+	}{
+		{"Rows", func() *store.DB { return store.NewDB(db) }},
+		{"Copy", func() *store.DB { return store.NewBufferedDB(db, 500) }},
+	}
+
+	ctx := context.TODO()
+	for _, storer := range storers {
+		b.Run(storer.name, func(b *testing.B) {
+			_, err := db.Exec("DELETE FROM github_issue_comments_versioned")
+			require.NoError(b, err)
+
+			s := storer.new()
+			s.Version(1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, s.Begin())
+				for j := 0; j < 1000; j++ {
+					comment := &graphql.IssueComment{
+						ID:        fmt.Sprintf("IC_%d_%d", i, j),
+						Body:      "a comment\nwith\ttabs",
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now().Format(time.RFC3339),
+					}
+					require.NoError(b, s.SaveIssueComment(ctx, "acme", "repository", i, comment))
+				}
+				require.NoError(b, s.Commit())
+			}
+		})
+	}
+}
+
+// sqliteHarness runs the Suite against store.SQLite, reading the versioned
+// tables and the views like dbHarness
+type sqliteHarness struct {
+	dbHarness
+}
+
+func (h *sqliteHarness) NewStorer(t *testing.T) github.Storer {
+	for _, kind := range storertest.Kinds {
+		_, err := h.db.Exec(fmt.Sprintf("DELETE FROM github_%s_versioned", kind))
+		require.NoError(t, err)
+	}
+
+	return store.NewSQLite(h.db)
+}
+
+func (h *sqliteHarness) Items(t *testing.T, s github.Storer, kind storertest.Kind, version int) []string {
+	return h.query(t, fmt.Sprintf(`SELECT node_id FROM github_%s_versioned
+		WHERE replace(replace(versions, '[', ','), ']', ',') LIKE '%%,' || ? || ',%%'`, kind), version)
+}
+
+func TestSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metadata-sqlite")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "metadata.db")
+	require.NoError(t, sqlite.Migrate(path), "Cannot migrate the DB")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	storertest.Run(t, &sqliteHarness{dbHarness{db: db}})
+
+	// the migrations are applied once
+	require.NoError(t, sqlite.Migrate(path))
+}
+
+func TestSQLiteRepeatedVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metadata-sqlite")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "metadata.db")
+	require.NoError(t, sqlite.Migrate(path))
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	saveContributorTwice(t, store.NewSQLite(db))
+
+	var versions string
+	require.NoError(t, db.QueryRow("SELECT versions FROM github_contributors_versioned").Scan(&versions))
+	require.Equal(t, "[1]", versions)
+}
+
+// TestSQLiteUnifiedViews checks that the unified views of a SQLite database
+// have the GitHub rows of the active version
+func TestSQLiteUnifiedViews(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+
+	dir, err := ioutil.TempDir("", "metadata-sqlite")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "metadata.db")
+	require.NoError(sqlite.Migrate(path))
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(err)
+	defer db.Close()
+
+	s := store.NewSQLite(db)
+	s.Version(1)
+	require.NoError(s.Begin())
+	require.NoError(s.SaveIssue(ctx, "acme", "repository", &graphql.Issue{IssueFields: graphql.IssueFields{Number: 1}}, nil, []string{"bug"}))
+	require.NoError(s.SaveIssueComment(ctx, "acme", "repository", 1, &graphql.IssueComment{Body: "issue comment"}))
+	require.NoError(s.SavePullRequest(ctx, "acme", "repository", &graphql.PullRequest{PullRequestFields: graphql.PullRequestFields{Number: 2}}, nil, nil))
+	require.NoError(s.SavePullRequestComment(ctx, "acme", "repository", 2, &graphql.IssueComment{Body: "PR comment"}))
+	require.NoError(s.SavePullRequestReview(ctx, "acme", "repository", 2, &graphql.PullRequestReview{
+		PullRequestReviewFields: graphql.PullRequestReviewFields{Body: "LGTM", State: "CHANGES_REQUESTED"},
+	}))
+	require.NoError(s.SaveCommitComment(ctx, "acme", "repository", &graphql.CommitComment{Body: "commit comment"}))
+	require.NoError(s.Commit())
+
+	s.Version(2)
+	require.NoError(s.Begin())
+	require.NoError(s.SaveIssue(ctx, "acme", "repository", &graphql.Issue{IssueFields: graphql.IssueFields{Number: 3}}, nil, nil))
+	require.NoError(s.Commit())
+
+	require.NoError(s.SetActiveVersion(ctx, 1))
+
+	var labels string
+	require.NoError(db.QueryRow(`SELECT labels FROM issues WHERE number = 1`).Scan(&labels))
+	require.Equal(`["bug"]`, labels)
+
+	var state string
+	require.NoError(db.QueryRow(`SELECT state FROM pull_request_reviews`).Scan(&state))
+	require.Equal("COMMENTED", state)
+
+	rows, err := db.Query(`SELECT repository_full_name, kind, body FROM comments ORDER BY body`)
+	require.NoError(err)
+	defer rows.Close()
+
+	var comments []string
+	for rows.Next() {
+		var fullName, kind, body string
+		require.NoError(rows.Scan(&fullName, &kind, &body))
+		comments = append(comments, fmt.Sprintf("%s %s %s", fullName, kind, body))
+	}
+
+	require.NoError(rows.Err())
+	require.Equal([]string{
+		"acme/repository pull_request LGTM",
+		"acme/repository pull_request PR comment",
+		"acme/repository commit commit comment",
+		"acme/repository issue issue comment",
+	}, comments)
+
+	// the views are recreated for the new active version
+	require.NoError(s.SetActiveVersion(ctx, 2))
+
+	var number int
+	require.NoError(db.QueryRow(`SELECT number FROM issues`).Scan(&number))
+	require.Equal(3, number)
+}
+
+// jsonlHarness runs the Suite against store.JSONL, each storer writes the
+// files in its own directory
+type jsonlHarness struct {
+	compress bool
+	dirs     []string
+}
+
+func (h *jsonlHarness) NewStorer(t *testing.T) github.Storer {
+	dir, err := ioutil.TempDir("", "metadata-jsonl")
+	require.NoError(t, err)
+	h.dirs = append(h.dirs, dir)
+
+	return store.NewJSONL(dir, h.compress)
+}
+
+func (h *jsonlHarness) Features() storertest.Features {
+	return storertest.Features{Read: true, Transactions: true}
+}
+
+// jsonlFiles are the files with the items of each storertest.Kind, the PR
+// comments are saved along with the issue comments in the other storers
+var jsonlFiles = map[storertest.Kind][]string{
+	storertest.IssueComments:             {"issue_comments", "pull_request_comments"},
+	storertest.PullRequestReviewComments: {"pull_request_review_comments"},
+}
+
+func (h *jsonlHarness) Items(t *testing.T, s github.Storer, kind storertest.Kind, version int) []string {
+	files, ok := jsonlFiles[kind]
+	if !ok {
+		files = []string{string(kind)}
+	}
+
+	// the items saved again, like when downloading again, are written again
+	// in later segments, the readers keep the last line of each item
+	var ids []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		for _, line := range readSegments(t, s.(*store.JSONL), file) {
+			if int(line["version"].(float64)) != version {
+				continue
+			}
+
+			// the item is the object with a node ID
+			for _, v := range line {
+				item, ok := v.(map[string]interface{})
+				if !ok || item["ID"] == nil || seen[item["ID"].(string)] {
+					continue
+				}
+
+				seen[item["ID"].(string)] = true
+				ids = append(ids, item["ID"].(string))
+			}
+		}
+	}
+
+	return ids
+}
+
+func (h *jsonlHarness) ActiveItems(t *testing.T, s github.Storer, kind storertest.Kind) []string {
+	return nil
+}
+
+// readJSONL returns the lines of the file, gzipped when its name ends with
+// .gz, nothing if it does not exist
+func readJSONL(t *testing.T, path string) []map[string]interface{} {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	defer f.Close()
+
+	var r io.Reader = f
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		defer gz.Close()
+		r = gz
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+// readSegments returns the lines of all the segments of the kind
+func readSegments(t *testing.T, s *store.JSONL, kind string) []map[string]interface{} {
+	paths, err := s.Segments(kind)
+	require.NoError(t, err)
+
+	var lines []map[string]interface{}
+	for _, path := range paths {
+		lines = append(lines, readJSONL(t, path)...)
+	}
+
+	return lines
+}
+
+func TestJSONL(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		h := &jsonlHarness{compress: compress}
+		t.Run(fmt.Sprintf("compress=%v", compress), func(t *testing.T) {
+			storertest.Run(t, h)
+		})
+
+		for _, dir := range h.dirs {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+func TestJSONLFiles(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+
+	dir, err := ioutil.TempDir("", "metadata-jsonl")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := store.NewJSONL(dir, true)
+	require.Error(s.SaveContributor(ctx, &graphql.UserExtended{}), "Begin was not called")
+
+	issue := &graphql.Issue{IssueFields: graphql.IssueFields{Number: 1, Title: "first"}}
+	for v := 1; v <= 2; v++ {
+		s.Version(v)
+		require.NoError(s.Begin())
+		require.NoError(s.SaveIssue(ctx, "acme", "repository", issue, []string{"alice"}, nil))
+		require.NoError(s.SaveIssueComment(ctx, "acme", "repository", 1, &graphql.IssueComment{Body: "hello"}))
+		require.NoError(s.Commit())
+	}
+
+	s.Version(3)
+	require.NoError(s.Begin())
+	require.NoError(s.SaveIssue(ctx, "acme", "repository", issue, nil, nil))
+	require.NoError(s.SaveCommitComment(ctx, "acme", "repository", &graphql.CommitComment{}))
+	require.NoError(s.Rollback())
+
+	// only the segments of the committed transactions are left
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(err)
+	require.ElementsMatch([]string{
+		s.Path("issues", 1, 1), s.Path("issue_comments", 1, 1),
+		s.Path("issues", 2, 2), s.Path("issue_comments", 2, 2),
+	}, files)
+	require.Equal(filepath.Join(dir, "issues.2.2.jsonl.gz"), s.Path("issues", 2, 2))
+	hidden, err := filepath.Glob(filepath.Join(dir, ".*"))
+	require.NoError(err)
+	require.Empty(hidden)
+
+	segments, err := s.Segments("issues")
+	require.NoError(err)
+	require.Equal([]string{s.Path("issues", 1, 1), s.Path("issues", 2, 2)}, segments)
+
+	// the next transaction continues the sequence, also in a new JSONL
+	s = store.NewJSONL(dir, true)
+	s.Version(2)
+	require.NoError(s.Begin())
+	require.NoError(s.SaveIssue(ctx, "acme", "repository", issue, []string{"alice"}, nil))
+	require.NoError(s.Commit())
+	segments, err = s.Segments("issues")
+	require.NoError(err)
+	require.Equal([]string{s.Path("issues", 1, 1), s.Path("issues", 2, 2), s.Path("issues", 2, 3)}, segments)
+
+	lines := readSegments(t, s, "issues")
+	versions := []float64{1, 2, 2}
+	require.Len(lines, len(versions))
+	for i, line := range lines {
+		require.Equal(versions[i], line["version"])
+		require.Equal("acme", line["repository_owner"])
+		require.Equal("repository", line["repository_name"])
+		require.Equal([]interface{}{"alice"}, line["assignees"])
+		require.Equal("first", line["issue"].(map[string]interface{})["Title"])
+	}
+
+	lines = readSegments(t, s, "issue_comments")
+	require.Len(lines, 2)
+	require.Equal(float64(1), lines[0]["issue_number"])
+	require.Equal("hello", lines[0]["comment"].(map[string]interface{})["Body"])
+}
+
+// parquetHarness runs the Suite against store.Parquet, each storer writes the
+// files in its own directory. The files are only readable once they are
+// closed, TestParquetFiles reads them
+type parquetHarness struct {
+	dirs []string
+}
+
+func (h *parquetHarness) NewStorer(t *testing.T) github.Storer {
+	dir, err := ioutil.TempDir("", "metadata-parquet")
+	require.NoError(t, err)
+	h.dirs = append(h.dirs, dir)
+
+	return store.NewParquet(dir, 0)
+}
+
+func (h *parquetHarness) Features() storertest.Features {
+	return storertest.Features{}
+}
+
+func (h *parquetHarness) Items(t *testing.T, s github.Storer, kind storertest.Kind, version int) []string {
+	return nil
+}
+
+func (h *parquetHarness) ActiveItems(t *testing.T, s github.Storer, kind storertest.Kind) []string {
+	return nil
+}
+
+func TestParquet(t *testing.T) {
+	h := &parquetHarness{}
+	storertest.Run(t, h)
+
+	for _, dir := range h.dirs {
+		os.RemoveAll(dir)
+	}
+}
+
+// parquetFile is the source.ParquetFile of a local file read by the tests
+type parquetFile struct {
+	*os.File
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.Name()
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetFile{file}, nil
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, fmt.Errorf("the file is read only")
+}
+
+// readParquet returns the reader of the file with the schema of its footer
+func readParquet(t *testing.T, path string) *reader.ParquetReader {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	r, err := reader.NewParquetReader(&parquetFile{f}, nil, 1)
+	require.NoError(t, err)
+	return r
+}
+
+// parquetSchema returns the columns of the file schema, with their type,
+// converted type and repetition
+func parquetSchema(r *reader.ParquetReader) []string {
+	var schema []string
+	for i, e := range r.SchemaHandler.SchemaElements[1:] {
+		col := r.SchemaHandler.Infos[i+1].ExName
+		if e.Type != nil {
+			col += " " + e.Type.String()
+		}
+		if e.ConvertedType != nil {
+			col += " " + e.ConvertedType.String()
+		}
+
+		schema = append(schema, col+" "+e.RepetitionType.String())
+	}
+
+	return schema
+}
+
+// parquetRows returns the rows of the file, with a key for each column
+func parquetRows(t *testing.T, r *reader.ParquetReader) []map[string]interface{} {
+	rows, err := r.ReadByNumber(int(r.GetNumRows()))
+	require.NoError(t, err)
+
+	content, err := json.Marshal(rows)
+	require.NoError(t, err)
+
+	var maps []map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &maps))
+	return maps
+}
+
+func TestParquetFiles(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+
+	dir, err := ioutil.TempDir("", "metadata-parquet")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := store.NewParquet(dir, 2)
+	require.Error(s.SaveContributor(ctx, &graphql.UserExtended{}), "Begin was not called")
+
+	createdAt := time.Date(2019, 10, 1, 12, 30, 0, int(250*time.Millisecond), time.UTC)
+	for v := 1; v <= 2; v++ {
+		s.Version(v)
+		require.NoError(s.Begin())
+		for i := 1; i <= 5; i++ {
+			issue := &graphql.Issue{IssueFields: graphql.IssueFields{
+				Number:    i,
+				Title:     fmt.Sprintf("issue %d", i),
+				CreatedAt: createdAt,
+			}}
+			require.NoError(s.SaveIssue(ctx, "acme", "repository", issue, []string{"alice", "bob"}, nil))
+		}
+		require.NoError(s.Commit())
+	}
+
+	s.Version(3)
+	require.NoError(s.Begin())
+	require.NoError(s.SaveCommitComment(ctx, "acme", "repository", &graphql.CommitComment{}))
+	require.NoError(s.Rollback())
+
+	// the files are written once they are closed, the rolled back items are
+	// not written
+	files, err := filepath.Glob(filepath.Join(dir, "*.parquet"))
+	require.NoError(err)
+	require.Empty(files)
+
+	require.NoError(s.Close())
+	files, err = filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(err)
+	require.ElementsMatch([]string{s.Path("issues", 1), s.Path("issues", 2)}, files)
+	hidden, err := filepath.Glob(filepath.Join(dir, ".*"))
+	require.NoError(err)
+	require.Empty(hidden)
+
+	r := readParquet(t, s.Path("issues", 1))
+	defer r.ReadStop()
+
+	require.Equal([]string{
+		"assignees LIST REQUIRED",
+		"list REPEATED",
+		"element BYTE_ARRAY UTF8 REQUIRED",
+		"body BYTE_ARRAY UTF8 REQUIRED",
+		"closed_at INT64 TIMESTAMP_MILLIS OPTIONAL",
+		"closed_by_id INT64 REQUIRED",
+		"closed_by_login BYTE_ARRAY UTF8 REQUIRED",
+		"comments INT64 REQUIRED",
+		"created_at INT64 TIMESTAMP_MILLIS OPTIONAL",
+		"htmlurl BYTE_ARRAY UTF8 REQUIRED",
+		"id INT64 REQUIRED",
+		"labels LIST REQUIRED",
+		"list REPEATED",
+		"element BYTE_ARRAY UTF8 REQUIRED",
+		"locked BOOLEAN REQUIRED",
+		"milestone_id BYTE_ARRAY UTF8 REQUIRED",
+		"milestone_title BYTE_ARRAY UTF8 REQUIRED",
+		"node_id BYTE_ARRAY UTF8 REQUIRED",
+		"number INT64 REQUIRED",
+		"repository_name BYTE_ARRAY UTF8 REQUIRED",
+		"repository_owner BYTE_ARRAY UTF8 REQUIRED",
+		"state BYTE_ARRAY UTF8 REQUIRED",
+		"title BYTE_ARRAY UTF8 REQUIRED",
+		"updated_at INT64 TIMESTAMP_MILLIS OPTIONAL",
+		"user_id INT64 REQUIRED",
+		"user_login BYTE_ARRAY UTF8 REQUIRED",
+	}, parquetSchema(r))
+
+	// the rows are written in row groups of 2 rows
+	require.Len(r.Footer.RowGroups, 3)
+
+	rows := parquetRows(t, r)
+	require.Len(rows, 5)
+	for i, row := range rows {
+		require.Equal(float64(i+1), row["Number"])
+		require.Equal(fmt.Sprintf("issue %d", i+1), row["Title"])
+		require.Equal("acme", row["Repository_owner"])
+		require.Equal([]interface{}{"alice", "bob"}, row["Assignees"])
+		require.Equal([]interface{}{}, row["Labels"])
+		require.Equal(float64(createdAt.UnixNano()/int64(time.Millisecond)), row["Created_at"])
+		require.Nil(row["Closed_at"])
+	}
+
+	require.NoError(s.Cleanup(ctx, 2))
+	files, err = filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(err)
+	require.Equal([]string{s.Path("issues", 2)}, files)
+}
+
+// TestParquetKinds checks that the columns of the files of each kind are the
+// ones of its table
+func TestParquetKinds(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+
+	dir, err := ioutil.TempDir("", "metadata-parquet")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := store.NewParquet(dir, 0)
+	s.Version(1)
+	require.NoError(s.Begin())
+	require.NoError(s.SaveOrganization(ctx, &graphql.Organization{}))
+	require.NoError(s.SaveUser(ctx, 1, "acme", &graphql.UserExtended{}))
+	require.NoError(s.SaveContributor(ctx, &graphql.UserExtended{}))
+	require.NoError(s.SaveRepository(ctx, &graphql.RepositoryFields{}, []string{"go"}))
+	require.NoError(s.SaveIssue(ctx, "acme", "repository", &graphql.Issue{}, nil, nil))
+	require.NoError(s.SaveIssueComment(ctx, "acme", "repository", 1, &graphql.IssueComment{}))
+	require.NoError(s.SavePullRequest(ctx, "acme", "repository", &graphql.PullRequest{}, nil, nil))
+	require.NoError(s.SavePullRequestComment(ctx, "acme", "repository", 2, &graphql.IssueComment{}))
+	require.NoError(s.SavePullRequestReview(ctx, "acme", "repository", 2, &graphql.PullRequestReview{}))
+	require.NoError(s.SavePullRequestReviewComment(ctx, "acme", "repository", 2, 3, &graphql.PullRequestReviewComment{}))
+	require.NoError(s.SaveCommitComment(ctx, "acme", "repository", &graphql.CommitComment{}))
+	require.NoError(s.SaveProject(ctx, "acme", "repository", &graphql.Project{}))
+	require.NoError(s.SaveProjectColumn(ctx, "project", &graphql.ProjectColumn{}))
+	require.NoError(s.SaveProjectCard(ctx, "project", "column", &graphql.ProjectCard{}))
+	require.NoError(s.SaveProjectV2(ctx, "acme", "repository", &graphql.ProjectV2{}))
+	require.NoError(s.SaveProjectV2Item(ctx, "project", &graphql.ProjectV2Item{}, map[string]string{"Status": "Done"}))
+	require.NoError(s.Commit())
+	require.NoError(s.Close())
+
+	path := filepath.Join(dir, "metadata.db")
+	require.NoError(sqlite.Migrate(path))
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(err)
+	defer db.Close()
+
+	// the PR comments are saved along with the issue comments
+	for _, kind := range store.ParquetKinds {
+		r := readParquet(t, s.Path(kind, 1))
+		if kind == "issue_comments" {
+			require.Equal(int64(2), r.GetNumRows(), kind)
+		} else {
+			require.Equal(int64(1), r.GetNumRows(), kind)
+		}
+
+		var cols []string
+		for _, col := range parquetSchema(r) {
+			name := strings.Fields(col)[0]
+			if name != "list" && name != "element" {
+				cols = append(cols, name)
+			}
+		}
+		require.Equal(tableColumns(t, db, kind), cols, kind)
+
+		rows := parquetRows(t, r)
+		if kind == "project_v2_items" {
+			require.Equal(`{"Status":"Done"}`, rows[0]["Field_values"])
+		}
+		r.ReadStop()
+	}
+}
+
+// tableColumns returns the columns of the versioned table of the kind in a
+// SQLite database, without sum256 and versions
+func tableColumns(t *testing.T, db *sql.DB, kind string) []string {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)
+		WHERE name NOT IN ('sum256', 'versions')
+		ORDER BY name`, "github_"+kind+"_versioned")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var col string
+		require.NoError(t, rows.Scan(&col))
+		cols = append(cols, col)
+	}
+
+	require.NoError(t, rows.Err())
+	return cols
+}
@@ -0,0 +1,480 @@
+package storertest
+
+import (
+	"context"
+	"time"
+
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/github/graphql"
+)
+
+// entry is an item saved by the Suite
+type entry struct {
+	kind Kind
+	id   string
+	// removed entries are not saved in the second version
+	removed bool
+	// save saves the item, with some of its fields changed when updated is
+	// true. The node ID does not change
+	save func(ctx context.Context, s github.Storer, updated bool) error
+}
+
+// fixture is a list of entries saved in the order of the downloaders, the
+// parent items first
+type fixture struct {
+	name    string
+	entries []entry
+}
+
+var (
+	createdAt = time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt = createdAt.Add(24 * time.Hour)
+	closedAt  = createdAt.Add(48 * time.Hour)
+)
+
+func description(updated bool) string {
+	if updated {
+		return "updated description"
+	}
+
+	return "description"
+}
+
+func actor(login string, id int) graphql.Actor {
+	a := graphql.Actor{Login: login, Typename: "User"}
+	a.User = graphql.User{DatabaseID: id, ID: "U_" + login, Login: login}
+	return a
+}
+
+func user(id int, login string, complete bool) *graphql.UserExtended {
+	u := &graphql.UserExtended{}
+	u.DatabaseID = id
+	u.ID = "U_" + login
+	u.Login = login
+	u.CreatedAt = createdAt
+	u.UpdatedAt = updatedAt
+	if complete {
+		u.AvatarURL = "https://avatars.githubusercontent.com/u/1"
+		u.Bio = "Software engineer"
+		u.Company = "acme"
+		u.Location = "Madrid"
+		u.Name = "The first user"
+		u.URL = "https://github.com/" + login
+		u.IsHireable = true
+		u.Followers.TotalCount = 10
+		u.Following.TotalCount = 5
+		u.PublicRepos.TotalCount = 3
+		u.Email = login + "@acme.example.com"
+		u.PublicGists = 2
+	}
+
+	return u
+}
+
+func organizationFixture() fixture {
+	return fixture{name: "Organization", entries: []entry{{
+		kind: Organizations,
+		id:   "O_acme",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			org := &graphql.Organization{}
+			org.DatabaseID = 1
+			org.ID = "O_acme"
+			org.Login = "acme"
+			org.Name = "Acme"
+			org.Description = description(updated)
+			org.Email = "hello@acme.example.com"
+			org.URL = "https://github.com/acme"
+			org.CreatedAt = createdAt
+			org.UpdatedAt = updatedAt.Format(time.RFC3339)
+			org.PublicRepos.TotalCount = 2
+			return s.SaveOrganization(ctx, org)
+		},
+	}, {
+		kind: Users,
+		id:   "U_alice",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SaveUser(ctx, 1, "acme", user(10, "alice", true))
+		},
+	}, {
+		kind:    Users,
+		id:      "U_bob",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SaveUser(ctx, 1, "acme", user(11, "bob", false))
+		},
+	}, {
+		kind: Projects,
+		id:   "PRO_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			p := &graphql.Project{}
+			p.DatabaseID = 100
+			p.ID = "PRO_1"
+			p.Number = 1
+			p.Name = "Roadmap"
+			p.Body = description(updated)
+			p.State = "OPEN"
+			if updated {
+				p.Closed = true
+				p.ClosedAt = &closedAt
+				p.State = "CLOSED"
+			}
+			p.Creator.Login = "alice"
+			p.URL = "https://github.com/orgs/acme/projects/1"
+			p.CreatedAt = createdAt
+			p.UpdatedAt = updatedAt
+			return s.SaveProject(ctx, "acme", "", p)
+		},
+	}, {
+		kind: ProjectColumns,
+		id:   "PC_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			c := &graphql.ProjectColumn{}
+			c.DatabaseID = 200
+			c.ID = "PC_1"
+			c.Name = "To do"
+			c.Purpose = "TODO"
+			c.URL = "https://github.com/orgs/acme/projects/1#column-200"
+			c.CreatedAt = createdAt
+			c.UpdatedAt = updatedAt
+			return s.SaveProjectColumn(ctx, "PRO_1", c)
+		},
+	}, {
+		kind: ProjectCards,
+		id:   "PCA_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			card := &graphql.ProjectCard{
+				DatabaseID: 300,
+				ID:         "PCA_1",
+				Note:       "a note",
+				State:      "NOTE_ONLY",
+				URL:        "https://github.com/orgs/acme/projects/1#card-300",
+				CreatedAt:  createdAt,
+				UpdatedAt:  updatedAt,
+			}
+			card.Creator.Login = "alice"
+			return s.SaveProjectCard(ctx, "PRO_1", "PC_1", card)
+		},
+	}, {
+		kind:    ProjectCards,
+		id:      "PCA_2",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			card := &graphql.ProjectCard{
+				DatabaseID: 301,
+				ID:         "PCA_2",
+				State:      "CONTENT_ONLY",
+				IsArchived: true,
+				URL:        "https://github.com/orgs/acme/projects/1#card-301",
+				CreatedAt:  createdAt,
+				UpdatedAt:  updatedAt,
+			}
+			card.Creator.Login = "bob"
+			card.Content.Typename = "Issue"
+			card.Content.Issue.ID = "I_1"
+			card.Content.Issue.Number = 1
+			card.Content.Issue.Title = "An issue"
+			card.Content.Issue.Repository.Name = "repository"
+			card.Content.Issue.Repository.Owner.Login = "acme"
+			return s.SaveProjectCard(ctx, "PRO_1", "PC_1", card)
+		},
+	}, {
+		kind: ProjectsV2,
+		id:   "PVT_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			p := &graphql.ProjectV2{}
+			p.ID = "PVT_1"
+			p.Number = 2
+			p.Title = "Planning"
+			p.ShortDescription = description(updated)
+			p.Public = true
+			p.Creator.Login = "alice"
+			p.URL = "https://github.com/orgs/acme/projects/2"
+			p.CreatedAt = createdAt
+			p.UpdatedAt = updatedAt
+			return s.SaveProjectV2(ctx, "acme", "", p)
+		},
+	}, {
+		kind: ProjectV2Items,
+		id:   "PVTI_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			item := &graphql.ProjectV2Item{}
+			item.ID = "PVTI_1"
+			item.Type = "DRAFT_ISSUE"
+			item.Content.Typename = "DraftIssue"
+			item.Content.DraftIssue.Title = "A draft"
+			item.Creator.Login = "alice"
+			item.CreatedAt = createdAt
+			item.UpdatedAt = updatedAt
+			return s.SaveProjectV2Item(ctx, "PVT_1", item, map[string]string{})
+		},
+	}, {
+		kind:    ProjectV2Items,
+		id:      "PVTI_2",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			item := &graphql.ProjectV2Item{}
+			item.ID = "PVTI_2"
+			item.Type = "PULL_REQUEST"
+			item.Content.Typename = "PullRequest"
+			item.Content.PullRequest.ID = "PR_2"
+			item.Content.PullRequest.Number = 2
+			item.Content.PullRequest.Title = "A PR"
+			item.Content.PullRequest.Repository.Name = "repository"
+			item.Content.PullRequest.Repository.Owner.Login = "acme"
+			item.Creator.Login = "bob"
+			item.CreatedAt = createdAt
+			item.UpdatedAt = updatedAt
+			return s.SaveProjectV2Item(ctx, "PVT_1", item, map[string]string{"Status": "Done", "Estimate": "3"})
+		},
+	}}}
+}
+
+func repositoryFixture() fixture {
+	return fixture{name: "Repository", entries: []entry{{
+		kind: Repositories,
+		id:   "R_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			r := &graphql.RepositoryFields{}
+			r.DatabaseID = 1000
+			r.ID = "R_1"
+			r.Name = "repository"
+			r.NameWithOwner = "acme/repository"
+			r.Description = description(updated)
+			r.DefaultBranchRef.Name = "master"
+			r.PrimaryLanguage.Name = "Go"
+			r.HasIssuesEnabled = true
+			r.MergeCommitAllowed = true
+			r.URL = "https://github.com/acme/repository"
+			r.SSHURL = "git@github.com:acme/repository.git"
+			r.Owner.Login = "acme"
+			r.Owner.Typename = "Organization"
+			r.Owner.Organization.DatabaseID = 1
+			r.OpenIssues.TotalCount = 1
+			r.CreatedAt = createdAt
+			r.PushedAt = updatedAt
+			r.UpdatedAt = updatedAt
+			return s.SaveRepository(ctx, r, []string{"go", "metadata"})
+		},
+	}, {
+		kind:    Issues,
+		id:      "I_1",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			issue := &graphql.Issue{}
+			issue.DatabaseID = 2000
+			issue.ID = "I_1"
+			issue.Number = 1
+			issue.Title = "An open issue"
+			issue.State = "OPEN"
+			issue.URL = "https://github.com/acme/repository/issues/1"
+			issue.Author = actor("alice", 10)
+			issue.CreatedAt = createdAt
+			issue.UpdatedAt = updatedAt
+			return s.SaveIssue(ctx, "acme", "repository", issue, []string{}, []string{})
+		},
+	}, {
+		kind: Issues,
+		id:   "I_2",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			issue := &graphql.Issue{}
+			issue.DatabaseID = 2001
+			issue.ID = "I_2"
+			issue.Number = 2
+			issue.Title = "A closed issue"
+			if updated {
+				issue.Title = "A closed issue, renamed"
+			}
+			issue.Body = "Body of the issue"
+			issue.State = "CLOSED"
+			issue.Locked = true
+			issue.URL = "https://github.com/acme/repository/issues/2"
+			issue.Author = actor("bob", 11)
+			issue.Milestone.ID = "MI_1"
+			issue.Milestone.Title = "v1.0.0"
+			issue.Comments.TotalCount = 1
+			issue.ClosedAt = &closedAt
+			issue.ClosedBy.Nodes = make([]struct {
+				ClosedEvent struct {
+					Actor graphql.Actor
+				} `graphql:"... on ClosedEvent"`
+			}, 1)
+			issue.ClosedBy.Nodes[0].ClosedEvent.Actor = actor("alice", 10)
+			issue.CreatedAt = createdAt
+			issue.UpdatedAt = updatedAt
+			return s.SaveIssue(ctx, "acme", "repository", issue, []string{"alice", "bob"}, []string{"bug"})
+		},
+	}, {
+		kind:    IssueComments,
+		id:      "IC_1",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SaveIssueComment(ctx, "acme", "repository", 1, comment(3000, "IC_1", "bob"))
+		},
+	}, {
+		kind: IssueComments,
+		id:   "IC_2",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SaveIssueComment(ctx, "acme", "repository", 2, comment(3001, "IC_2", "alice"))
+		},
+	}, {
+		kind: PullRequests,
+		id:   "PR_3",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			pr := pullRequest(4000, "PR_3", 3)
+			pr.State = "MERGED"
+			pr.Merged = true
+			pr.MergedAt = &closedAt
+			pr.ClosedAt = &closedAt
+			pr.MergedBy = actor("alice", 10)
+			pr.MergeCommit.Oid = "8d2b2ae2c56ba5ea0c5bec8e4a8f39a7e0e3ad35"
+			pr.Milestone.ID = "MI_1"
+			pr.Milestone.Title = "v1.0.0"
+			return s.SavePullRequest(ctx, "acme", "repository", pr, []string{"bob"}, []string{"enhancement", "documentation"})
+		},
+	}, {
+		kind:    PullRequests,
+		id:      "PR_4",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			pr := pullRequest(4001, "PR_4", 4)
+			pr.State = "OPEN"
+			pr.Mergeable = "UNKNOWN"
+			return s.SavePullRequest(ctx, "acme", "repository", pr, []string{}, []string{})
+		},
+	}, {
+		// the PR comments are issue comments
+		kind: IssueComments,
+		id:   "IC_3",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SavePullRequestComment(ctx, "acme", "repository", 3, comment(3002, "IC_3", "bob"))
+		},
+	}, {
+		kind: PullRequestReviews,
+		id:   "PRR_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			review := &graphql.PullRequestReview{}
+			review.DatabaseID = 5000
+			review.ID = "PRR_1"
+			review.Body = description(updated)
+			review.State = "APPROVED"
+			review.Commit.Oid = "a1c5fd1ca1e5b8e2f9e5f0d5ac4a3bce0b6fd1c5"
+			review.URL = "https://github.com/acme/repository/pull/3#pullrequestreview-5000"
+			review.Author = actor("alice", 10)
+			review.SubmittedAt = createdAt
+			return s.SavePullRequestReview(ctx, "acme", "repository", 3, review)
+		},
+	}, {
+		kind: PullRequestReviewComments,
+		id:   "PRRC_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SavePullRequestReviewComment(ctx, "acme", "repository", 3, 5000, reviewComment(6000, "PRRC_1", 0))
+		},
+	}, {
+		kind:    PullRequestReviewComments,
+		id:      "PRRC_2",
+		removed: true,
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SavePullRequestReviewComment(ctx, "acme", "repository", 3, 5000, reviewComment(6001, "PRRC_2", 6000))
+		},
+	}, {
+		kind: CommitComments,
+		id:   "CC_1",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			c := &graphql.CommitComment{
+				DatabaseID:        7000,
+				ID:                "CC_1",
+				Body:              description(updated),
+				AuthorAssociation: "MEMBER",
+				URL:               "https://github.com/acme/repository/commit/a1c5fd1#commitcomment-7000",
+				CreatedAt:         createdAt,
+				UpdatedAt:         updatedAt,
+				Author:            actor("bob", 11),
+			}
+			c.Commit.Oid = "a1c5fd1ca1e5b8e2f9e5f0d5ac4a3bce0b6fd1c5"
+			path, position := "main.go", 4
+			c.Path, c.Position = &path, &position
+			return s.SaveCommitComment(ctx, "acme", "repository", c)
+		},
+	}, {
+		kind: Projects,
+		id:   "PRO_2",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			p := &graphql.Project{}
+			p.DatabaseID = 101
+			p.ID = "PRO_2"
+			p.Number = 1
+			p.Name = "Repository roadmap"
+			p.State = "OPEN"
+			p.Creator.Login = "bob"
+			p.URL = "https://github.com/acme/repository/projects/1"
+			p.CreatedAt = createdAt
+			p.UpdatedAt = updatedAt
+			return s.SaveProject(ctx, "acme", "repository", p)
+		},
+	}, {
+		kind: Contributors,
+		id:   "U_carol",
+		save: func(ctx context.Context, s github.Storer, updated bool) error {
+			return s.SaveContributor(ctx, user(12, "carol", true))
+		},
+	}}}
+}
+
+func comment(id int, nodeID string, author string) *graphql.IssueComment {
+	return &graphql.IssueComment{
+		AuthorAssociation: "MEMBER",
+		Body:              "a comment",
+		CreatedAt:         createdAt,
+		URL:               "https://github.com/acme/repository/issues/1#issuecomment-" + nodeID,
+		DatabaseID:        id,
+		ID:                nodeID,
+		UpdatedAt:         updatedAt.Format(time.RFC3339),
+		Author:            actor(author, 10),
+	}
+}
+
+func pullRequest(id int, nodeID string, number int) *graphql.PullRequest {
+	pr := &graphql.PullRequest{}
+	pr.DatabaseID = id
+	pr.ID = nodeID
+	pr.Number = number
+	pr.Title = "A PR"
+	pr.Body = "Body of the PR"
+	pr.AuthorAssociation = "MEMBER"
+	pr.Additions = 10
+	pr.Deletions = 2
+	pr.ChangedFiles = 1
+	pr.Commits.TotalCount = 1
+	pr.BaseRef.Name = "master"
+	pr.BaseRef.Repository.Name = "repository"
+	pr.BaseRef.Repository.Owner.Login = "acme"
+	pr.BaseRef.Target.Oid = "8fb9c8ee30d4ea0a1b2e7d4f9a0c7be2d3e1f0a9"
+	pr.HeadRef.Name = "feature"
+	pr.HeadRef.Repository.Name = "repository"
+	pr.HeadRef.Repository.Owner.Login = "acme"
+	pr.HeadRef.Target.Oid = "a1c5fd1ca1e5b8e2f9e5f0d5ac4a3bce0b6fd1c5"
+	pr.URL = "https://github.com/acme/repository/pull/" + nodeID
+	pr.Author = actor("bob", 11)
+	pr.CreatedAt = createdAt
+	pr.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return pr
+}
+
+func reviewComment(id int, nodeID string, inReplyTo int) *graphql.PullRequestReviewComment {
+	c := &graphql.PullRequestReviewComment{InReplyTo: inReplyTo}
+	c.DatabaseID = id
+	c.ID = nodeID
+	c.Body = "a review comment"
+	c.AuthorAssociation = "MEMBER"
+	c.Commit.Oid = "a1c5fd1ca1e5b8e2f9e5f0d5ac4a3bce0b6fd1c5"
+	c.OriginalCommit.Oid = "a1c5fd1ca1e5b8e2f9e5f0d5ac4a3bce0b6fd1c5"
+	c.DiffHunk = "@@ -1,3 +1,4 @@"
+	c.Path = "main.go"
+	c.Position = 3
+	c.OriginalPosition = 3
+	c.URL = "https://github.com/acme/repository/pull/3#discussion_r" + nodeID
+	c.Author = actor("alice", 10)
+	c.CreatedAt = createdAt
+	c.UpdatedAt = updatedAt
+	return c
+}
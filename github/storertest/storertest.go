@@ -0,0 +1,250 @@
+// Package storertest provides a conformance test suite for the
+// implementations of github.Storer.
+//
+// The Suite saves every kind of item, with and without their optional fields,
+// and checks that the Storer accepts them. The Storers that can read back
+// what they saved are also checked for the versions of the items,
+// SetActiveVersion, Cleanup, Rollback and the repeated saves of the same items.
+//
+// A Storer runs the Suite from its tests with a Harness:
+//
+//	func TestStorer(t *testing.T) {
+//		storertest.Run(t, &harness{})
+//	}
+package storertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Kind is a kind of saved item. The values are the names of the tables of
+// store.DB, without the github_ prefix and the _versioned suffix
+type Kind string
+
+const (
+	Organizations  Kind = "organizations"
+	Users          Kind = "users"
+	Repositories   Kind = "repositories"
+	Issues         Kind = "issues"
+	IssueComments  Kind = "issue_comments"
+	PullRequests   Kind = "pull_requests"
+	Projects       Kind = "projects"
+	ProjectColumns Kind = "project_columns"
+	ProjectCards   Kind = "project_cards"
+	ProjectsV2     Kind = "projects_v2"
+	ProjectV2Items Kind = "project_v2_items"
+	CommitComments Kind = "commit_comments"
+	Contributors   Kind = "contributors"
+	// PullRequestReviews are saved with SavePullRequestReview
+	PullRequestReviews Kind = "pull_request_reviews"
+	// PullRequestReviewComments are saved with SavePullRequestReviewComment.
+	// The comments saved with SavePullRequestComment are IssueComments
+	PullRequestReviewComments Kind = "pull_request_comments"
+)
+
+// Kinds are all the kinds of saved items
+var Kinds = []Kind{
+	Organizations, Users, Repositories, Issues, IssueComments, PullRequests,
+	PullRequestReviews, PullRequestReviewComments, Projects, ProjectColumns,
+	ProjectCards, ProjectsV2, ProjectV2Items, CommitComments, Contributors,
+}
+
+// Features are the optional behaviors of a Storer checked by the Suite
+type Features struct {
+	// Read is set when Harness.Items returns the saved items
+	Read bool
+	// Versions is set when the items are kept for each version: Harness.Items
+	// only returns the ones of the given version, Harness.ActiveItems the ones
+	// of the version given to SetActiveVersion, and Cleanup removes the items
+	// that are not part of the current version. It requires Read
+	Versions bool
+	// Transactions is set when Rollback discards the items saved since Begin.
+	// It requires Read
+	Transactions bool
+}
+
+// Harness gives the Suite access to a Storer implementation
+type Harness interface {
+	// NewStorer returns a Storer without saved items
+	NewStorer(t *testing.T) github.Storer
+	// Features returns the optional behaviors implemented by the Storers
+	Features() Features
+	// Items returns the node IDs of the items of the given kind saved in the
+	// Storer for the given version, once for each stored copy. It is only
+	// called with the Read feature
+	Items(t *testing.T, s github.Storer, kind Kind, version int) []string
+	// ActiveItems returns the node IDs of the items of the given kind of the
+	// active version. It is only called with the Versions feature
+	ActiveItems(t *testing.T, s github.Storer, kind Kind) []string
+}
+
+// Run runs the Suite against the Storers returned by the Harness, with a
+// subtest for each check
+func Run(t *testing.T, h Harness) {
+	features := h.Features()
+	for _, f := range []fixture{organizationFixture(), repositoryFixture()} {
+		f := f
+		t.Run(f.name, func(t *testing.T) {
+			t.Run("Save", func(t *testing.T) { testSave(t, h, f) })
+			if !features.Read {
+				return
+			}
+
+			t.Run("RepeatedSave", func(t *testing.T) { testRepeatedSave(t, h, f) })
+			if features.Versions {
+				t.Run("Versions", func(t *testing.T) { testVersions(t, h, f) })
+				t.Run("SetActiveVersion", func(t *testing.T) { testSetActiveVersion(t, h, f) })
+				t.Run("Cleanup", func(t *testing.T) { testCleanup(t, h, f) })
+			}
+
+			if features.Transactions {
+				t.Run("Rollback", func(t *testing.T) { testRollback(t, h, f) })
+			}
+		})
+	}
+}
+
+func testSave(t *testing.T, h Harness, f fixture) {
+	s := h.NewStorer(t)
+	save(t, s, f.entries, 1, false)
+
+	if h.Features().Read {
+		requireItems(t, h, s, 1, ids(f.entries))
+	}
+}
+
+// testRepeatedSave checks that saving the same items again in the same
+// version, like downloading again, does not duplicate them
+func testRepeatedSave(t *testing.T, h Harness, f fixture) {
+	s := h.NewStorer(t)
+	save(t, s, f.entries, 1, false)
+	save(t, s, f.entries, 1, false)
+
+	requireItems(t, h, s, 1, ids(f.entries))
+}
+
+// testVersions checks that the items of a version are kept when the next one
+// is saved without some of them, and with others updated
+func testVersions(t *testing.T, h Harness, f fixture) {
+	s := h.NewStorer(t)
+	save(t, s, f.entries, 1, false)
+	save(t, s, kept(f.entries), 2, true)
+
+	requireItems(t, h, s, 1, ids(f.entries))
+	requireItems(t, h, s, 2, ids(kept(f.entries)))
+	requireItems(t, h, s, 3, nil)
+}
+
+func testSetActiveVersion(t *testing.T, h Harness, f fixture) {
+	ctx := context.TODO()
+	s := h.NewStorer(t)
+	save(t, s, f.entries, 1, false)
+	save(t, s, kept(f.entries), 2, true)
+
+	require.NoError(t, s.SetActiveVersion(ctx, 1))
+	requireActiveItems(t, h, s, ids(f.entries))
+
+	require.NoError(t, s.SetActiveVersion(ctx, 2))
+	requireActiveItems(t, h, s, ids(kept(f.entries)))
+}
+
+// testCleanup checks that Cleanup removes the items that are not part of the
+// current version, and keeps the active ones
+func testCleanup(t *testing.T, h Harness, f fixture) {
+	ctx := context.TODO()
+	s := h.NewStorer(t)
+	save(t, s, f.entries, 1, false)
+	save(t, s, kept(f.entries), 2, true)
+	require.NoError(t, s.SetActiveVersion(ctx, 2))
+
+	require.NoError(t, s.Cleanup(ctx, 2))
+	requireItems(t, h, s, 1, nil)
+	requireItems(t, h, s, 2, ids(kept(f.entries)))
+	requireActiveItems(t, h, s, ids(kept(f.entries)))
+
+	// the next version is saved after the cleanup
+	save(t, s, f.entries, 3, false)
+	requireItems(t, h, s, 2, ids(kept(f.entries)))
+	requireItems(t, h, s, 3, ids(f.entries))
+}
+
+// testRollback checks that the items saved in a rolled back transaction are
+// discarded, also when they were already saved in a previous version
+func testRollback(t *testing.T, h Harness, f fixture) {
+	ctx := context.TODO()
+	s := h.NewStorer(t)
+
+	s.Version(1)
+	require.NoError(t, s.Begin())
+	for _, e := range f.entries {
+		require.NoError(t, e.save(ctx, s, false), "%s %s", e.kind, e.id)
+	}
+	require.NoError(t, s.Rollback())
+	requireItems(t, h, s, 1, nil)
+
+	save(t, s, f.entries, 1, false)
+
+	s.Version(2)
+	require.NoError(t, s.Begin())
+	for _, e := range f.entries {
+		require.NoError(t, e.save(ctx, s, true), "%s %s", e.kind, e.id)
+	}
+	require.NoError(t, s.Rollback())
+
+	requireItems(t, h, s, 1, ids(f.entries))
+	requireItems(t, h, s, 2, nil)
+}
+
+// save saves the entries in a transaction for the given version
+func save(t *testing.T, s github.Storer, entries []entry, version int, updated bool) {
+	ctx := context.TODO()
+
+	s.Version(version)
+	require.NoError(t, s.Begin())
+	for _, e := range entries {
+		require.NoError(t, e.save(ctx, s, updated), "%s %s", e.kind, e.id)
+	}
+	require.NoError(t, s.Commit())
+}
+
+// kept returns the entries saved in the second version
+func kept(entries []entry) []entry {
+	var list []entry
+	for _, e := range entries {
+		if !e.removed {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
+
+func ids(entries []entry) map[Kind][]string {
+	m := make(map[Kind][]string)
+	for _, e := range entries {
+		m[e.kind] = append(m[e.kind], e.id)
+	}
+
+	return m
+}
+
+func requireItems(t *testing.T, h Harness, s github.Storer, version int, expected map[Kind][]string) {
+	t.Helper()
+
+	for _, kind := range Kinds {
+		require.ElementsMatch(t, expected[kind], h.Items(t, s, kind, version), "%s in version %d", kind, version)
+	}
+}
+
+func requireActiveItems(t *testing.T, h Harness, s github.Storer, expected map[Kind][]string) {
+	t.Helper()
+
+	for _, kind := range Kinds {
+		require.ElementsMatch(t, expected[kind], h.ActiveItems(t, s, kind), "active %s", kind)
+	}
+}
@@ -0,0 +1,98 @@
+package storertest
+
+import (
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/github"
+	"github.com/src-d/metadata-retrieval/testutils"
+)
+
+// memoryHarness runs the Suite against testutils.Memory, that keeps the last
+// downloaded items without versions
+type memoryHarness struct{}
+
+func (memoryHarness) NewStorer(t *testing.T) github.Storer {
+	return &testutils.Memory{}
+}
+
+func (memoryHarness) Features() Features {
+	return Features{Read: true}
+}
+
+func (memoryHarness) Items(t *testing.T, s github.Storer, kind Kind, version int) []string {
+	m := s.(*testutils.Memory)
+
+	var ids []string
+	switch kind {
+	case Organizations:
+		if m.Organization != nil {
+			ids = append(ids, m.Organization.ID)
+		}
+	case Users:
+		for _, u := range m.Users {
+			ids = append(ids, u.ID)
+		}
+	case Repositories:
+		if m.Repository != nil {
+			ids = append(ids, m.Repository.ID)
+		}
+	case Issues:
+		for _, i := range m.Issues {
+			ids = append(ids, i.ID)
+		}
+	case IssueComments:
+		for _, c := range append(m.IssueComments, m.PRComments...) {
+			ids = append(ids, c.ID)
+		}
+	case PullRequests:
+		for _, pr := range m.PRs {
+			ids = append(ids, pr.ID)
+		}
+	case PullRequestReviews:
+		for _, r := range m.PRReviews {
+			ids = append(ids, r.ID)
+		}
+	case PullRequestReviewComments:
+		for _, c := range m.PRReviewComments {
+			ids = append(ids, c.ID)
+		}
+	case Projects:
+		for _, p := range m.Projects {
+			ids = append(ids, p.ID)
+		}
+	case ProjectColumns:
+		for _, c := range m.ProjectColumns {
+			ids = append(ids, c.ID)
+		}
+	case ProjectCards:
+		for _, c := range m.ProjectCards {
+			ids = append(ids, c.ID)
+		}
+	case ProjectsV2:
+		for _, p := range m.ProjectsV2 {
+			ids = append(ids, p.ID)
+		}
+	case ProjectV2Items:
+		for _, i := range m.ProjectV2Items {
+			ids = append(ids, i.ID)
+		}
+	case CommitComments:
+		for _, c := range m.CommitComments {
+			ids = append(ids, c.ID)
+		}
+	case Contributors:
+		for _, u := range m.Contributors {
+			ids = append(ids, u.ID)
+		}
+	}
+
+	return ids
+}
+
+func (memoryHarness) ActiveItems(t *testing.T, s github.Storer, kind Kind) []string {
+	return nil
+}
+
+func TestMemory(t *testing.T) {
+	Run(t, memoryHarness{})
+}
@@ -0,0 +1,147 @@
+// Package api contains the GitLab REST API v4 resources downloaded by the
+// gitlab Downloader
+package api
+
+import "time"
+
+// Group represents https://docs.gitlab.com/ee/api/groups.html
+type Group struct {
+	AvatarURL   string    `json:"avatar_url"`  // avatar_url text,
+	CreatedAt   time.Time `json:"created_at"`  // created_at timestamptz,
+	Description string    `json:"description"` // description text,
+	FullName    string    `json:"full_name"`   // full_name text,
+	FullPath    string    `json:"full_path"`   // full_path text,
+	WebURL      string    `json:"web_url"`     // htmlurl text,
+	ID          int       `json:"id"`          // id bigint,
+	Name        string    `json:"name"`        // name text,
+	ParentID    int       `json:"parent_id"`   // parent_id bigint,
+	Path        string    `json:"path"`        // path text,
+	Visibility  string    `json:"visibility"`  // visibility text,
+}
+
+// User represents the basic user fields returned as part of other resources
+// https://docs.gitlab.com/ee/api/users.html
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// Member represents https://docs.gitlab.com/ee/api/members.html
+type Member struct {
+	AccessLevel int    `json:"access_level"` // access_level bigint,
+	AvatarURL   string `json:"avatar_url"`   // avatar_url text,
+	WebURL      string `json:"web_url"`      // htmlurl text,
+	ID          int    `json:"id"`           // id bigint,
+	Name        string `json:"name"`         // name text,
+	State       string `json:"state"`        // state text,
+	Username    string `json:"username"`     // username text,
+}
+
+// Namespace is the group or user that owns a Project
+type Namespace struct {
+	FullPath string `json:"full_path"` // namespace_full_path text NOT NULL,
+	ID       int    `json:"id"`        // namespace_id bigint,
+	Kind     string `json:"kind"`      // namespace_kind text,
+}
+
+// Project represents https://docs.gitlab.com/ee/api/projects.html
+type Project struct {
+	Archived          bool       `json:"archived"`            // archived boolean,
+	CreatedAt         time.Time  `json:"created_at"`          // created_at timestamptz,
+	DefaultBranch     string     `json:"default_branch"`      // default_branch text,
+	Description       string     `json:"description"`         // description text,
+	ForkedFromProject *struct{}  `json:"forked_from_project"` // fork boolean,
+	ForksCount        int        `json:"forks_count"`         // forks_count bigint,
+	PathWithNamespace string     `json:"path_with_namespace"` // full_name text,
+	WebURL            string     `json:"web_url"`             // htmlurl text,
+	HTTPURLToRepo     string     `json:"http_url_to_repo"`    // http_url text,
+	ID                int        `json:"id"`                  // id bigint,
+	LastActivityAt    *time.Time `json:"last_activity_at"`    // last_activity_at timestamptz,
+	Name              string     `json:"name"`                // name text,
+	Namespace         Namespace  `json:"namespace"`           // namespace_full_path text NOT NULL, namespace_id bigint, namespace_kind text,
+	OpenIssuesCount   int        `json:"open_issues_count"`   // open_issues_count bigint,
+	Path              string     `json:"path"`                // path text NOT NULL,
+	SSHURLToRepo      string     `json:"ssh_url_to_repo"`     // sshurl text,
+	StarCount         int        `json:"star_count"`          // star_count bigint,
+	Topics            []string   `json:"topics"`              // topics text[],
+	TagList           []string   `json:"tag_list"`            // topics text[], before GitLab 14.0
+	Visibility        string     `json:"visibility"`          // visibility text,
+}
+
+// Milestone represents https://docs.gitlab.com/ee/api/milestones.html
+type Milestone struct {
+	ID    int    `json:"id"`    // milestone_id bigint,
+	Title string `json:"title"` // milestone_title text,
+}
+
+// Issue represents https://docs.gitlab.com/ee/api/issues.html
+type Issue struct {
+	Assignees        []User     `json:"assignees"`         // assignees text[],
+	Description      string     `json:"description"`       // body text,
+	ClosedAt         *time.Time `json:"closed_at"`         // closed_at timestamptz,
+	ClosedBy         *User      `json:"closed_by"`         // closed_by_id bigint, closed_by_login text,
+	UserNotesCount   int        `json:"user_notes_count"`  // comments bigint,
+	Confidential     bool       `json:"confidential"`      // confidential boolean,
+	CreatedAt        time.Time  `json:"created_at"`        // created_at timestamptz,
+	WebURL           string     `json:"web_url"`           // htmlurl text,
+	ID               int        `json:"id"`                // id bigint,
+	IID              int        `json:"iid"`               // iid bigint,
+	Labels           []string   `json:"labels"`            // labels text[],
+	DiscussionLocked bool       `json:"discussion_locked"` // locked boolean,
+	Milestone        *Milestone `json:"milestone"`         // milestone_id bigint, milestone_title text,
+	State            string     `json:"state"`             // state text,
+	Title            string     `json:"title"`             // title text,
+	UpdatedAt        time.Time  `json:"updated_at"`        // updated_at timestamptz,
+	Author           User       `json:"author"`            // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// MergeRequest represents https://docs.gitlab.com/ee/api/merge_requests.html
+type MergeRequest struct {
+	Assignees       []User     `json:"assignees"`         // assignees text[],
+	Description     string     `json:"description"`       // body text,
+	ClosedAt        *time.Time `json:"closed_at"`         // closed_at timestamptz,
+	UserNotesCount  int        `json:"user_notes_count"`  // comments bigint,
+	CreatedAt       time.Time  `json:"created_at"`        // created_at timestamptz,
+	Draft           bool       `json:"draft"`             // draft boolean,
+	WorkInProgress  bool       `json:"work_in_progress"`  // draft boolean, before GitLab 13.2
+	WebURL          string     `json:"web_url"`           // htmlurl text,
+	ID              int        `json:"id"`                // id bigint,
+	IID             int        `json:"iid"`               // iid bigint,
+	Labels          []string   `json:"labels"`            // labels text[],
+	MergeCommitSHA  string     `json:"merge_commit_sha"`  // merge_commit_sha text,
+	MergeStatus     string     `json:"merge_status"`      // merge_status text,
+	MergedAt        *time.Time `json:"merged_at"`         // merged_at timestamptz,
+	MergedBy        *User      `json:"merged_by"`         // merged_by_id bigint, merged_by_login text,
+	Milestone       *Milestone `json:"milestone"`         // milestone_id bigint, milestone_title text,
+	SHA             string     `json:"sha"`               // sha text,
+	SourceBranch    string     `json:"source_branch"`     // source_branch text,
+	SourceProjectID int        `json:"source_project_id"` // source_project_id bigint,
+	State           string     `json:"state"`             // state text,
+	TargetBranch    string     `json:"target_branch"`     // target_branch text,
+	TargetProjectID int        `json:"target_project_id"` // target_project_id bigint,
+	Title           string     `json:"title"`             // title text,
+	UpdatedAt       time.Time  `json:"updated_at"`        // updated_at timestamptz,
+	Author          User       `json:"author"`            // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// Note represents a comment on an issue or a merge request
+// https://docs.gitlab.com/ee/api/notes.html
+type Note struct {
+	Body         string    `json:"body"`          // body text,
+	CreatedAt    time.Time `json:"created_at"`    // created_at timestamptz,
+	ID           int       `json:"id"`            // id bigint,
+	NoteableIID  int       `json:"noteable_iid"`  // noteable_iid bigint NOT NULL,
+	NoteableType string    `json:"noteable_type"` // noteable_type text NOT NULL,
+	System       bool      `json:"system"`        // system boolean,
+	UpdatedAt    time.Time `json:"updated_at"`    // updated_at timestamptz,
+	Author       User      `json:"author"`        // user_id bigint NOT NULL, user_login text NOT NULL,
+}
+
+// Approvals represents the approvals of a merge request
+// https://docs.gitlab.com/ee/api/merge_request_approvals.html
+type Approvals struct {
+	ApprovedBy []struct {
+		User User `json:"user"`
+	} `json:"approved_by"`
+}
@@ -0,0 +1,430 @@
+// Package gitlab downloads the metadata of GitLab groups and projects using
+// the REST API v4, and stores it with the same model as the github package
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/src-d/metadata-retrieval/gitlab/api"
+	"github.com/src-d/metadata-retrieval/utils/ctxlog"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// DefaultURL is the base URL of the gitlab.com REST API
+const DefaultURL = "https://gitlab.com/api/v4/"
+
+// perPage is the page size requested for every paginated resource, the
+// maximum allowed by GitLab
+const perPage = 100
+
+// Storer is an interface required by Downloader to persist the downloaded data
+type Storer interface {
+	SaveGroup(ctx context.Context, group *api.Group) error
+	SaveUser(ctx context.Context, groupID int, groupPath string, user *api.Member) error
+	SaveProject(ctx context.Context, project *api.Project) error
+	SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error
+	SaveIssueNote(ctx context.Context, repositoryOwner, repositoryName string, issueIID int, note *api.Note) error
+	SaveMergeRequest(ctx context.Context, repositoryOwner, repositoryName string, mr *api.MergeRequest) error
+	SaveMergeRequestNote(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, note *api.Note) error
+	SaveMergeRequestApproval(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, user *api.User) error
+
+	Begin() error
+	Commit() error
+	Rollback() error
+	Version(v int)
+	SetActiveVersion(ctx context.Context, v int) error
+	Cleanup(ctx context.Context, currentVersion int) error
+}
+
+// Downloader fetches GitLab data using the REST API v4
+type Downloader struct {
+	storer  Storer
+	client  *http.Client
+	baseURL string
+}
+
+// NewDownloader creates a new Downloader that will store the GitLab metadata
+// in the given Storer. The HTTP client is expected to have the proper
+// authentication setup. The base URL of the API is DefaultURL when empty,
+// e.g. https://gitlab.example.com/api/v4/ for a self-managed instance
+func NewDownloader(httpClient *http.Client, baseURL string, storer Storer) (*Downloader, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid GitLab API URL %q: %v", baseURL, err)
+	}
+
+	return &Downloader{
+		storer:  storer,
+		client:  httpClient,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+	}, nil
+}
+
+// pathID returns the URL-encoded path of a group or project, accepted by the
+// API in place of its numeric ID
+func pathID(path string) string {
+	return strings.Replace(url.PathEscape(path), "/", "%2F", -1)
+}
+
+// errNotFound is returned when the requested resource does not exist, or it
+// is not available in the GitLab edition
+type errNotFound struct {
+	path string
+}
+
+func (e *errNotFound) Error() string {
+	return fmt.Sprintf("GET %s: 404 Not Found", e.path)
+}
+
+// get requests the given resource path, decodes the JSON response into v and
+// returns the page that follows, 0 if it was the last one
+func (d Downloader) get(ctx context.Context, path string, params url.Values, v interface{}) (int, error) {
+	u := d.baseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := d.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, &errNotFound{path: path}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return 0, fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return 0, fmt.Errorf("GET %s: could not decode the response: %v", path, err)
+	}
+
+	next, _ := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+	return next, nil
+}
+
+// downloadPages requests all the pages of the given resource path; process
+// is called with the raw JSON of each page
+func (d Downloader) downloadPages(ctx context.Context, name string, path string, params url.Values, process func(page json.RawMessage) error) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("per_page", strconv.Itoa(perPage))
+
+	for page := 1; page != 0; {
+		params.Set("page", strconv.Itoa(page))
+
+		var res json.RawMessage
+		next, err := d.get(ctx, path, params, &res)
+		if err != nil {
+			return fmt.Errorf("query to %s failed: %v", name, err)
+		}
+
+		if err := process(res); err != nil {
+			return fmt.Errorf("can not process %s: %v", name, err)
+		}
+
+		page = next
+	}
+
+	return nil
+}
+
+// DownloadProject downloads the metadata for the given project and all its
+// resources (issues, merge requests, notes, approvals). The owner is the full
+// path of the project namespace, e.g. gitlab-org/security
+func (d Downloader) DownloadProject(ctx context.Context, owner string, name string, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"owner": owner, "repo": name})
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var project api.Project
+	_, err = d.get(ctx, "projects/"+pathID(owner+"/"+name), nil, &project)
+	if err != nil {
+		return fmt.Errorf("project query failed: %v", err)
+	}
+
+	err = d.storer.SaveProject(ctx, &project)
+	if err != nil {
+		return fmt.Errorf("failed to save project %v: %v", project.PathWithNamespace, err)
+	}
+
+	err = d.downloadIssues(ctx, &project)
+	if err != nil {
+		return err
+	}
+
+	err = d.downloadMergeRequests(ctx, &project)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d Downloader) downloadIssues(ctx context.Context, project *api.Project) error {
+	logger := ctxlog.Get(ctx)
+	logger.Infof("start downloading issues")
+	defer logger.Infof("finished downloading issues")
+
+	owner, name := project.Namespace.FullPath, project.Path
+	params := url.Values{"scope": []string{"all"}}
+	path := fmt.Sprintf("projects/%d/issues", project.ID)
+
+	return d.downloadPages(ctx, "issues", path, params, func(page json.RawMessage) error {
+		var issues []api.Issue
+		if err := json.Unmarshal(page, &issues); err != nil {
+			return err
+		}
+
+		for _, issue := range issues {
+			if err := d.storer.SaveIssue(ctx, owner, name, &issue); err != nil {
+				return fmt.Errorf("failed to save issue #%v: %v", issue.IID, err)
+			}
+
+			if err := d.downloadNotes(ctx, project, "issues", issue.IID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d Downloader) downloadMergeRequests(ctx context.Context, project *api.Project) error {
+	logger := ctxlog.Get(ctx)
+	logger.Infof("start downloading merge requests")
+	defer logger.Infof("finished downloading merge requests")
+
+	owner, name := project.Namespace.FullPath, project.Path
+	params := url.Values{"scope": []string{"all"}, "state": []string{"all"}}
+	path := fmt.Sprintf("projects/%d/merge_requests", project.ID)
+
+	return d.downloadPages(ctx, "merge requests", path, params, func(page json.RawMessage) error {
+		var mrs []api.MergeRequest
+		if err := json.Unmarshal(page, &mrs); err != nil {
+			return err
+		}
+
+		for _, mr := range mrs {
+			if err := d.storer.SaveMergeRequest(ctx, owner, name, &mr); err != nil {
+				return fmt.Errorf("failed to save merge request !%v: %v", mr.IID, err)
+			}
+
+			if err := d.downloadNotes(ctx, project, "merge_requests", mr.IID); err != nil {
+				return err
+			}
+
+			if err := d.downloadApprovals(ctx, project, mr.IID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// downloadNotes downloads the notes of an issue or a merge request, the
+// noteables are "issues" or "merge_requests"
+func (d Downloader) downloadNotes(ctx context.Context, project *api.Project, noteables string, iid int) error {
+	owner, name := project.Namespace.FullPath, project.Path
+	params := url.Values{"sort": []string{"asc"}, "order_by": []string{"created_at"}}
+	path := fmt.Sprintf("projects/%d/%s/%d/notes", project.ID, noteables, iid)
+
+	return d.downloadPages(ctx, noteables+" notes", path, params, func(page json.RawMessage) error {
+		var notes []api.Note
+		if err := json.Unmarshal(page, &notes); err != nil {
+			return err
+		}
+
+		for _, note := range notes {
+			var err error
+			if noteables == "issues" {
+				err = d.storer.SaveIssueNote(ctx, owner, name, iid, &note)
+			} else {
+				err = d.storer.SaveMergeRequestNote(ctx, owner, name, iid, &note)
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to save note %v of %s %v: %v", note.ID, noteables, iid, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d Downloader) downloadApprovals(ctx context.Context, project *api.Project, iid int) error {
+	var approvals api.Approvals
+	path := fmt.Sprintf("projects/%d/merge_requests/%d/approvals", project.ID, iid)
+	_, err := d.get(ctx, path, nil, &approvals)
+	if _, ok := err.(*errNotFound); ok {
+		// approvals are not available in older GitLab Community Edition versions
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("approvals query failed: %v", err)
+	}
+
+	for _, approval := range approvals.ApprovedBy {
+		err := d.storer.SaveMergeRequestApproval(ctx, project.Namespace.FullPath, project.Path, iid, &approval.User)
+		if err != nil {
+			return fmt.Errorf("failed to save approval of merge request !%v: %v", iid, err)
+		}
+	}
+
+	return nil
+}
+
+// ListProjects returns the full path of the projects of the given group and
+// its subgroups
+func (d Downloader) ListProjects(ctx context.Context, group string, noForks bool) ([]string, error) {
+	params := url.Values{
+		"include_subgroups": []string{"true"},
+		"order_by":          []string{"id"},
+		"sort":              []string{"asc"},
+	}
+	path := "groups/" + pathID(group) + "/projects"
+
+	projects := []string{}
+	err := d.downloadPages(ctx, "projects", path, params, func(page json.RawMessage) error {
+		var res []api.Project
+		if err := json.Unmarshal(page, &res); err != nil {
+			return err
+		}
+
+		for _, project := range res {
+			if noForks && project.ForkedFromProject != nil {
+				continue
+			}
+
+			projects = append(projects, project.PathWithNamespace)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// DownloadGroup downloads the metadata for the given group and its member
+// users
+func (d Downloader) DownloadGroup(ctx context.Context, name string, version int) error {
+	ctx, _ = ctxlog.WithLogFields(ctx, log.Fields{"group": name})
+
+	d.storer.Version(version)
+
+	var err error
+	err = d.storer.Begin()
+	if err != nil {
+		return fmt.Errorf("could not call Begin(): %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			d.storer.Rollback()
+			return
+		}
+
+		d.storer.Commit()
+	}()
+
+	var group api.Group
+	params := url.Values{"with_projects": []string{"false"}}
+	_, err = d.get(ctx, "groups/"+pathID(name), params, &group)
+	if err != nil {
+		return fmt.Errorf("group query failed: %v", err)
+	}
+
+	err = d.storer.SaveGroup(ctx, &group)
+	if err != nil {
+		return fmt.Errorf("failed to save group %v: %v", name, err)
+	}
+
+	err = d.downloadMembers(ctx, &group)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d Downloader) downloadMembers(ctx context.Context, group *api.Group) error {
+	path := fmt.Sprintf("groups/%d/members", group.ID)
+	return d.downloadPages(ctx, "members", path, nil, func(page json.RawMessage) error {
+		var members []api.Member
+		if err := json.Unmarshal(page, &members); err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if err := d.storer.SaveUser(ctx, group.ID, group.FullPath, &member); err != nil {
+				return fmt.Errorf("failed to save user %v: %v", member.Username, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetCurrent enables the given version as the current one accessible in the DB
+func (d Downloader) SetCurrent(ctx context.Context, version int) error {
+	err := d.storer.SetActiveVersion(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to set current DB version to %v: %v", version, err)
+	}
+	return nil
+}
+
+// Cleanup deletes from the DB all records that do not belong to the currentVersion
+func (d Downloader) Cleanup(ctx context.Context, currentVersion int) error {
+	err := d.storer.Cleanup(ctx, currentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to do cleanup for DB version %v: %v", currentVersion, err)
+	}
+	return nil
+}
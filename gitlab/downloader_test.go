@@ -0,0 +1,112 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/src-d/metadata-retrieval/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	recFile = "../testdata/gitlab-recordings.json"
+	testURL = "https://gitlab.example.com/api/v4/"
+)
+
+// getRoundTripDownloader returns a Downloader that replays the recorded
+// responses, indexed by the request URI relative to the API base URL
+func getRoundTripDownloader(t *testing.T, storer Storer) *Downloader {
+	replayer, err := testutils.NewReplayer(recFile, "/api/v4/", testutils.RecordedResponse{
+		Status: http.StatusInternalServerError,
+		Body:   []byte(`"unexpected request"`),
+	})
+	require.NoError(t, err)
+
+	downloader, err := NewDownloader(&http.Client{Transport: replayer}, testURL, storer)
+	require.NoError(t, err)
+
+	return downloader
+}
+
+func TestDownloadProject(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.GitLabMemory{}
+	downloader := getRoundTripDownloader(t, storer)
+
+	err := downloader.DownloadProject(context.TODO(), "acme/backend", "api", 1)
+	require.NoError(err)
+
+	require.Equal("acme/backend/api", storer.Project.PathWithNamespace)
+	require.Equal("acme/backend", storer.Project.Namespace.FullPath)
+	require.Equal([]string{"go", "api"}, storer.Project.TagList)
+
+	// the issues are paginated
+	require.Len(storer.Issues, 2)
+	require.Equal(1, storer.Issues[0].IID)
+	require.Equal("v1", storer.Issues[0].Milestone.Title)
+	require.Equal("bob", storer.Issues[1].ClosedBy.Username)
+
+	// system notes are stored too, they are filtered out in the unified views
+	require.Len(storer.IssueNotes, 3)
+	require.True(storer.IssueNotes[1].System)
+
+	require.Len(storer.MergeRequests, 2)
+	require.True(storer.MergeRequests[0].WorkInProgress)
+	require.Equal("merged", storer.MergeRequests[1].State)
+	require.Len(storer.MergeRequestNotes, 1)
+
+	// the approvals of the second merge request are not available
+	require.Len(storer.Approvals, 1)
+	require.Equal("bob", storer.Approvals[0].Username)
+}
+
+func TestDownloadProjectNotFound(t *testing.T) {
+	storer := &testutils.GitLabMemory{}
+	downloader := getRoundTripDownloader(t, storer)
+
+	err := downloader.DownloadProject(context.TODO(), "acme", "missing", 1)
+	require.Error(t, err)
+}
+
+func TestDownloadGroup(t *testing.T) {
+	require := require.New(t)
+
+	storer := &testutils.GitLabMemory{}
+	downloader := getRoundTripDownloader(t, storer)
+
+	err := downloader.DownloadGroup(context.TODO(), "acme", 1)
+	require.NoError(err)
+
+	require.Equal("acme", storer.Group.FullPath)
+	require.Len(storer.Users, 2)
+	require.Equal(50, storer.Users[0].AccessLevel)
+}
+
+func TestListProjects(t *testing.T) {
+	require := require.New(t)
+
+	downloader := getRoundTripDownloader(t, &testutils.GitLabMemory{})
+
+	projects, err := downloader.ListProjects(context.TODO(), "acme", false)
+	require.NoError(err)
+	require.Equal([]string{"acme/backend/api", "acme/api-fork", "acme/web"}, projects)
+
+	projects, err = downloader.ListProjects(context.TODO(), "acme", true)
+	require.NoError(err)
+	require.Equal([]string{"acme/backend/api", "acme/web"}, projects)
+}
+
+func TestProviderListRepositories(t *testing.T) {
+	downloader := getRoundTripDownloader(t, &testutils.GitLabMemory{})
+
+	projects, err := providerDownloader{downloader}.ListRepositories(context.TODO(), "acme", false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend/api", "api-fork", "web"}, projects)
+}
+
+func TestPathID(t *testing.T) {
+	require.Equal(t, "acme%2Fbackend%2Fapi", pathID("acme/backend/api"))
+}
@@ -0,0 +1,63 @@
+package gitlab
+
+import (
+	"context"
+	"strings"
+
+	"github.com/src-d/metadata-retrieval/gitlab/store"
+	"github.com/src-d/metadata-retrieval/provider"
+)
+
+func init() {
+	provider.Register("gitlab", newProvider)
+}
+
+// newProvider creates a Downloader for the provider registry
+func newProvider(c provider.Config) (provider.Downloader, error) {
+	var storer Storer = &store.Stdout{}
+	if c.DB != nil {
+		storer = store.NewDB(c.DB)
+	}
+
+	d, err := NewDownloader(c.HTTPClient, c.BaseURL, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerDownloader{d}, nil
+}
+
+// providerDownloader implements provider.Downloader, where groups are
+// organizations and projects are repositories
+type providerDownloader struct {
+	*Downloader
+}
+
+// ListRepositories returns the path of the projects of the given group and
+// its subgroups, relative to the group
+func (d providerDownloader) ListRepositories(ctx context.Context, group string, noForks bool) ([]string, error) {
+	projects, err := d.ListProjects(ctx, group, noForks)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range projects {
+		projects[i] = strings.TrimPrefix(p, group+"/")
+	}
+
+	return projects, nil
+}
+
+func (d providerDownloader) DownloadRepository(ctx context.Context, owner string, name string, version int) error {
+	return d.DownloadProject(ctx, owner, name, version)
+}
+
+func (d providerDownloader) DownloadOrganization(ctx context.Context, name string, version int) error {
+	return d.DownloadGroup(ctx, name, version)
+}
+
+// RateRemaining returns provider.UnknownRate, GitLab only reports the rate
+// limit status in the response headers
+func (d providerDownloader) RateRemaining(ctx context.Context) (int, error) {
+	return provider.UnknownRate, nil
+}
@@ -0,0 +1,324 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/database"
+	"github.com/src-d/metadata-retrieval/gitlab/api"
+
+	"github.com/lib/pq"
+)
+
+type DB struct {
+	*sql.DB
+	tx *sql.Tx
+	v  int
+}
+
+func NewDB(db *sql.DB) *DB {
+	return &DB{DB: db}
+}
+
+func (s *DB) Begin() error {
+	var err error
+	s.tx, err = s.DB.Begin()
+	return err
+}
+
+func (s *DB) Commit() error {
+	return s.tx.Commit()
+}
+
+func (s *DB) Rollback() error {
+	return s.tx.Rollback()
+}
+
+func (s *DB) Version(v int) {
+	s.v = v
+}
+
+const (
+	groupsCols                = "avatar_url, created_at, description, full_name, full_path, htmlurl, id, name, parent_id, path, visibility"
+	usersCols                 = "access_level, avatar_url, group_full_path, group_id, htmlurl, id, name, state, username"
+	projectsCols              = "archived, created_at, default_branch, description, fork, forks_count, full_name, htmlurl, http_url, id, last_activity_at, name, namespace_full_path, namespace_id, namespace_kind, open_issues_count, path, sshurl, star_count, topics, visibility"
+	issuesCols                = "assignees, body, closed_at, closed_by_id, closed_by_login, comments, confidential, created_at, htmlurl, id, iid, labels, locked, milestone_id, milestone_title, repository_name, repository_owner, state, title, updated_at, user_id, user_login"
+	mergeRequestsCols         = "assignees, body, closed_at, comments, created_at, draft, htmlurl, id, iid, labels, merge_commit_sha, merge_status, merged_at, merged_by_id, merged_by_login, milestone_id, milestone_title, repository_name, repository_owner, sha, source_branch, source_project_id, state, target_branch, target_project_id, title, updated_at, user_id, user_login"
+	notesCols                 = "body, created_at, id, noteable_iid, noteable_type, repository_name, repository_owner, system, updated_at, user_id, user_login"
+	mergeRequestApprovalsCols = "merge_request_iid, repository_name, repository_owner, user_id, user_login"
+)
+
+var tables = []string{
+	"gitlab_groups_versioned",
+	"gitlab_users_versioned",
+	"gitlab_projects_versioned",
+	"gitlab_issues_versioned",
+	"gitlab_merge_requests_versioned",
+	"gitlab_notes_versioned",
+	"gitlab_merge_request_approvals_versioned",
+}
+
+func (s *DB) SetActiveVersion(ctx context.Context, v int) error {
+	// Unified schema
+
+	if err := database.SetUnifiedViews(ctx, s.DB, v); err != nil {
+		return err
+	}
+
+	// GitLab schema without versions
+
+	return database.SetVersionedViews(ctx, s.DB, tables, v)
+}
+
+func (s *DB) Cleanup(ctx context.Context, currentVersion int) error {
+	return database.CleanupVersioned(ctx, s.DB, tables, currentVersion)
+}
+
+// insert stores the given values of a row in a versioned table
+func (s *DB) insert(ctx context.Context, table string, cols string, args ...interface{}) error {
+	return database.InsertVersioned(ctx, s.tx, s.v, table, cols, args...)
+}
+
+func (s *DB) SaveGroup(ctx context.Context, group *api.Group) error {
+	err := s.insert(ctx, "gitlab_groups_versioned", groupsCols,
+		group.AvatarURL,   // avatar_url text,
+		group.CreatedAt,   // created_at timestamptz,
+		group.Description, // description text,
+		group.FullName,    // full_name text,
+		group.FullPath,    // full_path text NOT NULL,
+		group.WebURL,      // htmlurl text,
+		group.ID,          // id bigint,
+		group.Name,        // name text,
+		group.ParentID,    // parent_id bigint,
+		group.Path,        // path text,
+		group.Visibility,  // visibility text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveGroup: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveUser(ctx context.Context, groupID int, groupPath string, user *api.Member) error {
+	err := s.insert(ctx, "gitlab_users_versioned", usersCols,
+		user.AccessLevel, // access_level bigint,
+		user.AvatarURL,   // avatar_url text,
+		groupPath,        // group_full_path text NOT NULL,
+		groupID,          // group_id bigint NOT NULL,
+		user.WebURL,      // htmlurl text,
+		user.ID,          // id bigint,
+		user.Name,        // name text,
+		user.State,       // state text,
+		user.Username,    // username text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveUser: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveProject(ctx context.Context, project *api.Project) error {
+	// the topics were named tag_list before GitLab 14.0
+	topics := project.Topics
+	if len(topics) == 0 {
+		topics = project.TagList
+	}
+
+	if topics == nil {
+		topics = []string{}
+	}
+
+	err := s.insert(ctx, "gitlab_projects_versioned", projectsCols,
+		project.Archived,                 // archived boolean,
+		project.CreatedAt,                // created_at timestamptz,
+		project.DefaultBranch,            // default_branch text,
+		project.Description,              // description text,
+		project.ForkedFromProject != nil, // fork boolean,
+		project.ForksCount,               // forks_count bigint,
+		project.PathWithNamespace,        // full_name text,
+		project.WebURL,                   // htmlurl text,
+		project.HTTPURLToRepo,            // http_url text,
+		project.ID,                       // id bigint,
+		project.LastActivityAt,           // last_activity_at timestamptz,
+		project.Name,                     // name text,
+		project.Namespace.FullPath,       // namespace_full_path text NOT NULL,
+		project.Namespace.ID,             // namespace_id bigint,
+		project.Namespace.Kind,           // namespace_kind text,
+		project.OpenIssuesCount,          // open_issues_count bigint,
+		project.Path,                     // path text NOT NULL,
+		project.SSHURLToRepo,             // sshurl text,
+		project.StarCount,                // star_count bigint,
+		pq.Array(topics),                 // topics text[] NOT NULL,
+		project.Visibility,               // visibility text,
+	)
+
+	if err != nil {
+		return fmt.Errorf("SaveProject: %v", err)
+	}
+	return nil
+}
+
+// usernames returns the username of each user, never nil
+func usernames(users []api.User) []string {
+	res := make([]string, len(users))
+	for i, user := range users {
+		res[i] = user.Username
+	}
+
+	return res
+}
+
+// nonNil returns an empty slice for a nil one, to store it in a NOT NULL
+// array column
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+// user returns the ID and the username of an optional user
+func user(u *api.User) (int, string) {
+	if u == nil {
+		return 0, ""
+	}
+
+	return u.ID, u.Username
+}
+
+// milestone returns the ID and the title of an optional milestone
+func milestone(m *api.Milestone) (int, string) {
+	if m == nil {
+		return 0, ""
+	}
+
+	return m.ID, m.Title
+}
+
+func (s *DB) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error {
+	closedByID, closedByLogin := user(issue.ClosedBy)
+	milestoneID, milestoneTitle := milestone(issue.Milestone)
+
+	err := s.insert(ctx, "gitlab_issues_versioned", issuesCols,
+		pq.Array(usernames(issue.Assignees)), // assignees text[] NOT NULL,
+		issue.Description,                    // body text,
+		issue.ClosedAt,                       // closed_at timestamptz,
+		closedByID,                           // closed_by_id bigint,
+		closedByLogin,                        // closed_by_login text,
+		issue.UserNotesCount,                 // comments bigint,
+		issue.Confidential,                   // confidential boolean,
+		issue.CreatedAt,                      // created_at timestamptz,
+		issue.WebURL,                         // htmlurl text,
+		issue.ID,                             // id bigint,
+		issue.IID,                            // iid bigint NOT NULL,
+		pq.Array(nonNil(issue.Labels)),       // labels text[] NOT NULL,
+		issue.DiscussionLocked,               // locked boolean,
+		milestoneID,                          // milestone_id bigint,
+		milestoneTitle,                       // milestone_title text,
+		repositoryName,                       // repository_name text NOT NULL,
+		repositoryOwner,                      // repository_owner text NOT NULL,
+		issue.State,                          // state text,
+		issue.Title,                          // title text,
+		issue.UpdatedAt,                      // updated_at timestamptz,
+		issue.Author.ID,                      // user_id bigint NOT NULL,
+		issue.Author.Username,                // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveIssue: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveMergeRequest(ctx context.Context, repositoryOwner, repositoryName string, mr *api.MergeRequest) error {
+	mergedByID, mergedByLogin := user(mr.MergedBy)
+	milestoneID, milestoneTitle := milestone(mr.Milestone)
+
+	err := s.insert(ctx, "gitlab_merge_requests_versioned", mergeRequestsCols,
+		pq.Array(usernames(mr.Assignees)), // assignees text[] NOT NULL,
+		mr.Description,                    // body text,
+		mr.ClosedAt,                       // closed_at timestamptz,
+		mr.UserNotesCount,                 // comments bigint,
+		mr.CreatedAt,                      // created_at timestamptz,
+		mr.Draft || mr.WorkInProgress,     // draft boolean,
+		mr.WebURL,                         // htmlurl text,
+		mr.ID,                             // id bigint,
+		mr.IID,                            // iid bigint NOT NULL,
+		pq.Array(nonNil(mr.Labels)),       // labels text[] NOT NULL,
+		mr.MergeCommitSHA,                 // merge_commit_sha text,
+		mr.MergeStatus,                    // merge_status text,
+		mr.MergedAt,                       // merged_at timestamptz,
+		mergedByID,                        // merged_by_id bigint,
+		mergedByLogin,                     // merged_by_login text,
+		milestoneID,                       // milestone_id bigint,
+		milestoneTitle,                    // milestone_title text,
+		repositoryName,                    // repository_name text NOT NULL,
+		repositoryOwner,                   // repository_owner text NOT NULL,
+		mr.SHA,                            // sha text,
+		mr.SourceBranch,                   // source_branch text,
+		mr.SourceProjectID,                // source_project_id bigint,
+		mr.State,                          // state text,
+		mr.TargetBranch,                   // target_branch text,
+		mr.TargetProjectID,                // target_project_id bigint,
+		mr.Title,                          // title text,
+		mr.UpdatedAt,                      // updated_at timestamptz,
+		mr.Author.ID,                      // user_id bigint NOT NULL,
+		mr.Author.Username,                // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveMergeRequest: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) saveNote(ctx context.Context, repositoryOwner, repositoryName string, noteableType string, noteableIID int, note *api.Note) error {
+	return s.insert(ctx, "gitlab_notes_versioned", notesCols,
+		note.Body,            // body text,
+		note.CreatedAt,       // created_at timestamptz,
+		note.ID,              // id bigint,
+		noteableIID,          // noteable_iid bigint NOT NULL,
+		noteableType,         // noteable_type text NOT NULL,
+		repositoryName,       // repository_name text NOT NULL,
+		repositoryOwner,      // repository_owner text NOT NULL,
+		note.System,          // system boolean,
+		note.UpdatedAt,       // updated_at timestamptz,
+		note.Author.ID,       // user_id bigint NOT NULL,
+		note.Author.Username, // user_login text NOT NULL,
+	)
+}
+
+func (s *DB) SaveIssueNote(ctx context.Context, repositoryOwner, repositoryName string, issueIID int, note *api.Note) error {
+	err := s.saveNote(ctx, repositoryOwner, repositoryName, "Issue", issueIID, note)
+	if err != nil {
+		return fmt.Errorf("saveIssueNote: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveMergeRequestNote(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, note *api.Note) error {
+	err := s.saveNote(ctx, repositoryOwner, repositoryName, "MergeRequest", mergeRequestIID, note)
+	if err != nil {
+		return fmt.Errorf("saveMergeRequestNote: %v", err)
+	}
+	return nil
+}
+
+func (s *DB) SaveMergeRequestApproval(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, user *api.User) error {
+	err := s.insert(ctx, "gitlab_merge_request_approvals_versioned", mergeRequestApprovalsCols,
+		mergeRequestIID, // merge_request_iid bigint NOT NULL,
+		repositoryName,  // repository_name text NOT NULL,
+		repositoryOwner, // repository_owner text NOT NULL,
+		user.ID,         // user_id bigint NOT NULL,
+		user.Username,   // user_login text NOT NULL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("saveMergeRequestApproval: %v", err)
+	}
+	return nil
+}
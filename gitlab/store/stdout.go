@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/src-d/metadata-retrieval/gitlab/api"
+)
+
+type Stdout struct{}
+
+func (s *Stdout) SaveGroup(ctx context.Context, group *api.Group) error {
+	fmt.Printf("group data fetched for %s\n", group.FullPath)
+	return nil
+}
+
+func (s *Stdout) SaveUser(ctx context.Context, groupID int, groupPath string, user *api.Member) error {
+	fmt.Printf("user data fetched for %s\n", user.Username)
+	return nil
+}
+
+func (s *Stdout) SaveProject(ctx context.Context, project *api.Project) error {
+	fmt.Printf("project data fetched for %s\n", project.PathWithNamespace)
+	return nil
+}
+
+func (s *Stdout) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error {
+	fmt.Printf("issue data fetched for #%v %s\n", issue.IID, issue.Title)
+	return nil
+}
+
+func (s *Stdout) SaveIssueNote(ctx context.Context, repositoryOwner, repositoryName string, issueIID int, note *api.Note) error {
+	fmt.Printf("  issue note data fetched by %s at %v: %q\n", note.Author.Username, note.CreatedAt, trim(note.Body))
+	return nil
+}
+
+func (s *Stdout) SaveMergeRequest(ctx context.Context, repositoryOwner, repositoryName string, mr *api.MergeRequest) error {
+	fmt.Printf("MR data fetched for !%v %s\n", mr.IID, mr.Title)
+	return nil
+}
+
+func (s *Stdout) SaveMergeRequestNote(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, note *api.Note) error {
+	fmt.Printf("  MR note data fetched by %s at %v: %q\n", note.Author.Username, note.CreatedAt, trim(note.Body))
+	return nil
+}
+
+func (s *Stdout) SaveMergeRequestApproval(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, user *api.User) error {
+	fmt.Printf("  MR approval data fetched for %s\n", user.Username)
+	return nil
+}
+
+func (s *Stdout) Begin() error {
+	return nil
+}
+
+func (s *Stdout) Commit() error {
+	return nil
+}
+
+func (s *Stdout) Rollback() error {
+	return nil
+}
+
+func (s *Stdout) Version(v int) {
+}
+
+func (s *Stdout) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+func (s *Stdout) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
+
+func trim(s string) string {
+	if len(s) > 40 {
+		return s[0:39] + "..."
+	}
+
+	return s
+}
@@ -0,0 +1,161 @@
+// Package provider defines the Downloader interface implemented by every
+// code hosting provider (GitHub, GitLab, Bitbucket, Gitea), and a registry
+// to create them by name.
+//
+// The provider packages register themselves when they are imported, in the
+// same way as the database/sql drivers:
+//
+//	import _ "github.com/src-d/metadata-retrieval/gitlab"
+//
+//	target, err := provider.ParseTarget("gitlab:gitlab-org/security")
+//	d, err := provider.New(target.Provider, provider.Config{HTTPClient: client, DB: db})
+//	err = d.DownloadOrganization(ctx, target.Path, version)
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultProvider is the provider of the targets without a prefix
+const DefaultProvider = "github"
+
+// UnknownRate is returned by RateRemaining when the provider does not report
+// the status of its rate limit
+const UnknownRate = -1
+
+// Downloader fetches the metadata of the repositories and organizations of a
+// provider. An organization is the account that owns the repositories: a
+// GitHub organization, a GitLab group, a Bitbucket workspace, ...
+type Downloader interface {
+	// ListRepositories returns the names of the repositories of the given
+	// owner, relative to it
+	ListRepositories(ctx context.Context, owner string, noForks bool) ([]string, error)
+	// DownloadRepository downloads the metadata for the given repository
+	DownloadRepository(ctx context.Context, owner string, name string, version int) error
+	// DownloadOrganization downloads the metadata for the given organization
+	DownloadOrganization(ctx context.Context, name string, version int) error
+	// RateRemaining returns the remaining rate limit, or UnknownRate
+	RateRemaining(ctx context.Context) (int, error)
+	// SetCurrent enables the given version as the current one accessible in the DB
+	SetCurrent(ctx context.Context, version int) error
+	// Cleanup deletes from the DB all records that do not belong to the currentVersion
+	Cleanup(ctx context.Context, currentVersion int) error
+}
+
+// Config is the configuration used by a Factory to create a Downloader
+type Config struct {
+	// HTTPClient is expected to have the proper authentication setup
+	HTTPClient *http.Client
+	// BaseURL is the URL of the provider API, or of the GitHub Enterprise
+	// Server instance. The provider default is used when empty
+	BaseURL string
+	// DB is where the metadata is stored, it is printed to stdout when nil
+	DB *sql.DB
+}
+
+// Factory creates a Downloader with the given configuration
+type Factory func(c Config) (Downloader, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a provider available by the given name. It panics if
+// Register is called twice with the same name or if factory is nil
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("provider: Register factory is nil")
+	}
+
+	if _, dup := factories[name]; dup {
+		panic("provider: Register called twice for provider " + name)
+	}
+
+	factories[name] = factory
+}
+
+// Providers returns a sorted list of the names of the registered providers
+func Providers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	var names []string
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// New creates a Downloader of the given provider
+func New(name string, c Config) (Downloader, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q (forgotten import?)", name)
+	}
+
+	return factory(c)
+}
+
+// Target is an organization or repository of a provider, written as
+// "provider:path", e.g. "gitlab:gitlab-org/security/gitlab". The provider is
+// DefaultProvider when there is no prefix
+type Target struct {
+	Provider string
+	// Path is the organization name, or the owner and name of a repository
+	// separated by a slash
+	Path string
+}
+
+// ParseTarget parses the given target, the provider must be registered
+func ParseTarget(s string) (Target, error) {
+	t := Target{Provider: DefaultProvider, Path: s}
+	if i := strings.Index(s, ":"); i >= 0 {
+		t.Provider, t.Path = s[:i], s[i+1:]
+	}
+
+	t.Path = strings.Trim(t.Path, "/")
+	if t.Path == "" {
+		return Target{}, fmt.Errorf("invalid target %q: the path is empty", s)
+	}
+
+	factoriesMu.RLock()
+	_, ok := factories[t.Provider]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return Target{}, fmt.Errorf("invalid target %q: unknown provider %q", s, t.Provider)
+	}
+
+	return t, nil
+}
+
+// Repository returns the owner and name of a repository target. The owner is
+// everything before the last slash, like a GitLab subgroup
+func (t Target) Repository() (owner string, name string, err error) {
+	i := strings.LastIndex(t.Path, "/")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid repository %q: expected owner/name", t.Path)
+	}
+
+	return t.Path[:i], t.Path[i+1:], nil
+}
+
+// String returns the target with its provider prefix
+func (t Target) String() string {
+	return t.Provider + ":" + t.Path
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDownloader struct {
+	Config
+}
+
+func (d fakeDownloader) ListRepositories(ctx context.Context, owner string, noForks bool) ([]string, error) {
+	return []string{"repo"}, nil
+}
+
+func (d fakeDownloader) DownloadRepository(ctx context.Context, owner string, name string, version int) error {
+	return nil
+}
+
+func (d fakeDownloader) DownloadOrganization(ctx context.Context, name string, version int) error {
+	return nil
+}
+
+func (d fakeDownloader) RateRemaining(ctx context.Context) (int, error) {
+	return UnknownRate, nil
+}
+
+func (d fakeDownloader) SetCurrent(ctx context.Context, version int) error {
+	return nil
+}
+
+func (d fakeDownloader) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
+
+func init() {
+	Register("github", func(c Config) (Downloader, error) { return fakeDownloader{c}, nil })
+	Register("fake", func(c Config) (Downloader, error) { return fakeDownloader{c}, nil })
+}
+
+func TestRegistry(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]string{"fake", "github"}, Providers())
+
+	d, err := New("fake", Config{BaseURL: "https://fake.example.com"})
+	require.NoError(err)
+	require.Equal("https://fake.example.com", d.(fakeDownloader).BaseURL)
+
+	_, err = New("missing", Config{})
+	require.Error(err)
+
+	require.Panics(func() {
+		Register("fake", func(c Config) (Downloader, error) { return nil, nil })
+	})
+	require.Panics(func() {
+		Register("nil", nil)
+	})
+}
+
+func TestParseTarget(t *testing.T) {
+	require := require.New(t)
+
+	target, err := ParseTarget("fake:acme/backend/api")
+	require.NoError(err)
+	require.Equal(Target{Provider: "fake", Path: "acme/backend/api"}, target)
+	require.Equal("fake:acme/backend/api", target.String())
+
+	owner, name, err := target.Repository()
+	require.NoError(err)
+	require.Equal("acme/backend", owner)
+	require.Equal("api", name)
+
+	target, err = ParseTarget("src-d")
+	require.NoError(err)
+	require.Equal(Target{Provider: DefaultProvider, Path: "src-d"}, target)
+
+	_, _, err = target.Repository()
+	require.Error(err)
+
+	_, err = ParseTarget("fake:")
+	require.Error(err)
+
+	_, err = ParseTarget("missing:acme")
+	require.Error(err)
+}
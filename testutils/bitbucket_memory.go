@@ -0,0 +1,90 @@
+package testutils
+
+import (
+	"context"
+
+	"github.com/src-d/metadata-retrieval/bitbucket/api"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// BitbucketMemory implements the bitbucket storer interface. The resources
+// are copied, so the downloader can reuse its variables
+type BitbucketMemory struct {
+	Workspace    *api.Workspace
+	Repository   *api.Repository
+	PRs          []api.PullRequest
+	PRComments   []api.Comment
+	Participants []api.Participant
+}
+
+// SaveWorkspace stores a workspace in memory
+func (s *BitbucketMemory) SaveWorkspace(ctx context.Context, workspace *api.Workspace) error {
+	log.Infof("workspace data fetched for %s\n", workspace.Slug)
+	w := *workspace
+	s.Workspace = &w
+	return nil
+}
+
+// SaveRepository stores a repository in memory and initializes its PRs, PR
+// comments and participants
+func (s *BitbucketMemory) SaveRepository(ctx context.Context, repository *api.Repository) error {
+	log.Infof("repository data fetched for %s\n", repository.FullName)
+	r := *repository
+	s.Repository = &r
+	s.PRs = make([]api.PullRequest, 0)
+	s.PRComments = make([]api.Comment, 0)
+	s.Participants = make([]api.Participant, 0)
+	return nil
+}
+
+// SavePullRequest appends a PR to the PR list in memory
+func (s *BitbucketMemory) SavePullRequest(ctx context.Context, workspace, repositorySlug string, pr *api.PullRequest) error {
+	log.Infof("PR data fetched for #%v %s\n", pr.ID, pr.Title)
+	s.PRs = append(s.PRs, *pr)
+	return nil
+}
+
+// SavePullRequestComment appends a PR comment to the PR comment list in memory
+func (s *BitbucketMemory) SavePullRequestComment(ctx context.Context, workspace, repositorySlug string, pullRequestID int, comment *api.Comment) error {
+	log.Infof("  pr comment data fetched by %s at %v: %q\n", comment.User.Nickname, comment.CreatedOn, trim(comment.Content.Raw))
+	s.PRComments = append(s.PRComments, *comment)
+	return nil
+}
+
+// SavePullRequestParticipant appends a PR participant to the participant list
+// in memory
+func (s *BitbucketMemory) SavePullRequestParticipant(ctx context.Context, workspace, repositorySlug string, pullRequestID int, participant *api.Participant) error {
+	log.Infof("  pr participant data fetched for %s\n", participant.User.Nickname)
+	s.Participants = append(s.Participants, *participant)
+	return nil
+}
+
+// Begin is a noop method at the moment
+func (s *BitbucketMemory) Begin() error {
+	return nil
+}
+
+// Commit is a noop method at the moment
+func (s *BitbucketMemory) Commit() error {
+	return nil
+}
+
+// Rollback is a noop method at the moment
+func (s *BitbucketMemory) Rollback() error {
+	return nil
+}
+
+// Version is a noop method at the moment
+func (s *BitbucketMemory) Version(v int) {
+}
+
+// SetActiveVersion is a noop method at the moment
+func (s *BitbucketMemory) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+// Cleanup is a noop method at the moment
+func (s *BitbucketMemory) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
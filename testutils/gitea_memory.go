@@ -0,0 +1,135 @@
+package testutils
+
+import (
+	"context"
+
+	"github.com/src-d/metadata-retrieval/gitea/api"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// GiteaMemory implements the gitea storer interface. The resources are
+// copied, so the downloader can reuse its variables
+type GiteaMemory struct {
+	Organization  *api.Organization
+	Users         []api.User
+	Repository    *api.Repository
+	Labels        []api.Label
+	Milestones    []api.Milestone
+	Issues        []api.Issue
+	IssueComments []api.Comment
+	PRs           []api.PullRequest
+	PRComments    []api.Comment
+	PRReviews     []api.PullReview
+}
+
+// SaveOrganization stores an organization in memory,
+// it also initializes the list of users
+func (s *GiteaMemory) SaveOrganization(ctx context.Context, organization *api.Organization) error {
+	log.Infof("organization data fetched for %s\n", organization.Name)
+	o := *organization
+	s.Organization = &o
+	s.Users = make([]api.User, 0)
+	return nil
+}
+
+// SaveUser appends a user to the user list in memory
+func (s *GiteaMemory) SaveUser(ctx context.Context, orgID int64, orgLogin string, user *api.User) error {
+	log.Infof("user data fetched for %s\n", user.Login)
+	s.Users = append(s.Users, *user)
+	return nil
+}
+
+// SaveRepository stores a repository in memory and initializes its
+// resources
+func (s *GiteaMemory) SaveRepository(ctx context.Context, repository *api.Repository) error {
+	log.Infof("repository data fetched for %s\n", repository.FullName)
+	r := *repository
+	s.Repository = &r
+	s.Labels = make([]api.Label, 0)
+	s.Milestones = make([]api.Milestone, 0)
+	s.Issues = make([]api.Issue, 0)
+	s.IssueComments = make([]api.Comment, 0)
+	s.PRs = make([]api.PullRequest, 0)
+	s.PRComments = make([]api.Comment, 0)
+	s.PRReviews = make([]api.PullReview, 0)
+	return nil
+}
+
+// SaveLabel appends a label to the label list in memory
+func (s *GiteaMemory) SaveLabel(ctx context.Context, repositoryOwner, repositoryName string, label *api.Label) error {
+	log.Infof("label data fetched for %s\n", label.Name)
+	s.Labels = append(s.Labels, *label)
+	return nil
+}
+
+// SaveMilestone appends a milestone to the milestone list in memory
+func (s *GiteaMemory) SaveMilestone(ctx context.Context, repositoryOwner, repositoryName string, milestone *api.Milestone) error {
+	log.Infof("milestone data fetched for %s\n", milestone.Title)
+	s.Milestones = append(s.Milestones, *milestone)
+	return nil
+}
+
+// SaveIssue appends an issue to the issue list in memory
+func (s *GiteaMemory) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error {
+	log.Infof("issue data fetched for #%v %s\n", issue.Number, issue.Title)
+	s.Issues = append(s.Issues, *issue)
+	return nil
+}
+
+// SaveIssueComment appends an issue comment to the issue comment list in memory
+func (s *GiteaMemory) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *api.Comment) error {
+	log.Infof("  issue comment data fetched by %s at %v: %q\n", comment.User.Login, comment.CreatedAt, trim(comment.Body))
+	s.IssueComments = append(s.IssueComments, *comment)
+	return nil
+}
+
+// SavePullRequest appends a PR to the PR list in memory
+func (s *GiteaMemory) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *api.PullRequest) error {
+	log.Infof("PR data fetched for #%v %s\n", pr.Number, pr.Title)
+	s.PRs = append(s.PRs, *pr)
+	return nil
+}
+
+// SavePullRequestComment appends a PR comment to the PR comment list in memory
+func (s *GiteaMemory) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *api.Comment) error {
+	log.Infof("  pr comment data fetched by %s at %v: %q\n", comment.User.Login, comment.CreatedAt, trim(comment.Body))
+	s.PRComments = append(s.PRComments, *comment)
+	return nil
+}
+
+// SavePullRequestReview appends a PR review to the PR review list in memory
+func (s *GiteaMemory) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *api.PullReview) error {
+	log.Infof("  PR Review data fetched at %v: %q\n", review.Submitted, trim(review.Body))
+	s.PRReviews = append(s.PRReviews, *review)
+	return nil
+}
+
+// Begin is a noop method at the moment
+func (s *GiteaMemory) Begin() error {
+	return nil
+}
+
+// Commit is a noop method at the moment
+func (s *GiteaMemory) Commit() error {
+	return nil
+}
+
+// Rollback is a noop method at the moment
+func (s *GiteaMemory) Rollback() error {
+	return nil
+}
+
+// Version is a noop method at the moment
+func (s *GiteaMemory) Version(v int) {
+}
+
+// SetActiveVersion is a noop method at the moment
+func (s *GiteaMemory) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+// Cleanup is a noop method at the moment
+func (s *GiteaMemory) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
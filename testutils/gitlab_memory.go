@@ -0,0 +1,117 @@
+package testutils
+
+import (
+	"context"
+
+	"github.com/src-d/metadata-retrieval/gitlab/api"
+
+	"gopkg.in/src-d/go-log.v1"
+)
+
+// GitLabMemory implements the gitlab storer interface. The resources are
+// copied, so the downloader can reuse its variables
+type GitLabMemory struct {
+	Group             *api.Group
+	Users             []api.Member
+	Project           *api.Project
+	Issues            []api.Issue
+	IssueNotes        []api.Note
+	MergeRequests     []api.MergeRequest
+	MergeRequestNotes []api.Note
+	Approvals         []api.User
+}
+
+// SaveGroup stores a group in memory, it also initializes the list of users
+func (s *GitLabMemory) SaveGroup(ctx context.Context, group *api.Group) error {
+	log.Infof("group data fetched for %s\n", group.FullPath)
+	g := *group
+	s.Group = &g
+	s.Users = make([]api.Member, 0)
+	return nil
+}
+
+// SaveUser appends a group member to the user list in memory
+func (s *GitLabMemory) SaveUser(ctx context.Context, groupID int, groupPath string, user *api.Member) error {
+	log.Infof("user data fetched for %s\n", user.Username)
+	s.Users = append(s.Users, *user)
+	return nil
+}
+
+// SaveProject stores a project in memory and initializes its issues, merge
+// requests and notes
+func (s *GitLabMemory) SaveProject(ctx context.Context, project *api.Project) error {
+	log.Infof("project data fetched for %s\n", project.PathWithNamespace)
+	p := *project
+	s.Project = &p
+	s.Issues = make([]api.Issue, 0)
+	s.IssueNotes = make([]api.Note, 0)
+	s.MergeRequests = make([]api.MergeRequest, 0)
+	s.MergeRequestNotes = make([]api.Note, 0)
+	s.Approvals = make([]api.User, 0)
+	return nil
+}
+
+// SaveIssue appends an issue to the issue list in memory
+func (s *GitLabMemory) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *api.Issue) error {
+	log.Infof("issue data fetched for #%v %s\n", issue.IID, issue.Title)
+	s.Issues = append(s.Issues, *issue)
+	return nil
+}
+
+// SaveIssueNote appends an issue note to the issue note list in memory
+func (s *GitLabMemory) SaveIssueNote(ctx context.Context, repositoryOwner, repositoryName string, issueIID int, note *api.Note) error {
+	log.Infof("  issue note data fetched by %s at %v: %q\n", note.Author.Username, note.CreatedAt, trim(note.Body))
+	s.IssueNotes = append(s.IssueNotes, *note)
+	return nil
+}
+
+// SaveMergeRequest appends a merge request to the merge request list in memory
+func (s *GitLabMemory) SaveMergeRequest(ctx context.Context, repositoryOwner, repositoryName string, mr *api.MergeRequest) error {
+	log.Infof("MR data fetched for !%v %s\n", mr.IID, mr.Title)
+	s.MergeRequests = append(s.MergeRequests, *mr)
+	return nil
+}
+
+// SaveMergeRequestNote appends a merge request note to the merge request note
+// list in memory
+func (s *GitLabMemory) SaveMergeRequestNote(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, note *api.Note) error {
+	log.Infof("  MR note data fetched by %s at %v: %q\n", note.Author.Username, note.CreatedAt, trim(note.Body))
+	s.MergeRequestNotes = append(s.MergeRequestNotes, *note)
+	return nil
+}
+
+// SaveMergeRequestApproval appends an approver to the approval list in memory
+func (s *GitLabMemory) SaveMergeRequestApproval(ctx context.Context, repositoryOwner, repositoryName string, mergeRequestIID int, user *api.User) error {
+	log.Infof("  MR approval data fetched for %s\n", user.Username)
+	s.Approvals = append(s.Approvals, *user)
+	return nil
+}
+
+// Begin is a noop method at the moment
+func (s *GitLabMemory) Begin() error {
+	return nil
+}
+
+// Commit is a noop method at the moment
+func (s *GitLabMemory) Commit() error {
+	return nil
+}
+
+// Rollback is a noop method at the moment
+func (s *GitLabMemory) Rollback() error {
+	return nil
+}
+
+// Version is a noop method at the moment
+func (s *GitLabMemory) Version(v int) {
+}
+
+// SetActiveVersion is a noop method at the moment
+func (s *GitLabMemory) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+// Cleanup is a noop method at the moment
+func (s *GitLabMemory) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}
@@ -2,15 +2,20 @@ package testutils
 
 import (
 	"fmt"
+	"sync"
 
 	"gopkg.in/src-d/go-log.v1"
 )
 
 type LoggerMock struct {
+	mu  sync.Mutex
 	out []string
 }
 
 func (l *LoggerMock) Next() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	if len(l.out) == 0 {
 		return ""
 	}
@@ -20,25 +25,32 @@ func (l *LoggerMock) Next() string {
 	return first
 }
 
+func (l *LoggerMock) append(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.out = append(l.out, line)
+}
+
 func (l *LoggerMock) Debugf(format string, args ...interface{}) {
-	l.out = append(l.out, fmt.Sprintf(format, args...))
+	l.append(fmt.Sprintf(format, args...))
 	log.Debugf(format, args...)
 }
 
 func (l *LoggerMock) Errorf(err error, format string, args ...interface{}) {
 	arguments := append([]interface{}{err}, args)
 	errorFormat := fmt.Sprintf("Error %s; %s", err, format)
-	l.out = append(l.out, fmt.Sprintf(errorFormat, arguments...))
+	l.append(fmt.Sprintf(errorFormat, arguments...))
 	log.Errorf(err, format, args...)
 }
 
 func (l *LoggerMock) Infof(format string, args ...interface{}) {
-	l.out = append(l.out, fmt.Sprintf(format, args...))
+	l.append(fmt.Sprintf(format, args...))
 	log.Infof(format, args...)
 }
 
 func (l *LoggerMock) Warningf(format string, args ...interface{}) {
-	l.out = append(l.out, fmt.Sprintf(format, args...))
+	l.append(fmt.Sprintf(format, args...))
 	log.Warningf(format, args...)
 }
 
@@ -20,6 +20,13 @@ type Memory struct {
 	PRComments       []*graphql.IssueComment
 	PRReviews        []*graphql.PullRequestReview
 	PRReviewComments []*graphql.PullRequestReviewComment
+	Projects         []*graphql.Project
+	ProjectColumns   []*graphql.ProjectColumn
+	ProjectCards     []*graphql.ProjectCard
+	ProjectsV2       []*graphql.ProjectV2
+	ProjectV2Items   []*graphql.ProjectV2Item
+	CommitComments   []*graphql.CommitComment
+	Contributors     []*graphql.UserExtended
 }
 
 // SaveOrganization stores an organization in memory,
@@ -29,6 +36,7 @@ func (s *Memory) SaveOrganization(ctx context.Context, organization *graphql.Org
 	s.Organization = organization
 	// Initialize users to 0 for each repo
 	s.Users = make([]*graphql.UserExtended, 0)
+	s.initProjects()
 	return nil
 }
 
@@ -39,6 +47,13 @@ func (s *Memory) SaveUser(ctx context.Context, orgID int, orgLogin string, user
 	return nil
 }
 
+// SaveContributor appends a user to the contributor list in memory
+func (s *Memory) SaveContributor(ctx context.Context, user *graphql.UserExtended) error {
+	log.Infof("contributor data fetched for %s\n", user.Login)
+	s.Contributors = append(s.Contributors, user)
+	return nil
+}
+
 // SaveRepository stores a repository and its topics in memory and
 // initializes PRs and PR comments
 func (s *Memory) SaveRepository(ctx context.Context, repository *graphql.RepositoryFields, topics []string) error {
@@ -52,9 +67,20 @@ func (s *Memory) SaveRepository(ctx context.Context, repository *graphql.Reposit
 	s.PRComments = make([]*graphql.IssueComment, 0)
 	s.PRReviews = make([]*graphql.PullRequestReview, 0)
 	s.PRReviewComments = make([]*graphql.PullRequestReviewComment, 0)
+	s.CommitComments = make([]*graphql.CommitComment, 0)
+	s.Contributors = make([]*graphql.UserExtended, 0)
+	s.initProjects()
 	return nil
 }
 
+func (s *Memory) initProjects() {
+	s.Projects = make([]*graphql.Project, 0)
+	s.ProjectColumns = make([]*graphql.ProjectColumn, 0)
+	s.ProjectCards = make([]*graphql.ProjectCard, 0)
+	s.ProjectsV2 = make([]*graphql.ProjectV2, 0)
+	s.ProjectV2Items = make([]*graphql.ProjectV2Item, 0)
+}
+
 // SaveIssue appends an issue to the issue list in memory
 func (s *Memory) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *graphql.Issue, assignees []string, labels []string) error {
 	log.Infof("issue data fetched for #%v %s\n", issue.Number, issue.Title)
@@ -97,6 +123,48 @@ func (s *Memory) SavePullRequestReviewComment(ctx context.Context, repositoryOwn
 	return nil
 }
 
+// SaveCommitComment appends a commit comment to the commit comments list in memory
+func (s *Memory) SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error {
+	log.Infof("commit comment data fetched by %s at %v: %q\n", comment.Author.Login, comment.CreatedAt, trim(comment.Body))
+	s.CommitComments = append(s.CommitComments, comment)
+	return nil
+}
+
+// SaveProject appends a classic project to the project list in memory
+func (s *Memory) SaveProject(ctx context.Context, owner, repositoryName string, project *graphql.Project) error {
+	log.Infof("project data fetched for #%v %s\n", project.Number, project.Name)
+	s.Projects = append(s.Projects, project)
+	return nil
+}
+
+// SaveProjectColumn appends a project column to the project column list in memory
+func (s *Memory) SaveProjectColumn(ctx context.Context, projectID string, column *graphql.ProjectColumn) error {
+	log.Infof("\tproject column data fetched for %s\n", column.Name)
+	s.ProjectColumns = append(s.ProjectColumns, column)
+	return nil
+}
+
+// SaveProjectCard appends a project card to the project card list in memory
+func (s *Memory) SaveProjectCard(ctx context.Context, projectID string, columnID string, card *graphql.ProjectCard) error {
+	log.Infof("\t\tproject card data fetched by %s at %v: %q\n", card.Creator.Login, card.CreatedAt, trim(card.Note))
+	s.ProjectCards = append(s.ProjectCards, card)
+	return nil
+}
+
+// SaveProjectV2 appends a project (v2) to the project v2 list in memory
+func (s *Memory) SaveProjectV2(ctx context.Context, owner, repositoryName string, project *graphql.ProjectV2) error {
+	log.Infof("project v2 data fetched for #%v %s\n", project.Number, project.Title)
+	s.ProjectsV2 = append(s.ProjectsV2, project)
+	return nil
+}
+
+// SaveProjectV2Item appends a project (v2) item to the project v2 item list in memory
+func (s *Memory) SaveProjectV2Item(ctx context.Context, projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) error {
+	log.Infof("\tproject v2 item data fetched for %s %s\n", item.Type, item.ID)
+	s.ProjectV2Items = append(s.ProjectV2Items, item)
+	return nil
+}
+
 // Begin is a noop method at the moment
 func (s *Memory) Begin() error {
 	return nil
@@ -0,0 +1,75 @@
+package testutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// RoundTripFunc is an http.RoundTripper that returns the response of the
+// function, without error
+type RoundTripFunc func(req *http.Request) *http.Response
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+// RecordedResponse is a response of a provider API recorded in a JSON file
+type RecordedResponse struct {
+	Status  int
+	Headers map[string]string
+	Body    json.RawMessage
+}
+
+// Replayer is an http.RoundTripper that replays the recorded responses of a
+// provider API, indexed by the request URI relative to the API base path
+type Replayer struct {
+	basePath  string
+	missing   RecordedResponse
+	responses map[string]RecordedResponse
+}
+
+// NewReplayer returns a Replayer of the responses recorded in the given JSON
+// file. The requests that were not recorded get the missing response
+func NewReplayer(path string, basePath string, missing RecordedResponse) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	responses := make(map[string]RecordedResponse)
+	if err := json.NewDecoder(f).Decode(&responses); err != nil {
+		return nil, fmt.Errorf("failed to read the recordings %s: %v", path, err)
+	}
+
+	return &Replayer{basePath: basePath, missing: missing, responses: responses}, nil
+}
+
+// Response returns the recorded response of the request
+func (r *Replayer) Response(req *http.Request) *http.Response {
+	rec, ok := r.responses[strings.TrimPrefix(req.URL.RequestURI(), r.basePath)]
+	if !ok {
+		rec = r.missing
+	}
+
+	header := make(http.Header)
+	for k, v := range rec.Headers {
+		header.Set(k, v)
+	}
+
+	return &http.Response{
+		StatusCode: rec.Status,
+		Status:     fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		Body:       ioutil.NopCloser(bytes.NewReader(rec.Body)),
+		Header:     header,
+	}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.Response(req), nil
+}
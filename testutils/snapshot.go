@@ -0,0 +1,153 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/src-d/metadata-retrieval/github/graphql"
+)
+
+// Snapshot implements the storer interface keeping a JSON line for each saved
+// item, with the arguments it was saved with, to compare the data saved by
+// different downloads. The items are serialized when they are saved, so the
+// downloader can reuse its variables. It is safe for concurrent use
+type Snapshot struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// Lines returns the saved items, sorted so the downloads of several
+// repositories in parallel can be compared
+func (s *Snapshot) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := append([]string{}, s.lines...)
+	sort.Strings(lines)
+	return lines
+}
+
+func (s *Snapshot) save(kind string, args ...interface{}) error {
+	content, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("could not serialize the %s: %v", kind, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lines = append(s.lines, kind+" "+string(content))
+	return nil
+}
+
+// SaveOrganization keeps the organization
+func (s *Snapshot) SaveOrganization(ctx context.Context, organization *graphql.Organization) error {
+	return s.save("organization", organization)
+}
+
+// SaveUser keeps the user
+func (s *Snapshot) SaveUser(ctx context.Context, orgID int, orgLogin string, user *graphql.UserExtended) error {
+	return s.save("user", orgID, orgLogin, user)
+}
+
+// SaveContributor keeps the contributor
+func (s *Snapshot) SaveContributor(ctx context.Context, user *graphql.UserExtended) error {
+	return s.save("contributor", user)
+}
+
+// SaveRepository keeps the repository and its topics
+func (s *Snapshot) SaveRepository(ctx context.Context, repository *graphql.RepositoryFields, topics []string) error {
+	return s.save("repository", repository, topics)
+}
+
+// SaveIssue keeps the issue with its assignees and labels
+func (s *Snapshot) SaveIssue(ctx context.Context, repositoryOwner, repositoryName string, issue *graphql.Issue, assignees []string, labels []string) error {
+	return s.save("issue", repositoryOwner, repositoryName, issue, assignees, labels)
+}
+
+// SaveIssueComment keeps the issue comment
+func (s *Snapshot) SaveIssueComment(ctx context.Context, repositoryOwner, repositoryName string, issueNumber int, comment *graphql.IssueComment) error {
+	return s.save("issue_comment", repositoryOwner, repositoryName, issueNumber, comment)
+}
+
+// SavePullRequest keeps the PR with its assignees and labels
+func (s *Snapshot) SavePullRequest(ctx context.Context, repositoryOwner, repositoryName string, pr *graphql.PullRequest, assignees []string, labels []string) error {
+	return s.save("pull_request", repositoryOwner, repositoryName, pr, assignees, labels)
+}
+
+// SavePullRequestComment keeps the PR comment
+func (s *Snapshot) SavePullRequestComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, comment *graphql.IssueComment) error {
+	return s.save("pull_request_comment", repositoryOwner, repositoryName, pullRequestNumber, comment)
+}
+
+// SavePullRequestReview keeps the PR review
+func (s *Snapshot) SavePullRequestReview(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, review *graphql.PullRequestReview) error {
+	return s.save("pull_request_review", repositoryOwner, repositoryName, pullRequestNumber, review)
+}
+
+// SavePullRequestReviewComment keeps the PR review comment
+func (s *Snapshot) SavePullRequestReviewComment(ctx context.Context, repositoryOwner, repositoryName string, pullRequestNumber int, pullRequestReviewID int, comment *graphql.PullRequestReviewComment) error {
+	return s.save("pull_request_review_comment", repositoryOwner, repositoryName, pullRequestNumber, pullRequestReviewID, comment)
+}
+
+// SaveCommitComment keeps the commit comment
+func (s *Snapshot) SaveCommitComment(ctx context.Context, repositoryOwner, repositoryName string, comment *graphql.CommitComment) error {
+	return s.save("commit_comment", repositoryOwner, repositoryName, comment)
+}
+
+// SaveProject keeps the classic project
+func (s *Snapshot) SaveProject(ctx context.Context, owner, repositoryName string, project *graphql.Project) error {
+	return s.save("project", owner, repositoryName, project)
+}
+
+// SaveProjectColumn keeps the project column
+func (s *Snapshot) SaveProjectColumn(ctx context.Context, projectID string, column *graphql.ProjectColumn) error {
+	return s.save("project_column", projectID, column)
+}
+
+// SaveProjectCard keeps the project card
+func (s *Snapshot) SaveProjectCard(ctx context.Context, projectID string, columnID string, card *graphql.ProjectCard) error {
+	return s.save("project_card", projectID, columnID, card)
+}
+
+// SaveProjectV2 keeps the project (v2)
+func (s *Snapshot) SaveProjectV2(ctx context.Context, owner, repositoryName string, project *graphql.ProjectV2) error {
+	return s.save("project_v2", owner, repositoryName, project)
+}
+
+// SaveProjectV2Item keeps the project (v2) item with its field values
+func (s *Snapshot) SaveProjectV2Item(ctx context.Context, projectID string, item *graphql.ProjectV2Item, fieldValues map[string]string) error {
+	return s.save("project_v2_item", projectID, item, fieldValues)
+}
+
+// Begin is a noop method
+func (s *Snapshot) Begin() error {
+	return nil
+}
+
+// Commit is a noop method
+func (s *Snapshot) Commit() error {
+	return nil
+}
+
+// Rollback is a noop method
+func (s *Snapshot) Rollback() error {
+	return nil
+}
+
+// Version is a noop method
+func (s *Snapshot) Version(v int) {
+}
+
+// SetActiveVersion is a noop method
+func (s *Snapshot) SetActiveVersion(ctx context.Context, v int) error {
+	return nil
+}
+
+// Cleanup is a noop method
+func (s *Snapshot) Cleanup(ctx context.Context, currentVersion int) error {
+	return nil
+}